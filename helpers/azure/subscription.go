@@ -0,0 +1,18 @@
+package azure
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/validation"
+)
+
+// SchemaSubscriptionIdOverride returns the Schema used for the `subscription_id` field on Resources which
+// can be provisioned within a different Subscription to the one configured in the Provider block
+func SchemaSubscriptionIdOverride() *pluginsdk.Schema {
+	return &pluginsdk.Schema{
+		Type:         pluginsdk.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.IsUUID,
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance/recording"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/features"
 )
 
@@ -54,6 +55,12 @@ type TestData struct {
 
 	// resourceLabel is the local used for the resource - generally "test""
 	resourceLabel string
+
+	// recorder records/replays the HTTP Interactions for this Test, when configured
+	recorder *recording.Recorder
+
+	// randomness is used to generate random values which are stable when recording/replaying
+	randomness *rand.Rand
 }
 
 // BuildTestData generates some test data for the given resource
@@ -85,6 +92,10 @@ func BuildTestData(t *testing.T, resourceType string, resourceLabel string) Test
 		}
 	}
 
+	if err := testData.configureRecording(t); err != nil {
+		t.Fatalf("Error configuring recording: %+v", err)
+	}
+
 	return testData
 }
 
@@ -123,6 +134,10 @@ func (td *TestData) RandomStringOfLength(len int) string {
 		panic("Invalid Test: RandomStringOfLength: length argument must be between 1 and 1024 characters")
 	}
 
+	if td.randomness != nil {
+		return randStringFromSource(td.randomness, len, charSetAlphaNum)
+	}
+
 	return randString(len)
 }
 
@@ -140,3 +155,13 @@ func randStringFromCharSet(strlen int, charSet string) string {
 	}
 	return string(result)
 }
+
+// randStringFromSource generates a random string by selecting characters from
+// the charset provided, using the specified source of randomness
+func randStringFromSource(source *rand.Rand, strlen int, charSet string) string {
+	result := make([]byte, strlen)
+	for i := 0; i < strlen; i++ {
+		result[i] = charSet[source.Intn(len(charSet))]
+	}
+	return string(result)
+}
@@ -0,0 +1,95 @@
+package acceptance
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance/recording"
+)
+
+// replayEnvironmentVariables are the Environment Variables required to run a Test, which are defaulted
+// to placeholder values when replaying a Test, since no requests are sent to Azure
+var replayEnvironmentVariables = map[string]string{
+	"ARM_CLIENT_ID":       recording.ClientIdPlaceholder,
+	"ARM_CLIENT_SECRET":   "replay",
+	"ARM_SUBSCRIPTION_ID": recording.SubscriptionIdPlaceholder,
+	"ARM_TENANT_ID":       recording.TenantIdPlaceholder,
+}
+
+// configureRecording configures this Test to record or replay the HTTP Interactions with Azure
+// when the `ARM_TEST_RECORDING_MODE` Environment Variable is set to `record` or `replay`.
+//
+// NOTE: only requests sent via the AzureRM Provider and the Test Client are recorded, as such Tests
+// which use other Providers (e.g. `azuread`) or the `object_id` from `azurerm_client_config` can't be replayed.
+func (td *TestData) configureRecording(t *testing.T) error {
+	mode, err := recording.CurrentMode()
+	if err != nil {
+		return err
+	}
+	if mode == recording.ModeLive {
+		return nil
+	}
+
+	if mode == recording.ModeReplay {
+		for key, value := range replayEnvironmentVariables {
+			if os.Getenv(key) == "" {
+				os.Setenv(key, value)
+			}
+		}
+	}
+
+	scrubber := recording.NewScrubber(os.Getenv("ARM_SUBSCRIPTION_ID"), os.Getenv("ARM_TENANT_ID"), os.Getenv("ARM_CLIENT_ID"))
+	recorder, err := recording.NewRecorder(mode, t.Name(), scrubber)
+	if err != nil {
+		return fmt.Errorf("building Recorder: %+v", err)
+	}
+
+	cassette := recorder.Cassette()
+	if mode == recording.ModeReplay {
+		// the same values need to be used as when the Test was recorded, so that the same requests are sent
+		if len(cassette.Locations) != 3 {
+			return fmt.Errorf("expected the Cassette for %q to contain 3 Locations but got %d", t.Name(), len(cassette.Locations))
+		}
+		td.RandomInteger = cassette.RandomInteger
+		td.RandomString = cassette.RandomString
+		td.Locations = Regions{
+			Primary:   cassette.Locations[0],
+			Secondary: cassette.Locations[1],
+			Ternary:   cassette.Locations[2],
+		}
+
+		for key, value := range map[string]string{
+			"ARM_TEST_LOCATION":      td.Locations.Primary,
+			"ARM_TEST_LOCATION_ALT":  td.Locations.Secondary,
+			"ARM_TEST_LOCATION_ALT2": td.Locations.Ternary,
+		} {
+			if os.Getenv(key) == "" {
+				os.Setenv(key, value)
+			}
+		}
+	} else {
+		cassette.RandomInteger = td.RandomInteger
+		cassette.RandomString = td.RandomString
+		cassette.Locations = []string{td.Locations.Primary, td.Locations.Secondary, td.Locations.Ternary}
+
+		t.Cleanup(func() {
+			if t.Failed() {
+				log.Printf("[DEBUG] Not saving the Cassette for %q since the Test failed", t.Name())
+				return
+			}
+
+			if err := recorder.Save(); err != nil {
+				t.Errorf("saving the Cassette for %q: %+v", t.Name(), err)
+			}
+		})
+	}
+
+	// RandomStringOfLength needs to return the same values when replaying as when recording
+	td.randomness = rand.New(rand.NewSource(int64(td.RandomInteger)))
+	td.recorder = recorder
+
+	return nil
+}
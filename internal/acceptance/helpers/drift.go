@@ -0,0 +1,181 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance/types"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+// ComputedValue is the value used for an attribute which the plan shows as known after apply
+const ComputedValue = "(known after apply)"
+
+// DriftResourceFunc returns a TestCheckFunc which changes the resource within Azure (outside of Terraform)
+// and then confirms that the next plan contains exactly the expected changes
+func DriftResourceFunc(client *clients.Client, testResource types.TestResourceVerifyingDrift, resource *pluginsdk.Resource, resourceName string, expectedChanges map[string]string) func(state *terraform.State) error {
+	return func(state *terraform.State) error {
+		ctx := client.StopContext
+
+		rs, ok := state.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("%q was not found in the state", resourceName)
+		}
+
+		result, err := testResource.Drift(ctx, client, rs.Primary)
+		if err != nil {
+			return fmt.Errorf("running drift func for %q: %+v", resourceName, err)
+		}
+		if result == nil {
+			return fmt.Errorf("received nil for drift result for %q", resourceName)
+		}
+		if !*result {
+			return fmt.Errorf("error drifting %q but no error", resourceName)
+		}
+
+		refreshed, diags := resource.RefreshWithoutUpgrade(ctx, rs.Primary, client)
+		if diags.HasError() {
+			return fmt.Errorf("refreshing %q: %+v", resourceName, diags)
+		}
+		if refreshed == nil {
+			return fmt.Errorf("%q was removed when refreshing, rather than being changed", resourceName)
+		}
+
+		actualChanges, err := planChanges(ctx, resource, rs.Primary, refreshed, client)
+		if err != nil {
+			return fmt.Errorf("planning %q: %+v", resourceName, err)
+		}
+		if err := compareChanges(expectedChanges, actualChanges); err != nil {
+			return fmt.Errorf("planning %q: %+v", resourceName, err)
+		}
+
+		return nil
+	}
+}
+
+// planChanges returns the changes within the plan for the refreshed State of the Resource.
+//
+// The Configuration isn't available to a TestCheckFunc - however the State following an apply contains
+// the configured values, so (once the Computed-only attributes are removed) is used in its place.
+func planChanges(ctx context.Context, resource *pluginsdk.Resource, applied, refreshed *terraform.InstanceState, meta interface{}) (map[string]string, error) {
+	schema := resource.CoreConfigSchema()
+	configValue, err := applied.AttrsAsObjectValue(schema.ImpliedType())
+	if err != nil {
+		return nil, fmt.Errorf("building the configuration: %+v", err)
+	}
+	config := terraform.NewResourceConfigShimmed(withoutComputedAttributes(resource.Schema, configValue), schema)
+
+	plan, err := resource.Diff(ctx, refreshed, config, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return PlannedChanges(plan), nil
+}
+
+// PlannedChanges returns a map of the (flatmapped) attributes which are changed by the plan to their
+// planned value - where an attribute is being removed this is an empty string, and where the value
+// is known after apply this is ComputedValue.
+//
+// The number of items within a List/Map/Set (e.g. `tags.%`) are ignored, since these are implied
+// by the items which are changing.
+func PlannedChanges(plan *terraform.InstanceDiff) map[string]string {
+	changes := make(map[string]string)
+	if plan == nil {
+		return changes
+	}
+
+	for k, v := range plan.Attributes {
+		if v == nil || strings.HasSuffix(k, ".%") || strings.HasSuffix(k, ".#") {
+			continue
+		}
+
+		switch {
+		case v.NewRemoved:
+			changes[k] = ""
+		case v.NewComputed:
+			changes[k] = ComputedValue
+		case v.Old != v.New:
+			changes[k] = v.New
+		}
+	}
+
+	return changes
+}
+
+// withoutComputedAttributes returns the specified value with any Computed-only attributes (including those
+// within nested blocks) set to null, since these can't be specified in the Configuration
+func withoutComputedAttributes(schema map[string]*pluginsdk.Schema, input cty.Value) cty.Value {
+	if input.IsNull() || !input.IsKnown() || !input.Type().IsObjectType() {
+		return input
+	}
+
+	attributes := input.AsValueMap()
+	for k, v := range attributes {
+		s, ok := schema[k]
+		if !ok {
+			continue
+		}
+
+		if s.Computed && !s.Optional {
+			attributes[k] = cty.NullVal(v.Type())
+			continue
+		}
+
+		nested, ok := s.Elem.(*pluginsdk.Resource)
+		if !ok || v.IsNull() || !v.IsKnown() || v.LengthInt() == 0 {
+			continue
+		}
+
+		items := make([]cty.Value, 0)
+		for it := v.ElementIterator(); it.Next(); {
+			_, item := it.Element()
+			items = append(items, withoutComputedAttributes(nested.Schema, item))
+		}
+		switch {
+		case v.Type().IsListType():
+			attributes[k] = cty.ListVal(items)
+		case v.Type().IsSetType():
+			attributes[k] = cty.SetVal(items)
+		}
+	}
+
+	if len(attributes) == 0 {
+		return input
+	}
+
+	return cty.ObjectVal(attributes)
+}
+
+func compareChanges(expected, actual map[string]string) error {
+	issues := make([]string, 0)
+	for k, v := range expected {
+		actualValue, ok := actual[k]
+		if !ok {
+			issues = append(issues, fmt.Sprintf("expected the plan to change `%s` to %q but it wasn't changed", k, v))
+			continue
+		}
+
+		if actualValue != v {
+			issues = append(issues, fmt.Sprintf("expected the plan to change `%s` to %q but got %q", k, v, actualValue))
+		}
+	}
+
+	for k, v := range actual {
+		if _, ok := expected[k]; !ok {
+			issues = append(issues, fmt.Sprintf("the plan unexpectedly changed `%s` to %q", k, v))
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil
+	}
+
+	sort.Strings(issues)
+	return fmt.Errorf("the planned changes didn't match those expected:\n\n%s", strings.Join(issues, "\n"))
+}
@@ -0,0 +1,259 @@
+package helpers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tags"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+	"github.com/hashicorp/terraform-provider-azurerm/utils"
+)
+
+func TestPlannedChanges(t *testing.T) {
+	testData := []struct {
+		Name     string
+		Input    *terraform.InstanceDiff
+		Expected map[string]string
+	}{
+		{
+			Name:     "No Plan",
+			Input:    nil,
+			Expected: map[string]string{},
+		},
+		{
+			Name: "Unchanged Value",
+			Input: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"sku_name": {Old: "Basic", New: "Basic"},
+				},
+			},
+			Expected: map[string]string{},
+		},
+		{
+			Name: "Changed Value",
+			Input: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"sku_name": {Old: "Standard", New: "Basic"},
+				},
+			},
+			Expected: map[string]string{
+				"sku_name": "Basic",
+			},
+		},
+		{
+			Name: "Added and Removed Tags",
+			Input: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"tags.%":           {Old: "1", New: "1"},
+					"tags.drift":       {Old: "true", New: "", NewRemoved: true},
+					"tags.environment": {Old: "", New: "Production"},
+				},
+			},
+			Expected: map[string]string{
+				"tags.drift":       "",
+				"tags.environment": "Production",
+			},
+		},
+		{
+			Name: "Computed Value",
+			Input: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"sku.#":          {Old: "1", New: "1"},
+					"sku.0.capacity": {Old: "2", New: "1"},
+					"fqdn":           {Old: "example.com", NewComputed: true},
+				},
+			},
+			Expected: map[string]string{
+				"fqdn":           ComputedValue,
+				"sku.0.capacity": "1",
+			},
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		actual := PlannedChanges(v.Input)
+		if !reflect.DeepEqual(actual, v.Expected) {
+			t.Fatalf("expected %+v but got %+v", v.Expected, actual)
+		}
+	}
+}
+
+// testDriftedResource is a fake Resource whose remote values have been changed outside of Terraform
+type testDriftedResource struct {
+	remoteTags     map[string]*string
+	remoteCapacity int
+}
+
+func (r *testDriftedResource) resource() *pluginsdk.Resource {
+	resource := &pluginsdk.Resource{
+		Read: func(d *pluginsdk.ResourceData, _ interface{}) error {
+			d.Set("fqdn", "example.com")
+			d.Set("sku", []interface{}{
+				map[string]interface{}{
+					"capacity": r.remoteCapacity,
+					"tier":     "Standard",
+				},
+			})
+			return tags.FlattenAndSet(d, r.remoteTags)
+		},
+		Schema: map[string]*pluginsdk.Schema{
+			"fqdn": {
+				Type:     pluginsdk.TypeString,
+				Computed: true,
+			},
+			"sku": {
+				Type:     pluginsdk.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &pluginsdk.Resource{
+					Schema: map[string]*pluginsdk.Schema{
+						"capacity": {
+							Type:     pluginsdk.TypeInt,
+							Optional: true,
+						},
+						"tier": {
+							Type:     pluginsdk.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"tags": tags.Schema(),
+		},
+	}
+	tags.AddDefaultsSupport(resource, func(_ interface{}) map[string]string {
+		return nil
+	}, func(_ interface{}) tags.IgnoreTags {
+		return tags.IgnoreTags{}
+	})
+	return resource
+}
+
+func TestPlanChanges(t *testing.T) {
+	applied := &terraform.InstanceState{
+		ID: "example",
+		Attributes: map[string]string{
+			"id":                   "example",
+			"fqdn":                 "example.com",
+			"sku.#":                "1",
+			"sku.0.capacity":       "1",
+			"sku.0.tier":           "Standard",
+			"tags.%":               "2",
+			"tags.cost_center":     "MSFT",
+			"tags.environment":     "Production",
+			"tags_all.%":           "2",
+			"tags_all.cost_center": "MSFT",
+			"tags_all.environment": "Production",
+		},
+	}
+
+	testData := []struct {
+		Name     string
+		Resource *testDriftedResource
+		Expected map[string]string
+	}{
+		{
+			Name: "No Drift",
+			Resource: &testDriftedResource{
+				remoteTags: map[string]*string{
+					"cost_center": utils.String("MSFT"),
+					"environment": utils.String("Production"),
+				},
+				remoteCapacity: 1,
+			},
+			Expected: map[string]string{},
+		},
+		{
+			Name: "Drifted Tag",
+			Resource: &testDriftedResource{
+				remoteTags: map[string]*string{
+					"cost_center": utils.String("MSFT"),
+					"environment": utils.String("Drifted"),
+				},
+				remoteCapacity: 1,
+			},
+			Expected: map[string]string{
+				"tags.environment":     "Production",
+				"tags_all.environment": "Production",
+			},
+		},
+		{
+			Name: "Added Tag",
+			Resource: &testDriftedResource{
+				remoteTags: map[string]*string{
+					"cost_center": utils.String("MSFT"),
+					"drift":       utils.String("true"),
+					"environment": utils.String("Production"),
+				},
+				remoteCapacity: 1,
+			},
+			Expected: map[string]string{
+				"tags.drift":     "",
+				"tags_all.drift": "",
+			},
+		},
+		{
+			Name: "Drifted Nested Block",
+			Resource: &testDriftedResource{
+				remoteTags: map[string]*string{
+					"cost_center": utils.String("MSFT"),
+					"environment": utils.String("Production"),
+				},
+				remoteCapacity: 2,
+			},
+			Expected: map[string]string{
+				"sku.0.capacity": "1",
+			},
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		resource := v.Resource.resource()
+		refreshed, diags := resource.RefreshWithoutUpgrade(context.TODO(), applied, nil)
+		if diags.HasError() {
+			t.Fatalf("refreshing: %+v", diags)
+		}
+
+		actual, err := planChanges(context.TODO(), resource, applied, refreshed, nil)
+		if err != nil {
+			t.Fatalf("planning: %+v", err)
+		}
+		if !reflect.DeepEqual(actual, v.Expected) {
+			t.Fatalf("expected %+v but got %+v", v.Expected, actual)
+		}
+	}
+}
+
+func TestCompareChanges(t *testing.T) {
+	expected := map[string]string{
+		"sku_name":   "Standard",
+		"tags.drift": "true",
+	}
+
+	if err := compareChanges(expected, map[string]string{"sku_name": "Standard", "tags.drift": "true"}); err != nil {
+		t.Fatalf("expected no error but got: %+v", err)
+	}
+
+	actual := map[string]string{
+		"sku_name":    "Premium",
+		"description": "changed",
+	}
+	err := compareChanges(expected, actual)
+	if err == nil {
+		t.Fatalf("expected an error but didn't get one")
+	}
+
+	expectedError := "the planned changes didn't match those expected:\n\n" +
+		"expected the plan to change `sku_name` to \"Standard\" but got \"Premium\"\n" +
+		"expected the plan to change `tags.drift` to \"true\" but it wasn't changed\n" +
+		"the plan unexpectedly changed `description` to \"changed\""
+	if err.Error() != expectedError {
+		t.Fatalf("expected the error:\n%s\n\nbut got:\n%s", expectedError, err.Error())
+	}
+}
@@ -0,0 +1,88 @@
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Cassette is a set of HTTP Interactions recorded for a single Test, alongside the
+// randomly generated values used in that Test - which need to be re-used when the
+// Cassette is replayed so that the same requests are sent
+type Cassette struct {
+	// Name is the name of the Test which this Cassette was recorded for
+	Name string `json:"name"`
+
+	// RandomInteger and RandomString are the values used for this Test (see acceptance.TestData)
+	RandomInteger int    `json:"randomInteger"`
+	RandomString  string `json:"randomString"`
+
+	// Locations are the Azure Regions used for this Test, in order (Primary, Secondary and Ternary)
+	Locations []string `json:"locations"`
+
+	// Interactions are the HTTP Requests sent and the Responses received, in the order they were sent
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int                 `json:"statusCode"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Body       string              `json:"body,omitempty"`
+}
+
+// cassetteFileNameRegex matches the characters which aren't valid in a file name, which Sub-Tests contain
+var cassetteFileNameRegex = regexp.MustCompile(`[^a-zA-Z0-9_\-.]`)
+
+// CassettePath returns the path to the Cassette file for the specified Test, which is relative
+// to the directory containing the Test (e.g. the Service Package)
+func CassettePath(testName string) string {
+	fileName := cassetteFileNameRegex.ReplaceAllString(testName, "_")
+	return filepath.Join("testdata", "recordings", fmt.Sprintf("%s.json", fileName))
+}
+
+// LoadCassette loads the Cassette from the specified path
+func LoadCassette(path string) (*Cassette, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading Cassette %q: %+v", path, err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(contents, &cassette); err != nil {
+		return nil, fmt.Errorf("parsing Cassette %q: %+v", path, err)
+	}
+
+	return &cassette, nil
+}
+
+// Save writes the Cassette to the specified path, creating the directory if necessary
+func (c Cassette) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory for Cassette %q: %+v", path, err)
+	}
+
+	contents, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serializing Cassette %q: %+v", path, err)
+	}
+
+	if err := ioutil.WriteFile(path, contents, 0644); err != nil {
+		return fmt.Errorf("writing Cassette %q: %+v", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package recording
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Mode determines whether HTTP Interactions are sent to Azure, recorded or replayed
+type Mode string
+
+const (
+	// ModeLive sends all requests to Azure without recording them, which is the default
+	ModeLive Mode = "live"
+
+	// ModeRecord sends all requests to Azure, recording them into a Cassette for each Test
+	ModeRecord Mode = "record"
+
+	// ModeReplay serves all requests from the Cassette for each Test, without sending them to Azure
+	ModeReplay Mode = "replay"
+)
+
+// ModeEnvVar is the Environment Variable used to configure the Mode
+const ModeEnvVar = "ARM_TEST_RECORDING_MODE"
+
+// CurrentMode returns the Mode configured via the `ARM_TEST_RECORDING_MODE` Environment Variable
+func CurrentMode() (Mode, error) {
+	v := strings.ToLower(os.Getenv(ModeEnvVar))
+	switch Mode(v) {
+	case "", ModeLive:
+		return ModeLive, nil
+
+	case ModeRecord, ModeReplay:
+		return Mode(v), nil
+	}
+
+	return "", fmt.Errorf("unsupported value %q for `%s` - supported values are %q, %q and %q", v, ModeEnvVar, ModeLive, ModeRecord, ModeReplay)
+}
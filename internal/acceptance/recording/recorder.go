@@ -0,0 +1,251 @@
+package recording
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// Recorder records the HTTP Interactions for a single Test into a Cassette, or replays
+// them from a Cassette - and is hooked into the HTTP pipeline via SendDecorator
+type Recorder struct {
+	mode     Mode
+	path     string
+	scrubber Scrubber
+
+	lock     sync.Mutex
+	cassette Cassette
+
+	// replayed is the number of times each Request has been replayed
+	replayed map[string]int
+}
+
+// NewRecorder returns a Recorder for the specified Test, which in ModeReplay loads the
+// existing Cassette for this Test, or in ModeRecord starts a new Cassette
+func NewRecorder(mode Mode, testName string, scrubber Scrubber) (*Recorder, error) {
+	if mode != ModeRecord && mode != ModeReplay {
+		return nil, fmt.Errorf("a Recorder can only be used in %q or %q mode but got %q", ModeRecord, ModeReplay, mode)
+	}
+
+	recorder := Recorder{
+		mode:     mode,
+		path:     CassettePath(testName),
+		scrubber: scrubber,
+		cassette: Cassette{
+			Name:         testName,
+			Interactions: make([]Interaction, 0),
+		},
+		replayed: make(map[string]int),
+	}
+
+	if mode == ModeReplay {
+		cassette, err := LoadCassette(recorder.path)
+		if err != nil {
+			return nil, fmt.Errorf("loading the Cassette for %q (this Test needs to be run with `%s=%s` first): %+v", testName, ModeEnvVar, ModeRecord, err)
+		}
+		recorder.cassette = *cassette
+	}
+
+	return &recorder, nil
+}
+
+// Mode returns the Mode this Recorder is running in
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Cassette returns the Cassette being recorded/replayed
+func (r *Recorder) Cassette() *Cassette {
+	return &r.cassette
+}
+
+// Save writes the recorded Cassette to disk, this is a no-op when replaying
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	log.Printf("[DEBUG] Saving %d Interactions to the Cassette %q..", len(r.cassette.Interactions), r.path)
+	return r.cassette.Save(r.path)
+}
+
+// SendDecorator returns an autorest.SendDecorator which records or replays each request
+func (r *Recorder) SendDecorator() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+			// tokens are never recorded, since these are sensitive - instead a placeholder is used on replay
+			if isTokenRequest(req) {
+				if r.mode == ModeReplay {
+					return placeholderTokenResponse(req), nil
+				}
+
+				return s.Do(req)
+			}
+
+			if r.mode == ModeReplay {
+				return r.replay(req)
+			}
+
+			return r.record(s, req)
+		})
+	}
+}
+
+func (r *Recorder) record(s autorest.Sender, req *http.Request) (*http.Response, error) {
+	requestBody, err := readAndRestoreRequestBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("reading the request body for %s %s: %+v", req.Method, req.URL, err)
+	}
+
+	resp, err := s.Do(req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	responseBody, err := readAndRestoreResponseBody(resp)
+	if err != nil {
+		return resp, fmt.Errorf("reading the response body for %s %s: %+v", req.Method, req.URL, err)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    r.scrubber.Scrub(req.URL.String()),
+			Body:   r.scrubber.ScrubBody(req.URL.String(), requestBody),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    r.scrubber.ScrubResponseHeaders(resp.Header),
+			Body:       r.scrubber.ScrubBody(req.URL.String(), responseBody),
+		},
+	})
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	method := req.Method
+	url := r.scrubber.Scrub(req.URL.String())
+	key := fmt.Sprintf("%s %s", method, url)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// identical requests (for example polling) are replayed in the order they were recorded, once the
+	// recorded responses for a read have been exhausted the last response continues to be returned
+	matches := make([]*Interaction, 0)
+	for i, interaction := range r.cassette.Interactions {
+		if interaction.Request.Method == method && interaction.Request.URL == url {
+			matches = append(matches, &r.cassette.Interactions[i])
+		}
+	}
+
+	var match *Interaction
+	if occurrence := r.replayed[key]; occurrence < len(matches) {
+		match = matches[occurrence]
+	} else if len(matches) > 0 && strings.EqualFold(method, http.MethodGet) {
+		match = matches[len(matches)-1]
+	}
+	if match == nil {
+		return nil, fmt.Errorf("no recorded Interaction was found for %s (occurrence %d) in the Cassette %q - this Test may need to be re-recorded", key, r.replayed[key]+1, r.path)
+	}
+	r.replayed[key]++
+
+	resp := &http.Response{
+		StatusCode: match.Response.StatusCode,
+		Status:     fmt.Sprintf("%d %s", match.Response.StatusCode, http.StatusText(match.Response.StatusCode)),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Request:    req,
+	}
+	for k, values := range match.Response.Headers {
+		for _, v := range values {
+			resp.Header.Add(k, r.scrubber.Unscrub(v))
+		}
+	}
+	// there's no need to wait when polling recorded Long Running Operations
+	if resp.Header.Get("Retry-After") != "" {
+		resp.Header.Set("Retry-After", "0")
+	}
+
+	body := r.scrubber.Unscrub(match.Response.Body)
+	resp.Body = ioutil.NopCloser(strings.NewReader(body))
+	resp.ContentLength = int64(len(body))
+
+	return resp, nil
+}
+
+func isTokenRequest(req *http.Request) bool {
+	path := strings.ToLower(req.URL.Path)
+	return strings.HasSuffix(path, "/oauth2/token") || strings.HasSuffix(path, "/oauth2/v2.0/token")
+}
+
+// placeholderTokenResponse returns a token response which is valid for an hour - which is
+// sufficient for the Azure SDK to send requests on replay, which never reach Azure
+func placeholderTokenResponse(req *http.Request) *http.Response {
+	resource := ""
+	if err := req.ParseForm(); err == nil {
+		resource = req.PostForm.Get("resource")
+	}
+
+	now := time.Now().Unix()
+	body := fmt.Sprintf(`{"token_type":"Bearer","expires_in":"3600","expires_on":"%s","not_before":"%s","resource":%q,"access_token":%q}`,
+		strconv.FormatInt(now+3600, 10), strconv.FormatInt(now, 10), resource, redactedValue)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Content-Type": []string{"application/json"},
+		},
+		Body:          ioutil.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func readAndRestoreRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+
+	contents, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(contents))
+	return string(contents), nil
+}
+
+func readAndRestoreResponseBody(resp *http.Response) (string, error) {
+	if resp.Body == nil {
+		return "", nil
+	}
+
+	contents, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(contents))
+	return string(contents), nil
+}
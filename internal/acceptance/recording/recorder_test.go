@@ -0,0 +1,269 @@
+package recording
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+)
+
+const (
+	testSubscriptionId = "12345678-1234-9876-4563-123456789012"
+	testTenantId       = "11111111-2222-3333-4444-555555555555"
+	testClientId       = "66666666-7777-8888-9999-000000000000"
+)
+
+// fakeResourceManager is a stand-in for Resource Manager which provisions a single
+// Resource Group via a Long Running Operation
+type fakeResourceManager struct {
+	lock     sync.Mutex
+	requests int
+	tokens   int
+	polls    int
+}
+
+func (f *fakeResourceManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Set-Cookie", "session=super-secret")
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/oauth2/token"):
+		f.tokens++
+		_, _ = w.Write([]byte(`{"token_type":"Bearer","expires_in":"3600","expires_on":"32503680000","access_token":"real-token"}`))
+
+	case r.Method == http.MethodPut:
+		f.requests++
+		w.Header().Set("Azure-AsyncOperation", "http://"+r.Host+"/subscriptions/"+testSubscriptionId+"/operations/abc")
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"/subscriptions/` + testSubscriptionId + `/resourceGroups/example","properties":{"adminPassword":"P@ssw0rd1234!","provisioningState":"Creating"}}`))
+
+	case strings.Contains(r.URL.Path, "/operations/"):
+		f.requests++
+		f.polls++
+		if f.polls < 2 {
+			_, _ = w.Write([]byte(`{"status":"InProgress"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"Succeeded"}`))
+
+	default:
+		f.requests++
+		_, _ = w.Write([]byte(`{"id":"/subscriptions/` + testSubscriptionId + `/resourceGroups/example"}`))
+	}
+}
+
+func TestRecorderRecordThenReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recording")
+	if err != nil {
+		t.Fatalf("creating temp dir: %+v", err)
+	}
+	defer os.RemoveAll(dir)
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("changing directory: %+v", err)
+	}
+	defer os.Chdir(wd) // nolint: errcheck
+
+	server := &fakeResourceManager{}
+	httpServer := httptest.NewServer(server)
+	scrubber := NewScrubber(testSubscriptionId, testTenantId, testClientId)
+
+	// record..
+	recorder, err := NewRecorder(ModeRecord, "TestAccExample_basic", scrubber)
+	if err != nil {
+		t.Fatalf("building Recorder: %+v", err)
+	}
+	recorder.Cassette().RandomInteger = 1234
+	recorded := sendRequests(t, recorder, httpServer.URL)
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("saving: %+v", err)
+	}
+	httpServer.Close()
+
+	if server.requests != 4 {
+		t.Fatalf("expected 4 requests to have been sent to Azure but got %d", server.requests)
+	}
+	if server.tokens == 0 {
+		t.Fatalf("expected a token to have been requested from Azure but it wasn't")
+	}
+
+	contents, err := ioutil.ReadFile(filepath.Join(dir, "testdata", "recordings", "TestAccExample_basic.json"))
+	if err != nil {
+		t.Fatalf("reading Cassette: %+v", err)
+	}
+	for _, sensitive := range []string{testSubscriptionId, "P@ssw0rd1234!", "super-secret", "real-token"} {
+		if strings.Contains(string(contents), sensitive) {
+			t.Fatalf("expected %q to have been scrubbed from the Cassette but it wasn't: %s", sensitive, string(contents))
+		}
+	}
+
+	// .. then replay, without the server
+	replayer, err := NewRecorder(ModeReplay, "TestAccExample_basic", scrubber)
+	if err != nil {
+		t.Fatalf("building Recorder: %+v", err)
+	}
+	if replayer.Cassette().RandomInteger != 1234 {
+		t.Fatalf("expected the RandomInteger to be 1234 but got %d", replayer.Cassette().RandomInteger)
+	}
+	replayed := sendRequests(t, replayer, httpServer.URL)
+
+	if len(recorded) != len(replayed) {
+		t.Fatalf("expected %d responses but got %d", len(recorded), len(replayed))
+	}
+	for i := range recorded {
+		expected := strings.ReplaceAll(recorded[i], "P@ssw0rd1234!", redactedValue)
+		if replayed[i] != expected {
+			t.Fatalf("expected response %d to be %q but got %q", i, expected, replayed[i])
+		}
+	}
+
+	// a write which wasn't recorded
+	client := autorest.NewClientWithUserAgent("")
+	client.Sender = autorest.DecorateSender(http.DefaultClient, replayer.SendDecorator())
+	req, _ := http.NewRequest(http.MethodDelete, httpServer.URL+"/subscriptions/"+testSubscriptionId+"/resourceGroups/example", nil)
+	if _, err := autorest.SendWithSender(client, req); err == nil {
+		t.Fatalf("expected an error for a request which wasn't recorded but didn't get one")
+	}
+}
+
+// sendRequests creates a resource, polls its Long Running Operation until completion and then
+// reads it - returning each of the response bodies
+func sendRequests(t *testing.T, recorder *Recorder, endpoint string) []string {
+	sender := autorest.DecorateSender(http.DefaultClient, recorder.SendDecorator())
+
+	oauthConfig, err := adal.NewOAuthConfig(endpoint, testTenantId)
+	if err != nil {
+		t.Fatalf("building OAuth Config: %+v", err)
+	}
+	token, err := adal.NewServicePrincipalToken(*oauthConfig, testClientId, "secret", endpoint)
+	if err != nil {
+		t.Fatalf("building token: %+v", err)
+	}
+	token.SetSender(sender)
+
+	client := autorest.NewClientWithUserAgent("")
+	client.Sender = sender
+	client.Authorizer = autorest.NewBearerAuthorizer(token)
+
+	resourceUri := endpoint + "/subscriptions/" + testSubscriptionId + "/resourceGroups/example?api-version=2020-06-01"
+	bodies := make([]string, 0)
+	send := func(method, uri string) *http.Response {
+		req, _ := http.NewRequest(method, uri, strings.NewReader(`{"location":"westeurope"}`))
+		resp, err := autorest.SendWithSender(client, req)
+		if err != nil {
+			t.Fatalf("sending %s %s: %+v", method, uri, err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		bodies = append(bodies, string(body))
+		return resp
+	}
+
+	resp := send(http.MethodPut, resourceUri)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected a 201 but got %d", resp.StatusCode)
+	}
+	pollingUri := resp.Header.Get("Azure-AsyncOperation")
+	if !strings.Contains(pollingUri, testSubscriptionId) {
+		t.Fatalf("expected the polling uri to contain the Subscription ID but got %q", pollingUri)
+	}
+	send(http.MethodGet, pollingUri)
+	send(http.MethodGet, pollingUri)
+	send(http.MethodGet, resourceUri)
+
+	return bodies
+}
+
+func TestScrubber(t *testing.T) {
+	scrubber := NewScrubber(testSubscriptionId, testTenantId, testClientId)
+
+	testData := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "/subscriptions/" + testSubscriptionId + "/resourceGroups/example",
+			expected: "/subscriptions/" + SubscriptionIdPlaceholder + "/resourceGroups/example",
+		},
+		{
+			input:    "/subscriptions/" + strings.ToUpper(testSubscriptionId) + "/resourceGroups/example",
+			expected: "/subscriptions/" + SubscriptionIdPlaceholder + "/resourceGroups/example",
+		},
+		{
+			input:    `{"tenantId":"` + testTenantId + `","clientId":"` + testClientId + `"}`,
+			expected: `{"tenantId":"` + TenantIdPlaceholder + `","clientId":"` + ClientIdPlaceholder + `"}`,
+		},
+		{
+			input:    `{"adminPassword": "abc\"123", "primaryKey":"abc==", "keyVaultId":"abc", "connectionString":"Endpoint=sb://"}`,
+			expected: `{"adminPassword": "REDACTED", "primaryKey":"REDACTED", "keyVaultId":"abc", "connectionString":"REDACTED"}`,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.input)
+
+		actual := scrubber.Scrub(v.input)
+		if actual != v.expected {
+			t.Fatalf("expected %q but got %q", v.expected, actual)
+		}
+	}
+
+	if actual := scrubber.Unscrub(scrubber.Scrub("/subscriptions/" + testSubscriptionId)); actual != "/subscriptions/"+testSubscriptionId {
+		t.Fatalf("expected the Subscription ID to be restored but got %q", actual)
+	}
+}
+
+func TestScrubberScrubBody(t *testing.T) {
+	scrubber := NewScrubber(testSubscriptionId, testTenantId, testClientId)
+
+	testData := []struct {
+		name     string
+		uri      string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Key Vault Secret",
+			uri:      "https://example.vault.azure.net/secrets/example/abc123?api-version=7.1",
+			input:    `{"value":"s3cr3t\"value","id":"https://example.vault.azure.net/secrets/example/abc123","attributes":{"enabled":true}}`,
+			expected: `{"value":"REDACTED","id":"https://example.vault.azure.net/secrets/example/abc123","attributes":{"enabled":true}}`,
+		},
+		{
+			name:     "Key Vault Secret in another Cloud",
+			uri:      "https://example.vault.usgovcloudapi.net/secrets/example?api-version=7.1",
+			input:    `{"value": "s3cr3t", "contentType": "text/plain"}`,
+			expected: `{"value": "REDACTED", "contentType": "text/plain"}`,
+		},
+		{
+			name:     "Storage Account List Keys",
+			uri:      "https://management.azure.com/subscriptions/" + testSubscriptionId + "/resourceGroups/example/providers/Microsoft.Storage/storageAccounts/example/listKeys?api-version=2021-01-01",
+			input:    `{"keys":[{"keyName":"key1","value":"abc==","permissions":"FULL"},{"keyName":"key2","value":"def==","permissions":"FULL"}]}`,
+			expected: `{"keys":[{"keyName":"key1","value":"REDACTED","permissions":"FULL"},{"keyName":"key2","value":"REDACTED","permissions":"FULL"}]}`,
+		},
+		{
+			name:     "Resource Manager",
+			uri:      "https://management.azure.com/subscriptions/" + testSubscriptionId + "/resourceGroups/example/providers/Microsoft.Web/sites/example/config/appsettings?api-version=2020-12-01",
+			input:    `{"value":[{"name":"example","value":"abc"}],"properties":{"adminPassword":"abc"}}`,
+			expected: `{"value":[{"name":"example","value":"abc"}],"properties":{"adminPassword":"REDACTED"}}`,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		actual := scrubber.ScrubBody(v.uri, v.input)
+		if actual != v.expected {
+			t.Fatalf("expected %q but got %q", v.expected, actual)
+		}
+	}
+}
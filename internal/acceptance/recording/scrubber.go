@@ -0,0 +1,129 @@
+package recording
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	// SubscriptionIdPlaceholder replaces the Subscription ID within recorded Interactions
+	SubscriptionIdPlaceholder = "00000000-0000-0000-0000-000000000000"
+
+	// TenantIdPlaceholder replaces the Tenant ID within recorded Interactions
+	TenantIdPlaceholder = "00000000-0000-0000-0000-000000000001"
+
+	// ClientIdPlaceholder replaces the Client ID within recorded Interactions
+	ClientIdPlaceholder = "00000000-0000-0000-0000-000000000002"
+
+	redactedValue = "REDACTED"
+)
+
+// recordedResponseHeaders are the Response Headers which are recorded - these are required to
+// poll Long Running Operations, all others are discarded since they can contain sensitive values
+var recordedResponseHeaders = []string{
+	"Azure-AsyncOperation",
+	"Content-Type",
+	"ETag",
+	"Location",
+	"Retry-After",
+}
+
+// sensitiveFieldsRegex matches JSON fields whose values are sensitive, for example passwords,
+// keys and connection strings - as such the values of these fields are redacted
+var sensitiveFieldsRegex = regexp.MustCompile(`(?i)("[a-z0-9_]*(?:password|secret|accesskey|primarykey|secondarykey|connectionstring|sastoken|token)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// sensitiveValueEndpointsRegex matches the URIs of endpoints which return a sensitive value within
+// the (otherwise generic) `value` field - that is the Key Vault/Managed HSM Data Plane (for example
+// a Secret) and the listing/regeneration of keys (for example a Storage Account's Access Keys)
+var sensitiveValueEndpointsRegex = regexp.MustCompile(`(?i)^https://[^/]+\.(?:vault|managedhsm)\.[^/]+/|/(?:listKeys|regenerateKey)(?:\?|$)`)
+
+// valueFieldRegex matches the string `value` fields within a JSON body
+var valueFieldRegex = regexp.MustCompile(`("value"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+type replacement struct {
+	value       string
+	placeholder string
+}
+
+// Scrubber removes identifiers (such as the Subscription ID) and sensitive values (such as
+// passwords) from the Interactions being recorded, and restores the identifiers on replay
+type Scrubber struct {
+	replacements []replacement
+}
+
+// NewScrubber returns a Scrubber which replaces the specified identifiers with their placeholders
+func NewScrubber(subscriptionId, tenantId, clientId string) Scrubber {
+	replacements := make([]replacement, 0)
+	for value, placeholder := range map[string]string{
+		subscriptionId: SubscriptionIdPlaceholder,
+		tenantId:       TenantIdPlaceholder,
+		clientId:       ClientIdPlaceholder,
+	} {
+		if value == "" || value == placeholder {
+			continue
+		}
+
+		replacements = append(replacements, replacement{
+			value:       value,
+			placeholder: placeholder,
+		})
+	}
+
+	return Scrubber{
+		replacements: replacements,
+	}
+}
+
+// Scrub replaces the identifiers within the input with their placeholders and redacts any sensitive values
+func (s Scrubber) Scrub(input string) string {
+	output := input
+	for _, r := range s.replacements {
+		output = replaceCaseInsensitive(output, r.value, r.placeholder)
+	}
+
+	return sensitiveFieldsRegex.ReplaceAllString(output, `${1}"`+redactedValue+`"`)
+}
+
+// ScrubBody scrubs the body of a request/response sent to the specified URI (see Scrub) - additionally
+// redacting the `value` fields for endpoints known to return sensitive values within them.
+//
+// NOTE: Tests which assert on these values can't be replayed, since the redacted value is returned.
+func (s Scrubber) ScrubBody(uri string, body string) string {
+	output := s.Scrub(body)
+	if sensitiveValueEndpointsRegex.MatchString(uri) {
+		output = valueFieldRegex.ReplaceAllString(output, `${1}"`+redactedValue+`"`)
+	}
+	return output
+}
+
+// Unscrub replaces the placeholders within the input with their identifiers
+func (s Scrubber) Unscrub(input string) string {
+	output := input
+	for _, r := range s.replacements {
+		output = strings.ReplaceAll(output, r.placeholder, r.value)
+	}
+	return output
+}
+
+// ScrubResponseHeaders returns the Response Headers which should be recorded, with any identifiers scrubbed
+func (s Scrubber) ScrubResponseHeaders(input http.Header) map[string][]string {
+	output := make(map[string][]string)
+	for _, key := range recordedResponseHeaders {
+		values := input.Values(key)
+		if len(values) == 0 {
+			continue
+		}
+
+		scrubbed := make([]string, 0)
+		for _, v := range values {
+			scrubbed = append(scrubbed, s.Scrub(v))
+		}
+		output[http.CanonicalHeaderKey(key)] = scrubbed
+	}
+	return output
+}
+
+func replaceCaseInsensitive(input, value, replacement string) string {
+	return regexp.MustCompile(`(?i)`+regexp.QuoteMeta(value)).ReplaceAllLiteralString(input, replacement)
+}
@@ -49,6 +49,53 @@ func (td TestData) DisappearsStep(data DisappearsStepData) resource.TestStep {
 	}
 }
 
+type DriftStepData struct {
+	// Config is a function which returns the Terraform Configuration which should be used for this step
+	Config func(data TestData) string
+
+	// TestResource is a reference to a TestResource which can change the resource outside of Terraform
+	// to enable a Drift step
+	TestResource types.TestResourceVerifyingDrift
+
+	// ExpectedChanges is a map of the (flatmapped) attributes which the next plan is expected to change
+	// (for example `sku_name` or `tags.environment`) to their planned value, or an empty string when the
+	// attribute is expected to be removed - including any attributes derived from these (e.g. `tags_all`)
+	ExpectedChanges map[string]string
+}
+
+// DriftStep returns a Test Step which first confirms the resource exists, then changes it
+// outside of Terraform (for example updating a tag or the SKU) and confirms that the next plan
+// contains exactly the expected changes to bring the resource back in line with the configuration
+func (td TestData) DriftStep(data DriftStepData) resource.TestStep {
+	config := data.Config(td)
+	return resource.TestStep{
+		Config: config,
+		Check: resource.ComposeTestCheckFunc(
+			func(state *terraform.State) error {
+				client, err := testclient.Build()
+				if err != nil {
+					return fmt.Errorf("building client: %+v", err)
+				}
+				return helpers.ExistsInAzure(client, data.TestResource, td.ResourceName)(state)
+			},
+			func(state *terraform.State) error {
+				client, err := testclient.Build()
+				if err != nil {
+					return fmt.Errorf("building client: %+v", err)
+				}
+
+				azureResource, ok := td.testAzureProvider().ResourcesMap[td.ResourceType]
+				if !ok {
+					return fmt.Errorf("the Resource %q was not found in the Provider", td.ResourceType)
+				}
+
+				return helpers.DriftResourceFunc(client, data.TestResource, azureResource, td.ResourceName, data.ExpectedChanges)(state)
+			},
+		),
+		ExpectNonEmptyPlan: true,
+	}
+}
+
 type ClientCheckFunc func(ctx context.Context, clients *clients.Client, state *terraform.InstanceState) error
 
 // CheckWithClient returns a TestCheckFunc which will call a ClientCheckFunc
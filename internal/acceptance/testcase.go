@@ -85,6 +85,12 @@ func (td TestData) runAcceptanceTest(t *testing.T, testCase resource.TestCase) {
 	testCase.ExternalProviders = td.externalProviders()
 	testCase.ProviderFactories = td.providers()
 
+	if td.recorder != nil {
+		// the Test Client is shared, so Tests being recorded/replayed can't be run in parallel
+		td.runRecordedTest(t, testCase)
+		return
+	}
+
 	resource.ParallelTest(t, testCase)
 }
 
@@ -92,22 +98,42 @@ func (td TestData) runAcceptanceSequentialTest(t *testing.T, testCase resource.T
 	testCase.ExternalProviders = td.externalProviders()
 	testCase.ProviderFactories = td.providers()
 
+	if td.recorder != nil {
+		td.runRecordedTest(t, testCase)
+		return
+	}
+
+	resource.Test(t, testCase)
+}
+
+func (td TestData) runRecordedTest(t *testing.T, testCase resource.TestCase) {
+	reset := testclient.UseSendDecorators(td.recorder.SendDecorator())
+	defer reset()
+
 	resource.Test(t, testCase)
 }
 
 func (td TestData) providers() map[string]func() (*schema.Provider, error) {
 	return map[string]func() (*schema.Provider, error){
 		"azurerm": func() (*schema.Provider, error) { //nolint:unparam
-			azurerm := provider.TestAzureProvider()
+			azurerm := td.testAzureProvider()
 			return azurerm, nil
 		},
 		"azurerm-alt": func() (*schema.Provider, error) { //nolint:unparam
-			azurerm := provider.TestAzureProvider()
+			azurerm := td.testAzureProvider()
 			return azurerm, nil
 		},
 	}
 }
 
+func (td TestData) testAzureProvider() *schema.Provider {
+	if td.recorder != nil {
+		return provider.TestAzureProviderWithSendDecorators(td.recorder.SendDecorator())
+	}
+
+	return provider.TestAzureProvider()
+}
+
 func (td TestData) externalProviders() map[string]resource.ExternalProvider {
 	return map[string]resource.ExternalProvider{
 		"azuread": {
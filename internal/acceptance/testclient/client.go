@@ -6,6 +6,7 @@ import (
 	"os"
 	"sync"
 
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/hashicorp/go-azure-helpers/authentication"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/features"
@@ -14,42 +15,30 @@ import (
 var (
 	_client    *clients.Client
 	clientLock = &sync.Mutex{}
+
+	// _decoratedClient is the Client returned from Build when sendDecorators are configured
+	_decoratedClient *clients.Client
+	sendDecorators   []autorest.SendDecorator
 )
 
 func Build() (*clients.Client, error) {
 	clientLock.Lock()
 	defer clientLock.Unlock()
 
-	if _client == nil {
-		environment, exists := os.LookupEnv("ARM_ENVIRONMENT")
-		if !exists {
-			environment = "public"
+	if len(sendDecorators) > 0 {
+		if _decoratedClient == nil {
+			client, err := build(sendDecorators...)
+			if err != nil {
+				return nil, err
+			}
+			_decoratedClient = client
 		}
 
-		builder := authentication.Builder{
-			SubscriptionID: os.Getenv("ARM_SUBSCRIPTION_ID"),
-			ClientID:       os.Getenv("ARM_CLIENT_ID"),
-			TenantID:       os.Getenv("ARM_TENANT_ID"),
-			ClientSecret:   os.Getenv("ARM_CLIENT_SECRET"),
-			Environment:    environment,
-			MetadataHost:   os.Getenv("ARM_METADATA_HOST"),
-
-			// we intentionally only support Client Secret auth for tests (since those variables are used all over)
-			SupportsClientSecretAuth: true,
-		}
-		config, err := builder.Build()
-		if err != nil {
-			return nil, fmt.Errorf("Error building ARM Client: %+v", err)
-		}
+		return _decoratedClient, nil
+	}
 
-		clientBuilder := clients.ClientBuilder{
-			AuthConfig:               config,
-			SkipProviderRegistration: true,
-			TerraformVersion:         os.Getenv("TERRAFORM_CORE_VERSION"),
-			Features:                 features.Default(),
-			StorageUseAzureAD:        false,
-		}
-		client, err := clients.Build(context.TODO(), clientBuilder)
+	if _client == nil {
+		client, err := build()
 		if err != nil {
 			return nil, err
 		}
@@ -58,3 +47,56 @@ func Build() (*clients.Client, error) {
 
 	return _client, nil
 }
+
+// UseSendDecorators configures the Client returned from Build to apply the specified SendDecorators
+// to every request (for example to record/replay requests) until the returned function is called.
+//
+// NOTE: since this applies to all callers of Build, Tests using this can't be run in parallel
+func UseSendDecorators(decorators ...autorest.SendDecorator) func() {
+	clientLock.Lock()
+	defer clientLock.Unlock()
+
+	sendDecorators = decorators
+	_decoratedClient = nil
+
+	return func() {
+		clientLock.Lock()
+		defer clientLock.Unlock()
+
+		sendDecorators = nil
+		_decoratedClient = nil
+	}
+}
+
+func build(sendDecorators ...autorest.SendDecorator) (*clients.Client, error) {
+	environment, exists := os.LookupEnv("ARM_ENVIRONMENT")
+	if !exists {
+		environment = "public"
+	}
+
+	builder := authentication.Builder{
+		SubscriptionID: os.Getenv("ARM_SUBSCRIPTION_ID"),
+		ClientID:       os.Getenv("ARM_CLIENT_ID"),
+		TenantID:       os.Getenv("ARM_TENANT_ID"),
+		ClientSecret:   os.Getenv("ARM_CLIENT_SECRET"),
+		Environment:    environment,
+		MetadataHost:   os.Getenv("ARM_METADATA_HOST"),
+
+		// we intentionally only support Client Secret auth for tests (since those variables are used all over)
+		SupportsClientSecretAuth: true,
+	}
+	config, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("Error building ARM Client: %+v", err)
+	}
+
+	clientBuilder := clients.ClientBuilder{
+		AuthConfig:               config,
+		SkipProviderRegistration: true,
+		TerraformVersion:         os.Getenv("TERRAFORM_CORE_VERSION"),
+		Features:                 features.Default(),
+		StorageUseAzureAD:        false,
+		SendDecorators:           sendDecorators,
+	}
+	return clients.Build(context.TODO(), clientBuilder)
+}
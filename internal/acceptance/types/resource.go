@@ -15,3 +15,8 @@ type TestResourceVerifyingRemoved interface {
 	TestResource
 	Destroy(ctx context.Context, client *clients.Client, state *pluginsdk.InstanceState) (*bool, error)
 }
+
+type TestResourceVerifyingDrift interface {
+	TestResource
+	Drift(ctx context.Context, client *clients.Client, state *pluginsdk.InstanceState) (*bool, error)
+}
@@ -2,10 +2,20 @@ package clients
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/hashicorp/go-azure-helpers/authentication"
+	"github.com/hashicorp/go-azure-helpers/sender"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/common"
 )
 
 type ResourceManagerAccount struct {
@@ -16,6 +26,9 @@ type ResourceManagerAccount struct {
 	SkipResourceProviderRegistration bool
 	SubscriptionId                   string
 	TenantId                         string
+
+	// ResourceProvidersToRegister are the Resource Providers which are registered up-front by the Provider
+	ResourceProvidersToRegister map[string]struct{}
 }
 
 func NewResourceManagerAccount(ctx context.Context, config authentication.Config, env azure.Environment, skipResourceProviderRegistration bool) (*ResourceManagerAccount, error) {
@@ -41,3 +54,221 @@ func NewResourceManagerAccount(ctx context.Context, config authentication.Config
 	}
 	return &account, nil
 }
+
+// clientAssertionTypeJWT is the type of Client Assertion used when exchanging an OIDC token for an access token
+const clientAssertionTypeJWT = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// oidcTokenAudience is the audience requested for OIDC tokens, which is required for Workload Identity Federation
+const oidcTokenAudience = "api://AzureADTokenExchange"
+
+// OIDCAuth configures authenticating as a Service Principal using an OIDC token (for example issued by GitHub
+// Actions or GitLab) which is exchanged for access tokens using Workload Identity Federation, rather than a secret
+type OIDCAuth struct {
+	// Token is the OIDC token which should be exchanged
+	Token string
+
+	// TokenFilePath is the path to a file containing the OIDC token, which is re-read each time an access token is requested
+	TokenFilePath string
+
+	// RequestURL and RequestToken are used to request an OIDC token (for example from GitHub Actions)
+	RequestURL   string
+	RequestToken string
+}
+
+// authorizationTokenFunc returns an Authorizer for the specified endpoint, matching authentication.Config.GetAuthorizationToken
+type authorizationTokenFunc func(sender autorest.Sender, oauth *authentication.OAuthConfig, endpoint string) (autorest.Authorizer, error)
+
+// BuildConfig builds the authentication.Config used when authenticating using an OIDC token - which
+// is used in place of authentication.Builder.Build, which doesn't support this authentication method.
+//
+// NOTE: the authentication method within the authentication.Config is private (and so can't be set here), as
+// such the Config returned must be used together with this OIDCAuth (see ClientBuilder.UseOIDC and ClientBuilder.OIDC)
+// - since the Config can't obtain an Authorization Token itself
+func (a OIDCAuth) BuildConfig(b authentication.Builder) (*authentication.Config, error) {
+	fmtErrorMessage := "A %s must be configured when authenticating as a Service Principal using an OIDC token."
+	if b.SubscriptionID == "" {
+		return nil, fmt.Errorf(fmtErrorMessage, "Subscription ID")
+	}
+	if b.ClientID == "" {
+		return nil, fmt.Errorf(fmtErrorMessage, "Client ID")
+	}
+	if b.TenantID == "" {
+		return nil, fmt.Errorf(fmtErrorMessage, "Tenant ID")
+	}
+	if len(b.AuxiliaryTenantIDs) > 0 {
+		return nil, fmt.Errorf("Auxiliary Tenants are not supported when authenticating as a Service Principal using an OIDC token")
+	}
+
+	if a.Token == "" && a.TokenFilePath == "" && (a.RequestURL == "" || a.RequestToken == "") {
+		return nil, fmt.Errorf("one of `oidc_token`, `oidc_token_file_path` or both `oidc_request_url` and `oidc_request_token` must be configured when authenticating as a Service Principal using an OIDC token")
+	}
+
+	config := authentication.Config{
+		ClientID:                         b.ClientID,
+		SubscriptionID:                   b.SubscriptionID,
+		TenantID:                         b.TenantID,
+		Environment:                      b.Environment,
+		MetadataHost:                     b.MetadataHost,
+		AuthenticatedAsAServicePrincipal: true,
+	}
+	config.GetAuthenticatedObjectID = a.servicePrincipalObjectIdFunc(config)
+
+	return &config, nil
+}
+
+// getAuthorizationTokenFunc returns the function used to obtain an Authorizer for each endpoint - which uses the
+// OIDCAuth when authenticating using an OIDC token, otherwise the authentication method within the authentication.Config
+func getAuthorizationTokenFunc(config authentication.Config, useOIDC bool, oidc *OIDCAuth) (authorizationTokenFunc, error) {
+	if !useOIDC {
+		return config.GetAuthorizationToken, nil
+	}
+
+	// a Config built for OIDC (see OIDCAuth.BuildConfig) has no authentication method, so can't obtain a token itself
+	if oidc == nil {
+		return nil, fmt.Errorf("the OIDC configuration must be specified when authenticating using an OIDC token")
+	}
+
+	return oidc.authorizationTokenFunc(config.ClientID), nil
+}
+
+func (a OIDCAuth) authorizationTokenFunc(clientId string) authorizationTokenFunc {
+	return func(sender autorest.Sender, oauth *authentication.OAuthConfig, endpoint string) (autorest.Authorizer, error) {
+		if oauth.OAuth == nil {
+			return nil, fmt.Errorf("getting Authorization Token for OIDC auth: an OAuth token wasn't configured correctly")
+		}
+
+		secret := &oidcClientAssertion{
+			auth:   a,
+			sender: sender,
+		}
+		spt, err := adal.NewServicePrincipalTokenWithSecret(*oauth.OAuth, clientId, endpoint, secret)
+		if err != nil {
+			return nil, err
+		}
+		spt.SetSender(sender)
+
+		return autorest.NewBearerAuthorizer(spt), nil
+	}
+}
+
+// federatedToken returns the OIDC token which should be exchanged for an access token
+func (a OIDCAuth) federatedToken(sender autorest.Sender) (string, error) {
+	if a.Token != "" {
+		return a.Token, nil
+	}
+
+	if a.TokenFilePath != "" {
+		contents, err := ioutil.ReadFile(a.TokenFilePath)
+		if err != nil {
+			return "", fmt.Errorf("reading OIDC token from %q: %+v", a.TokenFilePath, err)
+		}
+
+		token := strings.TrimSpace(string(contents))
+		if token == "" {
+			return "", fmt.Errorf("reading OIDC token from %q: the file was empty", a.TokenFilePath)
+		}
+		return token, nil
+	}
+
+	return a.requestFederatedToken(sender)
+}
+
+// requestFederatedToken requests an OIDC token from the RequestURL (for example from GitHub Actions)
+func (a OIDCAuth) requestFederatedToken(sender autorest.Sender) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, a.RequestURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("building request for OIDC token: %+v", err)
+	}
+
+	query := req.URL.Query()
+	query.Set("audience", oidcTokenAudience)
+	req.URL.RawQuery = query.Encode()
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.RequestToken))
+
+	resp, err := sender.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting OIDC token: %+v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("requesting OIDC token: expected a 200 but got a %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("parsing OIDC token response: %+v", err)
+	}
+	if result.Value == "" {
+		return "", fmt.Errorf("parsing OIDC token response: the token was empty")
+	}
+
+	return result.Value, nil
+}
+
+// servicePrincipalObjectIdFunc returns a function which looks up the Object ID of the Service Principal
+func (a OIDCAuth) servicePrincipalObjectIdFunc(config authentication.Config) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		env, err := authentication.AzureEnvironmentByNameFromEndpoint(ctx, config.MetadataHost, config.Environment)
+		if err != nil {
+			return "", err
+		}
+
+		s := sender.BuildSender("AzureRM")
+
+		oauthConfig, err := config.BuildOAuthConfig(env.ActiveDirectoryEndpoint)
+		if err != nil {
+			return "", err
+		}
+
+		graphAuth, err := a.authorizationTokenFunc(config.ClientID)(s, oauthConfig, env.GraphEndpoint)
+		if err != nil {
+			return "", err
+		}
+
+		client := graphrbac.NewServicePrincipalsClientWithBaseURI(env.GraphEndpoint, config.TenantID)
+		client.Authorizer = graphAuth
+		client.Sender = s
+
+		result, err := client.List(ctx, fmt.Sprintf("appId eq '%s'", config.ClientID))
+		if err != nil {
+			return "", fmt.Errorf("listing Service Principals: %+v", err)
+		}
+		if result.Values() == nil || len(result.Values()) != 1 || result.Values()[0].ObjectID == nil {
+			return "", fmt.Errorf("unexpected Service Principal query result: %+v", result.Values())
+		}
+
+		return *result.Values()[0].ObjectID, nil
+	}
+}
+
+// oidcClientAssertion authenticates token requests using an OIDC token as a Client Assertion
+type oidcClientAssertion struct {
+	auth   OIDCAuth
+	sender autorest.Sender
+}
+
+func (s *oidcClientAssertion) SetAuthenticationValues(_ *adal.ServicePrincipalToken, v *url.Values) error {
+	token, err := s.auth.federatedToken(s.sender)
+	if err != nil {
+		return err
+	}
+
+	v.Set("client_assertion", token)
+	v.Set("client_assertion_type", clientAssertionTypeJWT)
+	return nil
+}
+
+// bearerAuthorizerCallback returns a BearerAuthorizerCallback (used for Key Vault) which is valid only for the Primary
+// Tenant - which mirrors authentication.Config.BearerAuthorizerCallback, but caches the tokens in the TokenManager
+func bearerAuthorizerCallback(ctx context.Context, sender autorest.Sender, oauthConfig *authentication.OAuthConfig, tenantId string, tokenManager *common.TokenManager, getAuthorizationToken authorizationTokenFunc) *autorest.BearerAuthorizerCallback {
+	return autorest.NewBearerAuthorizerCallback(sender, func(_, resource string) (*autorest.BearerAuthorizer, error) {
+		return tokenManager.BearerAuthorizer(ctx, tenantId, resource, func() (autorest.Authorizer, error) {
+			// a BearerAuthorizer is only valid for the primary tenant
+			return getAuthorizationToken(sender, &authentication.OAuthConfig{OAuth: oauthConfig.OAuth}, resource)
+		})
+	})
+}
@@ -0,0 +1,358 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/hashicorp/go-azure-helpers/authentication"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/common"
+)
+
+const (
+	testOIDCClientId     = "11111111-1111-1111-1111-111111111111"
+	testOIDCTenantId     = "22222222-2222-2222-2222-222222222222"
+	testOIDCRequestToken = "request-token"
+	testOIDCToken        = "federated-token"
+)
+
+// fakeTokenEndpoint issues OIDC tokens (at /oidc) and exchanges them for access tokens (at /{tenant}/oauth2/token)
+type fakeTokenEndpoint struct {
+	t *testing.T
+
+	// assertions contains the Client Assertions received, keyed by resource
+	assertions map[string]string
+}
+
+func newFakeTokenEndpoint(t *testing.T) (*fakeTokenEndpoint, *httptest.Server) {
+	endpoint := &fakeTokenEndpoint{
+		t:          t,
+		assertions: map[string]string{},
+	}
+	server := httptest.NewServer(endpoint)
+	t.Cleanup(server.Close)
+	return endpoint, server
+}
+
+func (f *fakeTokenEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/oidc":
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", testOIDCRequestToken) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if v := r.URL.Query().Get("audience"); v != oidcTokenAudience {
+			f.t.Errorf("expected the audience %q but got %q", oidcTokenAudience, v)
+		}
+		f.writeJSON(w, map[string]interface{}{
+			"value": testOIDCToken,
+		})
+
+	case fmt.Sprintf("/%s/oauth2/token", testOIDCTenantId):
+		if err := r.ParseForm(); err != nil {
+			f.t.Errorf("parsing token request: %+v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if v := r.PostForm.Get("client_id"); v != testOIDCClientId {
+			f.t.Errorf("expected the client_id %q but got %q", testOIDCClientId, v)
+		}
+		if v := r.PostForm.Get("client_assertion_type"); v != clientAssertionTypeJWT {
+			f.t.Errorf("expected the client_assertion_type %q but got %q", clientAssertionTypeJWT, v)
+		}
+		if v := r.PostForm.Get("client_secret"); v != "" {
+			f.t.Errorf("expected no client_secret but got %q", v)
+		}
+
+		resource := r.PostForm.Get("resource")
+		f.assertions[resource] = r.PostForm.Get("client_assertion")
+
+		f.writeJSON(w, map[string]interface{}{
+			"access_token": fmt.Sprintf("access-token-for-%s", resource),
+			"token_type":   "Bearer",
+			"expires_in":   "3600",
+			"expires_on":   strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10),
+			"not_before":   strconv.FormatInt(time.Now().Unix(), 10),
+			"resource":     resource,
+		})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeTokenEndpoint) writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		f.t.Errorf("writing response: %+v", err)
+	}
+}
+
+func TestOIDCAuthFederatedToken(t *testing.T) {
+	_, server := newFakeTokenEndpoint(t)
+
+	tokenFilePath := filepath.Join(t.TempDir(), "token")
+	if err := ioutil.WriteFile(tokenFilePath, []byte(testOIDCToken+"\n"), 0600); err != nil {
+		t.Fatalf("writing token file: %+v", err)
+	}
+
+	testCases := []struct {
+		Name     string
+		Auth     OIDCAuth
+		Expected string
+		Error    bool
+	}{
+		{
+			Name:     "Token",
+			Auth:     OIDCAuth{Token: testOIDCToken},
+			Expected: testOIDCToken,
+		},
+		{
+			Name:     "Token File Path",
+			Auth:     OIDCAuth{TokenFilePath: tokenFilePath},
+			Expected: testOIDCToken,
+		},
+		{
+			Name:  "Missing Token File",
+			Auth:  OIDCAuth{TokenFilePath: filepath.Join(t.TempDir(), "missing")},
+			Error: true,
+		},
+		{
+			Name: "Request URL",
+			Auth: OIDCAuth{
+				RequestURL:   fmt.Sprintf("%s/oidc?api-version=2.0", server.URL),
+				RequestToken: testOIDCRequestToken,
+			},
+			Expected: testOIDCToken,
+		},
+		{
+			Name: "Request URL Unauthorized",
+			Auth: OIDCAuth{
+				RequestURL:   fmt.Sprintf("%s/oidc", server.URL),
+				RequestToken: "invalid",
+			},
+			Error: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			actual, err := testCase.Auth.federatedToken(http.DefaultClient)
+			if err != nil {
+				if testCase.Error {
+					return
+				}
+				t.Fatalf("retrieving token: %+v", err)
+			}
+			if testCase.Error {
+				t.Fatalf("expected an error but didn't get one")
+			}
+
+			if actual != testCase.Expected {
+				t.Fatalf("expected %q but got %q", testCase.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestOIDCAuthAuthorizationToken(t *testing.T) {
+	endpoint, server := newFakeTokenEndpoint(t)
+
+	oauthConfig, err := adal.NewOAuthConfig(server.URL, testOIDCTenantId)
+	if err != nil {
+		t.Fatalf("building OAuth Config: %+v", err)
+	}
+
+	auth := OIDCAuth{
+		RequestURL:   fmt.Sprintf("%s/oidc", server.URL),
+		RequestToken: testOIDCRequestToken,
+	}
+	getAuthorizationToken := auth.authorizationTokenFunc(testOIDCClientId)
+
+	// each of the audiences used by the Provider should be able to obtain a token
+	resources := []string{
+		"https://management.azure.com/",
+		"https://graph.windows.net/",
+		"https://storage.azure.com/",
+		"https://dev.azuresynapse.net",
+		"https://batch.core.windows.net/",
+	}
+	for _, resource := range resources {
+		authorizer, err := getAuthorizationToken(http.DefaultClient, &authentication.OAuthConfig{OAuth: oauthConfig}, resource)
+		if err != nil {
+			t.Fatalf("building Authorizer for %q: %+v", resource, err)
+		}
+
+		if v := authorizationHeader(t, authorizer); v != fmt.Sprintf("Bearer access-token-for-%s", resource) {
+			t.Fatalf("unexpected Authorization header for %q: %q", resource, v)
+		}
+		if v := endpoint.assertions[resource]; v != testOIDCToken {
+			t.Fatalf("expected the Client Assertion for %q to be %q but got %q", resource, testOIDCToken, v)
+		}
+	}
+
+	// Key Vault uses a callback, since the resource is determined from the challenge
+	keyVaultAuth := bearerAuthorizerCallback(context.TODO(), http.DefaultClient, &authentication.OAuthConfig{OAuth: oauthConfig}, testOIDCTenantId, common.NewTokenManager(), getAuthorizationToken)
+	if keyVaultAuth == nil {
+		t.Fatalf("expected a Key Vault Authorizer but got nil")
+	}
+}
+
+func TestOIDCAuthBuildConfig(t *testing.T) {
+	testCases := []struct {
+		Name    string
+		Auth    OIDCAuth
+		Builder authentication.Builder
+		Error   bool
+	}{
+		{
+			Name: "Valid",
+			Auth: OIDCAuth{Token: testOIDCToken},
+			Builder: authentication.Builder{
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+				ClientID:       testOIDCClientId,
+				TenantID:       testOIDCTenantId,
+				Environment:    "public",
+			},
+		},
+		{
+			Name: "Missing Token",
+			Auth: OIDCAuth{RequestURL: "https://example.com"},
+			Builder: authentication.Builder{
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+				ClientID:       testOIDCClientId,
+				TenantID:       testOIDCTenantId,
+			},
+			Error: true,
+		},
+		{
+			Name: "Missing Client ID",
+			Auth: OIDCAuth{Token: testOIDCToken},
+			Builder: authentication.Builder{
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+				TenantID:       testOIDCTenantId,
+			},
+			Error: true,
+		},
+		{
+			Name: "Auxiliary Tenants",
+			Auth: OIDCAuth{Token: testOIDCToken},
+			Builder: authentication.Builder{
+				SubscriptionID:     "00000000-0000-0000-0000-000000000000",
+				ClientID:           testOIDCClientId,
+				TenantID:           testOIDCTenantId,
+				AuxiliaryTenantIDs: []string{"33333333-3333-3333-3333-333333333333"},
+			},
+			Error: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			config, err := testCase.Auth.BuildConfig(testCase.Builder)
+			if err != nil {
+				if testCase.Error {
+					return
+				}
+				t.Fatalf("building config: %+v", err)
+			}
+			if testCase.Error {
+				t.Fatalf("expected an error but didn't get one")
+			}
+
+			if !config.AuthenticatedAsAServicePrincipal {
+				t.Fatalf("expected the config to authenticate as a Service Principal")
+			}
+			if config.ClientID != testCase.Builder.ClientID {
+				t.Fatalf("expected the Client ID %q but got %q", testCase.Builder.ClientID, config.ClientID)
+			}
+			if config.GetAuthenticatedObjectID == nil {
+				t.Fatalf("expected GetAuthenticatedObjectID to be set")
+			}
+		})
+	}
+}
+
+func TestGetAuthorizationTokenFunc(t *testing.T) {
+	endpoint, server := newFakeTokenEndpoint(t)
+
+	oauthConfig, err := adal.NewOAuthConfig(server.URL, testOIDCTenantId)
+	if err != nil {
+		t.Fatalf("building OAuth Config: %+v", err)
+	}
+
+	auth := OIDCAuth{Token: testOIDCToken}
+	oidcConfig, err := auth.BuildConfig(authentication.Builder{
+		SubscriptionID: "00000000-0000-0000-0000-000000000000",
+		ClientID:       testOIDCClientId,
+		TenantID:       testOIDCTenantId,
+		Environment:    "public",
+	})
+	if err != nil {
+		t.Fatalf("building OIDC config: %+v", err)
+	}
+
+	// the Config built for OIDC has no authentication method, so can't be used without the OIDCAuth
+	if _, err := getAuthorizationTokenFunc(*oidcConfig, true, nil); err == nil {
+		t.Fatalf("expected an error when the OIDC configuration isn't specified but didn't get one")
+	}
+
+	getAuthorizationToken, err := getAuthorizationTokenFunc(*oidcConfig, true, &auth)
+	if err != nil {
+		t.Fatalf("building Authorization Token func: %+v", err)
+	}
+	resource := "https://management.azure.com/"
+	authorizer, err := getAuthorizationToken(http.DefaultClient, &authentication.OAuthConfig{OAuth: oauthConfig}, resource)
+	if err != nil {
+		t.Fatalf("building Authorizer: %+v", err)
+	}
+	if v := authorizationHeader(t, authorizer); v != fmt.Sprintf("Bearer access-token-for-%s", resource) {
+		t.Fatalf("unexpected Authorization header: %q", v)
+	}
+	if v := endpoint.assertions[resource]; v != testOIDCToken {
+		t.Fatalf("expected the Client Assertion to be %q but got %q", testOIDCToken, v)
+	}
+
+	// whereas a Config built using the authentication.Builder uses its own authentication method
+	secretConfig, err := authentication.Builder{
+		SubscriptionID:           "00000000-0000-0000-0000-000000000000",
+		ClientID:                 testOIDCClientId,
+		ClientSecret:             "secret",
+		TenantID:                 testOIDCTenantId,
+		Environment:              "public",
+		SupportsClientSecretAuth: true,
+	}.Build()
+	if err != nil {
+		t.Fatalf("building client secret config: %+v", err)
+	}
+	if _, err := getAuthorizationTokenFunc(*secretConfig, false, nil); err != nil {
+		t.Fatalf("building Authorization Token func for a client secret: %+v", err)
+	}
+}
+
+func authorizationHeader(t *testing.T, authorizer autorest.Authorizer) string {
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("building request: %+v", err)
+	}
+
+	req, err = autorest.Prepare(req, authorizer.WithAuthorization())
+	if err != nil {
+		t.Fatalf("preparing request: %+v", err)
+	}
+
+	return req.Header.Get("Authorization")
+}
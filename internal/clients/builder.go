@@ -6,6 +6,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/profiles/2017-03-09/resources/mgmt/resources"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/hashicorp/go-azure-helpers/authentication"
@@ -14,6 +15,7 @@ import (
 	"github.com/hashicorp/terraform-provider-azurerm/internal/features"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/location"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/resourceproviders"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tags"
 )
 
 type ClientBuilder struct {
@@ -23,9 +25,32 @@ type ClientBuilder struct {
 	DisableTerraformPartnerID   bool
 	PartnerId                   string
 	SkipProviderRegistration    bool
+
+	// ResourceProvidersToRegister are the Resource Providers which are registered up-front, others
+	// are registered the first time they're used (unless SkipProviderRegistration is set)
+	ResourceProvidersToRegister map[string]struct{}
 	StorageUseAzureAD           bool
 	TerraformVersion            string
 	Features                    features.UserFeatures
+	RateLimit                   *common.RateLimitOptions
+	Retry                       *common.RetryOptions
+	AuditLog                    *common.AuditLogOptions
+
+	// DefaultTags are the Tags which should be applied to every Resource, unless overridden by the Resource
+	DefaultTags map[string]string
+
+	// IgnoreTags are the Tags which should be ignored on every Resource
+	IgnoreTags tags.IgnoreTags
+
+	// UseOIDC specifies that the AuthConfig was built using OIDCAuth.BuildConfig, in which case OIDC must be set
+	UseOIDC bool
+
+	// OIDC configures authenticating as a Service Principal using an OIDC token
+	OIDC *OIDCAuth
+
+	// SendDecorators are applied to every request, including those used to obtain tokens - this
+	// is intentionally not exposed in the provider block and is used to record/replay requests in tests
+	SendDecorators []autorest.SendDecorator
 }
 
 const azureStackEnvironmentError = `
@@ -38,6 +63,11 @@ and API's available in Azure Stack via Azure Stack Profiles.
 `
 
 func Build(ctx context.Context, builder ClientBuilder) (*Client, error) {
+	getAuthorizationToken, err := getAuthorizationTokenFunc(*builder.AuthConfig, builder.UseOIDC, builder.OIDC)
+	if err != nil {
+		return nil, fmt.Errorf("building AzureRM Client: %+v", err)
+	}
+
 	// point folks towards the separate Azure Stack Provider when using Azure Stack
 	if strings.EqualFold(builder.AuthConfig.Environment, "AZURESTACKCLOUD") {
 		return nil, fmt.Errorf(azureStackEnvironmentError)
@@ -56,12 +86,21 @@ func Build(ctx context.Context, builder ClientBuilder) (*Client, error) {
 		return nil, fmt.Errorf("unable to find environment %q from endpoint %q: %+v", builder.AuthConfig.Environment, builder.AuthConfig.MetadataHost, err)
 	}
 
+	authConfig := *builder.AuthConfig
+	if len(builder.SendDecorators) > 0 {
+		// the Object ID is looked up using a separate HTTP client which the SendDecorators can't be applied to
+		log.Printf("[DEBUG] Skipping looking up the Authenticated Object ID since custom Send Decorators are configured")
+		authConfig.GetAuthenticatedObjectID = nil
+	}
+
 	// client declarations:
-	account, err := NewResourceManagerAccount(ctx, *builder.AuthConfig, *env, builder.SkipProviderRegistration)
+	account, err := NewResourceManagerAccount(ctx, authConfig, *env, builder.SkipProviderRegistration)
 	if err != nil {
 		return nil, fmt.Errorf("Error building account: %+v", err)
 	}
 
+	account.ResourceProvidersToRegister = builder.ResourceProvidersToRegister
+
 	client := Client{
 		Account: account,
 	}
@@ -76,24 +115,34 @@ func Build(ctx context.Context, builder ClientBuilder) (*Client, error) {
 		return nil, fmt.Errorf("unable to configure OAuthConfig for tenant %s", builder.AuthConfig.TenantID)
 	}
 
-	sender := sender.BuildSender("AzureRM")
+	sender := autorest.DecorateSender(sender.BuildSender("AzureRM"), builder.SendDecorators...)
+
+	// tokens are cached for each audience and refreshed in the background before they expire, so
+	// that long-running operations don't fail when the token expires part way through
+	tokenManager := common.NewTokenManager()
+	tokenManager.Start(ctx)
+	managedAuthorizer := func(endpoint string) (autorest.Authorizer, error) {
+		return tokenManager.Authorizer(builder.AuthConfig.TenantID, endpoint, func() (autorest.Authorizer, error) {
+			return getAuthorizationToken(sender, oauthConfig, endpoint)
+		})
+	}
 
 	// Resource Manager endpoints
 	endpoint := env.ResourceManagerEndpoint
-	auth, err := builder.AuthConfig.GetAuthorizationToken(sender, oauthConfig, env.TokenAudience)
+	auth, err := managedAuthorizer(env.TokenAudience)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get authorization token for resource manager: %+v", err)
 	}
 
 	// Graph Endpoints
 	graphEndpoint := env.GraphEndpoint
-	graphAuth, err := builder.AuthConfig.GetAuthorizationToken(sender, oauthConfig, graphEndpoint)
+	graphAuth, err := managedAuthorizer(graphEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get authorization token for graph endpoints: %+v", err)
 	}
 
 	// Storage Endpoints
-	storageAuth, err := builder.AuthConfig.GetAuthorizationToken(sender, oauthConfig, env.ResourceIdentifiers.Storage)
+	storageAuth, err := managedAuthorizer(env.ResourceIdentifiers.Storage)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get authorization token for storage endpoints: %+v", err)
 	}
@@ -101,7 +150,7 @@ func Build(ctx context.Context, builder ClientBuilder) (*Client, error) {
 	// Synapse Endpoints
 	var synapseAuth autorest.Authorizer = nil
 	if env.ResourceIdentifiers.Synapse != azure.NotAvailable {
-		synapseAuth, err = builder.AuthConfig.GetAuthorizationToken(sender, oauthConfig, env.ResourceIdentifiers.Synapse)
+		synapseAuth, err = managedAuthorizer(env.ResourceIdentifiers.Synapse)
 		if err != nil {
 			return nil, fmt.Errorf("unable to get authorization token for synapse endpoints: %+v", err)
 		}
@@ -110,10 +159,10 @@ func Build(ctx context.Context, builder ClientBuilder) (*Client, error) {
 	}
 
 	// Key Vault Endpoints
-	keyVaultAuth := builder.AuthConfig.BearerAuthorizerCallback(sender, oauthConfig)
+	keyVaultAuth := bearerAuthorizerCallback(ctx, sender, oauthConfig, builder.AuthConfig.TenantID, tokenManager, getAuthorizationToken)
 
 	// Batch Management Endpoints
-	batchManagementAuth, err := builder.AuthConfig.GetAuthorizationToken(sender, oauthConfig, env.BatchManagementEndpoint)
+	batchManagementAuth, err := managedAuthorizer(env.BatchManagementEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get authorization token for batch management endpoint: %+v", err)
 	}
@@ -131,13 +180,48 @@ func Build(ctx context.Context, builder ClientBuilder) (*Client, error) {
 		StorageAuthorizer:           storageAuth,
 		SynapseAuthorizer:           synapseAuth,
 		BatchManagementAuthorizer:   batchManagementAuth,
+		TokenManager:                tokenManager,
 		SkipProviderReg:             builder.SkipProviderRegistration,
 		DisableCorrelationRequestID: builder.DisableCorrelationRequestID,
 		CustomCorrelationRequestID:  builder.CustomCorrelationRequestID,
 		DisableTerraformPartnerID:   builder.DisableTerraformPartnerID,
 		Environment:                 *env,
 		Features:                    builder.Features,
+		DefaultTags:                 builder.DefaultTags,
+		IgnoreTags:                  builder.IgnoreTags,
 		StorageUseAzureAD:           builder.StorageUseAzureAD,
+		Retry:                       builder.Retry,
+		SendDecorators:              builder.SendDecorators,
+	}
+
+	if builder.RateLimit != nil {
+		o.RateLimiter = common.NewRateLimiter(*builder.RateLimit)
+	}
+
+	if builder.AuditLog != nil {
+		auditLog, err := common.NewAuditLog(*builder.AuditLog)
+		if err != nil {
+			return nil, fmt.Errorf("building Audit Log: %+v", err)
+		}
+		o.AuditLog = auditLog
+
+		// the Audit Log is closed once the Provider is stopped
+		go func() {
+			<-ctx.Done()
+			if err := auditLog.Close(); err != nil {
+				log.Printf("[DEBUG] Closing the Audit Log: %+v", err)
+			}
+		}()
+	}
+
+	if !builder.SkipProviderRegistration {
+		// the client used to register Resource Providers uses these options, but can't itself register them
+		registrationOptions := *o
+		o.ResourceProviderRegistrar = common.NewResourceProviderRegistrar(func(ctx context.Context, subscriptionId, namespace string) error {
+			providersClient := resources.NewProvidersClientWithBaseURI(endpoint, subscriptionId)
+			registrationOptions.ConfigureClient(&providersClient.Client, auth)
+			return resourceproviders.RegisterAndWait(ctx, providersClient, subscriptionId, namespace)
+		})
 	}
 
 	if err := client.Build(ctx, o); err != nil {
@@ -145,8 +229,20 @@ func Build(ctx context.Context, builder ClientBuilder) (*Client, error) {
 	}
 
 	if features.EnhancedValidationEnabled() {
-		location.CacheSupportedLocations(ctx, env)
-		resourceproviders.CacheSupportedProviders(ctx, client.Resource.ProvidersClient)
+		if path := features.EnhancedValidationSnapshotPath(); path != "" {
+			// the snapshot is used in place of Azure, which may be unreachable (e.g. in air-gapped environments)
+			snapshot, err := features.LoadEnhancedValidationSnapshot(path)
+			if err != nil {
+				return nil, fmt.Errorf("loading Enhanced Validation Snapshot: %+v", err)
+			}
+			log.Printf("[DEBUG] Using the Enhanced Validation Snapshot %q (exported from the %q Environment)", path, snapshot.Environment)
+
+			location.CacheLocations(snapshot.Locations)
+			resourceproviders.CacheProviders(snapshot.ResourceProviders)
+		} else {
+			location.CacheSupportedLocations(ctx, env)
+			resourceproviders.CacheSupportedProviders(ctx, client.Resource.ProvidersClient)
+		}
 	}
 
 	return &client, nil
@@ -2,6 +2,7 @@ package clients
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/validation"
@@ -100,6 +101,7 @@ import (
 	trafficManager "github.com/hashicorp/terraform-provider-azurerm/internal/services/trafficmanager/client"
 	vmware "github.com/hashicorp/terraform-provider-azurerm/internal/services/vmware/client"
 	web "github.com/hashicorp/terraform-provider-azurerm/internal/services/web/client"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tags"
 )
 
 type Client struct {
@@ -109,6 +111,19 @@ type Client struct {
 	Account  *ResourceManagerAccount
 	Features features.UserFeatures
 
+	// DefaultTags are the Tags configured in the `default_tags` block of this Provider, which are merged
+	// into the top-level `tags` of each Resource
+	DefaultTags map[string]string
+
+	// IgnoreTags are the Tags configured in the `ignore_tags` block of this Provider, which are ignored on each Resource
+	IgnoreTags tags.IgnoreTags
+
+	// options are the ClientOptions used to build this Client, which are re-used to build Clients for other Subscriptions
+	options *common.ClientOptions
+
+	// subscriptions caches the Clients for other Subscriptions, see ForSubscription
+	subscriptions *subscriptionClients
+
 	Advisor               *advisor.Client
 	AnalysisServices      *analysisServices.Client
 	ApiManagement         *apiManagement.Client
@@ -212,8 +227,19 @@ func (client *Client) Build(ctx context.Context, o *common.ClientOptions) error
 	validation.Disabled = true
 
 	client.Features = o.Features
+	client.DefaultTags = o.DefaultTags
+	client.IgnoreTags = o.IgnoreTags
 	client.StopContext = ctx
 
+	client.options = o
+	if client.subscriptions == nil {
+		client.subscriptions = &subscriptionClients{
+			clients: map[string]*Client{
+				strings.ToLower(o.SubscriptionId): client,
+			},
+		}
+	}
+
 	client.Advisor = advisor.NewClient(o)
 	client.AnalysisServices = analysisServices.NewClient(o)
 	client.ApiManagement = apiManagement.NewClient(o)
@@ -0,0 +1,62 @@
+package clients
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// subscriptionClients caches the Clients used for Resources within other Subscriptions (which
+// are specified using the `subscription_id` field on a Resource) - keyed by Subscription ID
+type subscriptionClients struct {
+	lock    sync.Mutex
+	clients map[string]*Client
+}
+
+// ForSubscription returns a Client which sends requests to the specified Subscription, sharing
+// the authorizers (and other configuration) of this Client. Clients for other Subscriptions are
+// built on demand and cached, when no Subscription ID is specified this Client is returned.
+//
+// NOTE: Resource Providers aren't registered up-front in other Subscriptions, instead (unless Resource Provider
+// Registration is skipped) they're registered within that Subscription the first time they're used.
+func (client *Client) ForSubscription(subscriptionId string) (*Client, error) {
+	if subscriptionId == "" {
+		return client, nil
+	}
+	if client.Account != nil && strings.EqualFold(subscriptionId, client.Account.SubscriptionId) {
+		return client, nil
+	}
+
+	if client.options == nil || client.subscriptions == nil {
+		return nil, fmt.Errorf("building a Client for Subscription %q: the Client has not been built", subscriptionId)
+	}
+
+	client.subscriptions.lock.Lock()
+	defer client.subscriptions.lock.Unlock()
+
+	key := strings.ToLower(subscriptionId)
+	if existing, ok := client.subscriptions.clients[key]; ok {
+		return existing, nil
+	}
+
+	log.Printf("[DEBUG] Building a Client for Subscription %q..", subscriptionId)
+
+	options := *client.options
+	options.SubscriptionId = subscriptionId
+
+	account := *client.Account
+	account.SubscriptionId = subscriptionId
+
+	// all of the Clients share the same cache, so that a Client for each Subscription is only built once
+	subscriptionClient := Client{
+		Account:       &account,
+		subscriptions: client.subscriptions,
+	}
+	if err := subscriptionClient.Build(client.StopContext, &options); err != nil {
+		return nil, fmt.Errorf("building Client for Subscription %q: %+v", subscriptionId, err)
+	}
+	client.subscriptions.clients[key] = &subscriptionClient
+
+	return &subscriptionClient, nil
+}
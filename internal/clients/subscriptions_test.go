@@ -0,0 +1,163 @@
+package clients
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/common"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tags"
+)
+
+const (
+	testSubscriptionId      = "abcdef00-0000-0000-0000-000000000000"
+	testOtherSubscriptionId = "abcdef11-1111-1111-1111-111111111111"
+)
+
+func buildTestClient(t *testing.T) *Client {
+	options := &common.ClientOptions{
+		SubscriptionId:            testSubscriptionId,
+		TenantID:                  testOIDCTenantId,
+		Environment:               azure.PublicCloud,
+		ResourceManagerAuthorizer: autorest.NullAuthorizer{},
+		ResourceManagerEndpoint:   azure.PublicCloud.ResourceManagerEndpoint,
+		DefaultTags: map[string]string{
+			"env": "prod",
+		},
+		IgnoreTags: tags.IgnoreTags{
+			Keys: []string{"ms-resource-usage"},
+		},
+	}
+
+	client := Client{
+		Account: &ResourceManagerAccount{
+			SubscriptionId: testSubscriptionId,
+			TenantId:       testOIDCTenantId,
+		},
+	}
+	if err := client.Build(context.TODO(), options); err != nil {
+		t.Fatalf("building Client: %+v", err)
+	}
+
+	return &client
+}
+
+func TestForSubscriptionReturnsTheSameClient(t *testing.T) {
+	client := buildTestClient(t)
+
+	for _, subscriptionId := range []string{"", testSubscriptionId, "ABCDEF00-0000-0000-0000-000000000000"} {
+		t.Logf("[DEBUG] Testing %q..", subscriptionId)
+
+		actual, err := client.ForSubscription(subscriptionId)
+		if err != nil {
+			t.Fatalf("retrieving Client: %+v", err)
+		}
+		if actual != client {
+			t.Fatalf("expected the Client for the configured Subscription to be returned")
+		}
+	}
+}
+
+func TestForSubscriptionNotBuilt(t *testing.T) {
+	client := Client{
+		Account: &ResourceManagerAccount{
+			SubscriptionId: testSubscriptionId,
+		},
+	}
+
+	if _, err := client.ForSubscription(testOtherSubscriptionId); err == nil {
+		t.Fatalf("expected an error for a Client which hasn't been built but didn't get one")
+	}
+}
+
+func TestForSubscriptionBuildsAClient(t *testing.T) {
+	client := buildTestClient(t)
+
+	actual, err := client.ForSubscription(testOtherSubscriptionId)
+	if err != nil {
+		t.Fatalf("retrieving Client: %+v", err)
+	}
+	if actual == client {
+		t.Fatalf("expected a Client for the other Subscription but got the configured Client")
+	}
+
+	if actual.Account.SubscriptionId != testOtherSubscriptionId {
+		t.Fatalf("expected the Account's Subscription to be %q but got %q", testOtherSubscriptionId, actual.Account.SubscriptionId)
+	}
+	if actual.Account.TenantId != testOIDCTenantId {
+		t.Fatalf("expected the Account's Tenant to be %q but got %q", testOIDCTenantId, actual.Account.TenantId)
+	}
+	if v := actual.Authorization.RoleAssignmentsClient.SubscriptionID; v != testOtherSubscriptionId {
+		t.Fatalf("expected the Role Assignments Client to use the Subscription %q but got %q", testOtherSubscriptionId, v)
+	}
+	if v := actual.DefaultTags["env"]; v != "prod" {
+		t.Fatalf("expected the Default Tags to be shared with the Client for the other Subscription but got %+v", actual.DefaultTags)
+	}
+	if !actual.IgnoreTags.IsIgnored("ms-resource-usage") {
+		t.Fatalf("expected the ignored Tags to be shared with the Client for the other Subscription but got %+v", actual.IgnoreTags)
+	}
+
+	// the configured Client mustn't be changed
+	if client.Account.SubscriptionId != testSubscriptionId {
+		t.Fatalf("expected the configured Account's Subscription to be %q but got %q", testSubscriptionId, client.Account.SubscriptionId)
+	}
+	if v := client.Authorization.RoleAssignmentsClient.SubscriptionID; v != testSubscriptionId {
+		t.Fatalf("expected the configured Role Assignments Client to use the Subscription %q but got %q", testSubscriptionId, v)
+	}
+}
+
+func TestForSubscriptionCachesClients(t *testing.T) {
+	client := buildTestClient(t)
+
+	first, err := client.ForSubscription(testOtherSubscriptionId)
+	if err != nil {
+		t.Fatalf("retrieving Client: %+v", err)
+	}
+
+	// Subscription IDs are case-insensitive
+	second, err := client.ForSubscription("ABCDEF11-1111-1111-1111-111111111111")
+	if err != nil {
+		t.Fatalf("retrieving Client: %+v", err)
+	}
+	if first != second {
+		t.Fatalf("expected the cached Client for the other Subscription to be returned")
+	}
+
+	// the Clients for each Subscription share the same cache
+	original, err := first.ForSubscription(testSubscriptionId)
+	if err != nil {
+		t.Fatalf("retrieving Client: %+v", err)
+	}
+	if original != client {
+		t.Fatalf("expected the configured Client to be returned from the Client for the other Subscription")
+	}
+}
+
+func TestForSubscriptionConcurrently(t *testing.T) {
+	client := buildTestClient(t)
+
+	results := make([]*Client, 10)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			result, err := client.ForSubscription(testOtherSubscriptionId)
+			if err != nil {
+				t.Errorf("retrieving Client: %+v", err)
+				return
+			}
+			results[i] = result
+		}(i)
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		if result == nil || result != results[0] {
+			t.Fatalf("expected a single Client to be built for the other Subscription")
+		}
+	}
+}
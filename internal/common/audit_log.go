@@ -0,0 +1,314 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+const (
+	// headerRequestID is the header containing the ID assigned to the request by Azure
+	headerRequestID = "x-ms-request-id"
+
+	// auditLogMaxBodySize is the maximum size of a request/response body which is included in the Audit Log
+	auditLogMaxBodySize = 1024 * 1024
+
+	auditLogRedacted = "REDACTED"
+)
+
+// DefaultAuditLogRedactedFields are the (case-insensitive) JSON field names which are always redacted in the
+// Audit Log - a field is redacted when its name contains any of these values, for example `administratorLoginPassword`
+var DefaultAuditLogRedactedFields = []string{
+	"accesskey",
+	"accounttoken",
+	"certificate",
+	"connectionstring",
+	"credential",
+	"keys",
+	"password",
+	"primarykey",
+	"privatekey",
+	"sastoken",
+	"secondarykey",
+	"secret",
+	"sharedaccesskey",
+	"sharedkey",
+	"token",
+}
+
+// auditLogRedactedValues redacts sensitive values (SAS signatures and keys within connection strings) wherever
+// they occur within the URL or a string value in the body, regardless of the field name
+var auditLogRedactedValues = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)([?&]sig=)[^&"\s]+`),
+	regexp.MustCompile(`(?i)((?:AccountKey|SharedAccessKey|SharedAccessSignature|Password|Pwd)=)[^;"\s]+`),
+}
+
+// auditLogSensitiveValueEndpoints matches the URLs of endpoints which send/return a sensitive value within a `value`
+// field - for example the Key Vault (and Managed HSM) data plane, where this contains the value of a Secret
+var auditLogSensitiveValueEndpoints = regexp.MustCompile(`(?i)^https://[^/]+\.(?:vault|managedhsm)\.[^/]+/|/(?:listKeys|regenerateKey)(?:\?|$)`)
+
+// AuditLogOptions configures the Audit Log, which writes a JSON line for each HTTP request sent to Azure
+type AuditLogOptions struct {
+	// Path is the path to the file which the Audit Log is appended to
+	Path string
+
+	// RedactedFields are additional (case-insensitive) JSON field names which should be redacted,
+	// alongside the DefaultAuditLogRedactedFields
+	RedactedFields []string
+}
+
+// AuditLog writes a JSON line for each HTTP request/response, redacting any sensitive values
+type AuditLog struct {
+	lock   sync.Mutex
+	writer io.Writer
+
+	// file is the file which the Audit Log is written to, which is nil when writing to another io.Writer
+	file *os.File
+
+	redactedFields []string
+
+	// now is overridable for testing purposes
+	now func() time.Time
+}
+
+// auditLogEntry is the JSON line written for each HTTP request/response
+type auditLogEntry struct {
+	Time                 string          `json:"time"`
+	Method               string          `json:"method"`
+	URL                  string          `json:"url"`
+	StatusCode           int             `json:"status_code,omitempty"`
+	DurationMs           int64           `json:"duration_ms"`
+	CorrelationRequestID string          `json:"correlation_request_id,omitempty"`
+	RequestID            string          `json:"request_id,omitempty"`
+	RequestBody          json.RawMessage `json:"request_body,omitempty"`
+	ResponseBody         json.RawMessage `json:"response_body,omitempty"`
+	Error                string          `json:"error,omitempty"`
+}
+
+// NewAuditLog returns an AuditLog which appends to the file specified in the AuditLogOptions
+func NewAuditLog(options AuditLogOptions) (*AuditLog, error) {
+	file, err := os.OpenFile(options.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("opening Audit Log %q: %+v", options.Path, err)
+	}
+
+	auditLog := newAuditLog(file, options)
+	auditLog.file = file
+	return auditLog, nil
+}
+
+// Close closes the file which the Audit Log is written to, after which any further entries are discarded
+func (l *AuditLog) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.writer = ioutil.Discard
+	if l.file == nil {
+		return nil
+	}
+
+	file := l.file
+	l.file = nil
+	return file.Close()
+}
+
+func newAuditLog(writer io.Writer, options AuditLogOptions) *AuditLog {
+	redactedFields := make([]string, 0, len(DefaultAuditLogRedactedFields)+len(options.RedactedFields))
+	for _, v := range append(DefaultAuditLogRedactedFields, options.RedactedFields...) {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			redactedFields = append(redactedFields, v)
+		}
+	}
+
+	return &AuditLog{
+		writer:         writer,
+		redactedFields: redactedFields,
+		now:            time.Now,
+	}
+}
+
+func (l *AuditLog) write(entry auditLogEntry) {
+	line, err := marshalAuditLogJSON(entry)
+	if err != nil {
+		log.Printf("[DEBUG] Audit Log: marshalling entry for %s %s: %+v", entry.Method, entry.URL, err)
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if _, err := l.writer.Write(append(line, '\n')); err != nil {
+		log.Printf("[DEBUG] Audit Log: writing entry for %s %s: %+v", entry.Method, entry.URL, err)
+	}
+}
+
+// isRedactedField returns whether the value of the specified JSON field should be redacted
+func (l *AuditLog) isRedactedField(name string) bool {
+	name = strings.ToLower(name)
+	for _, v := range l.redactedFields {
+		if strings.Contains(name, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactString redacts any sensitive values (e.g. SAS signatures) within the specified string
+func (l *AuditLog) redactString(input string) string {
+	for _, r := range auditLogRedactedValues {
+		input = r.ReplaceAllString(input, "${1}"+auditLogRedacted)
+	}
+	return input
+}
+
+// redactValue redacts the sensitive fields within the specified JSON value - when redactValueFields is set any
+// string `value` fields are also redacted, since these contain sensitive values for some endpoints
+func (l *AuditLog) redactValue(input interface{}, redactValueFields bool) interface{} {
+	switch v := input.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if value != nil && l.isRedactedField(key) {
+				v[key] = auditLogRedacted
+				continue
+			}
+			if _, ok := value.(string); ok && redactValueFields && strings.EqualFold(key, "value") {
+				v[key] = auditLogRedacted
+				continue
+			}
+			v[key] = l.redactValue(value, redactValueFields)
+		}
+		return v
+
+	case []interface{}:
+		for i, value := range v {
+			v[i] = l.redactValue(value, redactValueFields)
+		}
+		return v
+
+	case string:
+		return l.redactString(v)
+	}
+
+	return input
+}
+
+// redactBody returns the redacted JSON body sent to/from the specified URL - non-JSON (and overly large) bodies are omitted
+func (l *AuditLog) redactBody(url, contentType string, body []byte) json.RawMessage {
+	if len(body) == 0 || len(body) > auditLogMaxBodySize || !strings.Contains(strings.ToLower(contentType), "json") {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil
+	}
+
+	redactValueFields := auditLogSensitiveValueEndpoints.MatchString(url)
+	redacted, err := marshalAuditLogJSON(l.redactValue(value, redactValueFields))
+	if err != nil {
+		return nil
+	}
+
+	return redacted
+}
+
+// marshalAuditLogJSON marshals the specified value without escaping HTML characters, so that URLs remain readable
+func marshalAuditLogJSON(input interface{}) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(input); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// readAuditLogBody reads the specified JSON body (up to the auditLogMaxBodySize) returning the contents and a replacement for
+// the (consumed) body - other (and overly large) bodies aren't included in the Audit Log, so are returned unread
+func readAuditLogBody(body io.ReadCloser, contentType string, contentLength int64) ([]byte, io.ReadCloser, error) {
+	if body == nil || body == http.NoBody {
+		return nil, body, nil
+	}
+	if !strings.Contains(strings.ToLower(contentType), "json") || contentLength > auditLogMaxBodySize {
+		return nil, body, nil
+	}
+
+	contents, err := ioutil.ReadAll(io.LimitReader(body, auditLogMaxBodySize+1))
+	if err != nil {
+		body.Close()
+		return nil, nil, err
+	}
+
+	// when the length isn't known up-front the body can exceed the limit, in which case the remainder is left unread
+	if len(contents) > auditLogMaxBodySize {
+		return nil, readCloser{
+			Reader: io.MultiReader(bytes.NewReader(contents), body),
+			Closer: body,
+		}, nil
+	}
+
+	body.Close()
+	return contents, ioutil.NopCloser(bytes.NewReader(contents)), nil
+}
+
+// readCloser combines an io.Reader with the io.Closer of the body it reads from
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// withAuditLog returns a SendDecorator which writes an entry to the Audit Log for each request
+func withAuditLog(auditLog *AuditLog) autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			requestBody, body, err := readAuditLogBody(r.Body, r.Header.Get("Content-Type"), r.ContentLength)
+			if err != nil {
+				return nil, fmt.Errorf("reading request body for the Audit Log: %+v", err)
+			}
+			r.Body = body
+
+			start := auditLog.now()
+			resp, sendErr := s.Do(r)
+
+			entry := auditLogEntry{
+				Time:                 start.UTC().Format(time.RFC3339Nano),
+				Method:               r.Method,
+				URL:                  auditLog.redactString(r.URL.String()),
+				DurationMs:           auditLog.now().Sub(start).Milliseconds(),
+				CorrelationRequestID: r.Header.Get(HeaderCorrelationRequestID),
+				RequestBody:          auditLog.redactBody(r.URL.String(), r.Header.Get("Content-Type"), requestBody),
+			}
+			if sendErr != nil {
+				entry.Error = auditLog.redactString(sendErr.Error())
+			}
+
+			if resp != nil {
+				entry.StatusCode = resp.StatusCode
+				entry.RequestID = resp.Header.Get(headerRequestID)
+
+				responseBody, body, err := readAuditLogBody(resp.Body, resp.Header.Get("Content-Type"), resp.ContentLength)
+				if err != nil {
+					return resp, fmt.Errorf("reading response body for the Audit Log: %+v", err)
+				}
+				resp.Body = body
+				entry.ResponseBody = auditLog.redactBody(r.URL.String(), resp.Header.Get("Content-Type"), responseBody)
+			}
+
+			auditLog.write(entry)
+
+			return resp, sendErr
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+func TestAuditLogRedactBody(t *testing.T) {
+	auditLog := newAuditLog(&bytes.Buffer{}, AuditLogOptions{
+		RedactedFields: []string{"customSensitiveField"},
+	})
+
+	testData := []struct {
+		name        string
+		url         string
+		contentType string
+		input       string
+		expected    string
+	}{
+		{
+			name:        "empty body",
+			contentType: "application/json",
+			input:       "",
+			expected:    "",
+		},
+		{
+			name:        "non-json body",
+			contentType: "text/plain",
+			input:       "password=abc123",
+			expected:    "",
+		},
+		{
+			name:        "invalid json body",
+			contentType: "application/json",
+			input:       "{",
+			expected:    "",
+		},
+		{
+			name:        "nothing to redact",
+			contentType: "application/json; charset=utf-8",
+			input:       `{"name":"example","properties":{"enabled":true}}`,
+			expected:    `{"name":"example","properties":{"enabled":true}}`,
+		},
+		{
+			name:        "passwords and keys",
+			contentType: "application/json",
+			input:       `{"properties":{"administratorLoginPassword":"abc123","keys":[{"keyName":"key1","value":"abc123"}],"primaryKey":"abc123","nullPassword":null}}`,
+			expected:    `{"properties":{"administratorLoginPassword":"REDACTED","keys":"REDACTED","nullPassword":null,"primaryKey":"REDACTED"}}`,
+		},
+		{
+			name:        "custom fields",
+			contentType: "application/json",
+			input:       `{"properties":{"CustomSensitiveFieldName":{"nested":true}}}`,
+			expected:    `{"properties":{"CustomSensitiveFieldName":"REDACTED"}}`,
+		},
+		{
+			name:        "sas tokens and connection strings",
+			contentType: "application/json",
+			input:       `{"uri":"https://example.blob.core.windows.net/c/b?sv=2019-12-12&sig=abc%2B123&se=2021","value":"DefaultEndpointsProtocol=https;AccountName=example;AccountKey=abc123;EndpointSuffix=core.windows.net"}`,
+			expected:    `{"uri":"https://example.blob.core.windows.net/c/b?sv=2019-12-12&sig=REDACTED&se=2021","value":"DefaultEndpointsProtocol=https;AccountName=example;AccountKey=REDACTED;EndpointSuffix=core.windows.net"}`,
+		},
+		{
+			name:        "key vault secret",
+			url:         "https://example.vault.azure.net/secrets/example/00000000000000000000000000000000?api-version=7.1",
+			contentType: "application/json; charset=utf-8",
+			input:       `{"value":"abc123","contentType":"text/plain","id":"https://example.vault.azure.net/secrets/example/00000000000000000000000000000000","attributes":{"enabled":true}}`,
+			expected:    `{"attributes":{"enabled":true},"contentType":"text/plain","id":"https://example.vault.azure.net/secrets/example/00000000000000000000000000000000","value":"REDACTED"}`,
+		},
+		{
+			name:        "key vault decrypted value",
+			url:         "https://example.vault.usgovcloudapi.net/keys/example/00000000000000000000000000000000/decrypt?api-version=7.1",
+			contentType: "application/json",
+			input:       `{"kid":"https://example.vault.usgovcloudapi.net/keys/example/00000000000000000000000000000000","value":"abc123"}`,
+			expected:    `{"kid":"https://example.vault.usgovcloudapi.net/keys/example/00000000000000000000000000000000","value":"REDACTED"}`,
+		},
+		{
+			name:        "key vault list",
+			url:         "https://example.vault.azure.net/secrets?api-version=7.1",
+			contentType: "application/json",
+			input:       `{"value":[{"id":"https://example.vault.azure.net/secrets/example"}],"nextLink":null}`,
+			expected:    `{"nextLink":null,"value":[{"id":"https://example.vault.azure.net/secrets/example"}]}`,
+		},
+		{
+			name:        "list keys",
+			url:         "https://management.azure.com/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/example/providers/Microsoft.Example/accounts/example/listKeys?api-version=2021-01-01",
+			contentType: "application/json",
+			input:       `{"value":[{"name":"key1","value":"abc123"}]}`,
+			expected:    `{"value":[{"name":"key1","value":"REDACTED"}]}`,
+		},
+		{
+			name:        "value outside of key vault",
+			url:         "https://management.azure.com/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/example/providers/Microsoft.Web/sites/example/config/appsettings?api-version=2021-01-01",
+			contentType: "application/json",
+			input:       `{"name":"example","value":"example"}`,
+			expected:    `{"name":"example","value":"example"}`,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		actual := string(auditLog.redactBody(v.url, v.contentType, []byte(v.input)))
+		if actual != v.expected {
+			t.Fatalf("expected %s but got %s", v.expected, actual)
+		}
+	}
+}
+
+func TestConfigureClientAuditLog(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if !strings.Contains(string(body), "abc123") {
+			t.Errorf("expected the request body to be sent unredacted but got %s", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(headerRequestID, "11111111-1111-1111-1111-111111111111")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"name":"example","properties":{"connectionString":"Server=example;Password=abc123"}}`))
+	}))
+	defer httpServer.Close()
+
+	buffer := &bytes.Buffer{}
+	auditLog := newAuditLog(buffer, AuditLogOptions{})
+
+	client := autorest.NewClientWithUserAgent("")
+	ClientOptions{
+		DisableCorrelationRequestID: true,
+		AuditLog:                    auditLog,
+	}.ConfigureClient(&client, nil)
+
+	req, _ := http.NewRequest(http.MethodPut, httpServer.URL+"/subscriptions/11111111-1111-1111-1111-111111111111?sig=abc123", strings.NewReader(`{"properties":{"password":"abc123"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderCorrelationRequestID, "22222222-2222-2222-2222-222222222222")
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		t.Fatalf("sending request: %+v", err)
+	}
+
+	// the response body is still available to the caller, unredacted
+	body, _ := ioutil.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "abc123") {
+		t.Fatalf("expected the response body to be returned unredacted but got %s", body)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line in the audit log but got %d", len(lines))
+	}
+	if strings.Contains(lines[0], "abc123") {
+		t.Fatalf("expected the audit log to be redacted but got %s", lines[0])
+	}
+
+	var entry auditLogEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("parsing audit log entry: %+v", err)
+	}
+	if entry.Method != http.MethodPut {
+		t.Fatalf("expected the method %q but got %q", http.MethodPut, entry.Method)
+	}
+	if entry.StatusCode != http.StatusCreated {
+		t.Fatalf("expected the status code %d but got %d", http.StatusCreated, entry.StatusCode)
+	}
+	if entry.CorrelationRequestID != "22222222-2222-2222-2222-222222222222" {
+		t.Fatalf("expected the correlation request id to be logged but got %q", entry.CorrelationRequestID)
+	}
+	if entry.RequestID != "11111111-1111-1111-1111-111111111111" {
+		t.Fatalf("expected the request id to be logged but got %q", entry.RequestID)
+	}
+	if expected := `{"properties":{"password":"REDACTED"}}`; string(entry.RequestBody) != expected {
+		t.Fatalf("expected the request body %s but got %s", expected, entry.RequestBody)
+	}
+	if expected := `{"name":"example","properties":{"connectionString":"REDACTED"}}`; string(entry.ResponseBody) != expected {
+		t.Fatalf("expected the response body %s but got %s", expected, entry.ResponseBody)
+	}
+}
+
+func TestAuditLogKeyVaultSecret(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	auditLog := newAuditLog(buffer, AuditLogOptions{})
+
+	sender := autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+			},
+			Body:    ioutil.NopCloser(strings.NewReader(`{"value":"abc123","id":"https://example.vault.azure.net/secrets/example/00000000000000000000000000000000"}`)),
+			Request: r,
+		}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodPut, "https://example.vault.azure.net/secrets/example?api-version=7.1", strings.NewReader(`{"value":"abc123"}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	resp, err := autorest.DecorateSender(sender, withAuditLog(auditLog)).Do(req)
+	if err != nil {
+		t.Fatalf("sending request: %+v", err)
+	}
+	resp.Body.Close()
+
+	line := strings.TrimSpace(buffer.String())
+	if strings.Contains(line, "abc123") {
+		t.Fatalf("expected the Key Vault Secret to be redacted in the audit log but got %s", line)
+	}
+
+	var entry auditLogEntry
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("parsing audit log entry: %+v", err)
+	}
+	if expected := `{"value":"REDACTED"}`; string(entry.RequestBody) != expected {
+		t.Fatalf("expected the request body %s but got %s", expected, entry.RequestBody)
+	}
+	if expected := `{"id":"https://example.vault.azure.net/secrets/example/00000000000000000000000000000000","value":"REDACTED"}`; string(entry.ResponseBody) != expected {
+		t.Fatalf("expected the response body %s but got %s", expected, entry.ResponseBody)
+	}
+}
+
+// trackedBody is a request/response body which records how much of it has been read and whether it's been closed
+type trackedBody struct {
+	reader io.Reader
+	read   int
+	closed bool
+}
+
+func (b *trackedBody) Read(p []byte) (int, error) {
+	n, err := b.reader.Read(p)
+	b.read += n
+	return n, err
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestAuditLogReadBody(t *testing.T) {
+	largeBody := fmt.Sprintf(`{"value":"%s"}`, strings.Repeat("a", auditLogMaxBodySize))
+
+	testData := []struct {
+		name          string
+		contentType   string
+		contentLength int64
+		input         string
+		expected      string
+		expectedRead  int
+	}{
+		{
+			name:          "json",
+			contentType:   "application/json; charset=utf-8",
+			contentLength: 18,
+			input:         `{"name":"example"}`,
+			expected:      `{"name":"example"}`,
+			expectedRead:  18,
+		},
+		{
+			name:          "json with unknown length",
+			contentType:   "application/json",
+			contentLength: -1,
+			input:         `{"name":"example"}`,
+			expected:      `{"name":"example"}`,
+			expectedRead:  18,
+		},
+		{
+			name:          "not json",
+			contentType:   "application/octet-stream",
+			contentLength: 7,
+			input:         "example",
+			expectedRead:  0,
+		},
+		{
+			name:          "json too large",
+			contentType:   "application/json",
+			contentLength: int64(len(largeBody)),
+			input:         largeBody,
+			expectedRead:  0,
+		},
+		{
+			name:          "json too large with unknown length",
+			contentType:   "application/json",
+			contentLength: -1,
+			input:         largeBody,
+			expectedRead:  auditLogMaxBodySize + 1,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		original := &trackedBody{reader: strings.NewReader(v.input)}
+		contents, body, err := readAuditLogBody(original, v.contentType, v.contentLength)
+		if err != nil {
+			t.Fatalf("reading body: %+v", err)
+		}
+		if string(contents) != v.expected {
+			t.Fatalf("expected the contents %q but got %q", v.expected, contents)
+		}
+		if original.read != v.expectedRead {
+			t.Fatalf("expected %d bytes to be read up-front but got %d", v.expectedRead, original.read)
+		}
+
+		// the body returned must still contain the full (original) body
+		remaining, err := ioutil.ReadAll(body)
+		if err != nil {
+			t.Fatalf("reading returned body: %+v", err)
+		}
+		if string(remaining) != v.input {
+			t.Fatalf("expected the returned body to contain the full body (%d bytes) but got %d bytes", len(v.input), len(remaining))
+		}
+		if err := body.Close(); err != nil {
+			t.Fatalf("closing returned body: %+v", err)
+		}
+		if !original.closed {
+			t.Fatalf("expected the original body to be closed")
+		}
+	}
+}
+
+func TestAuditLogClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditLog, err := NewAuditLog(AuditLogOptions{Path: path})
+	if err != nil {
+		t.Fatalf("building Audit Log: %+v", err)
+	}
+
+	auditLog.write(auditLogEntry{Method: http.MethodGet, URL: "https://example.com/first"})
+	if err := auditLog.Close(); err != nil {
+		t.Fatalf("closing Audit Log: %+v", err)
+	}
+	if auditLog.file != nil {
+		t.Fatalf("expected the file to be released once the Audit Log is closed")
+	}
+
+	// entries written once the Audit Log is closed are discarded, and closing it again is a no-op
+	auditLog.write(auditLogEntry{Method: http.MethodGet, URL: "https://example.com/second"})
+	if err := auditLog.Close(); err != nil {
+		t.Fatalf("closing Audit Log again: %+v", err)
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading Audit Log: %+v", err)
+	}
+	if !strings.Contains(string(contents), "/first") || strings.Contains(string(contents), "/second") {
+		t.Fatalf("expected only the entry written before closing the Audit Log but got %s", contents)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/go-azure-helpers/sender"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/meta"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/features"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tags"
 	"github.com/hashicorp/terraform-provider-azurerm/version"
 )
 
@@ -28,6 +29,9 @@ type ClientOptions struct {
 	SynapseAuthorizer         autorest.Authorizer
 	BatchManagementAuthorizer autorest.Authorizer
 
+	// TokenManager caches the tokens for each of the Authorizers above and refreshes them before they expire
+	TokenManager *TokenManager
+
 	SkipProviderReg             bool
 	CustomCorrelationRequestID  string
 	DisableCorrelationRequestID bool
@@ -35,14 +39,59 @@ type ClientOptions struct {
 	Environment                 azure.Environment
 	Features                    features.UserFeatures
 	StorageUseAzureAD           bool
+
+	// DefaultTags are the Tags which should be applied to every Resource, unless overridden by the Resource
+	DefaultTags map[string]string
+
+	// IgnoreTags are the Tags which should be ignored on every Resource
+	IgnoreTags tags.IgnoreTags
+
+	// Retry optionally configures how throttled/transient failures are retried, nil disables this
+	Retry *RetryOptions
+
+	// SendDecorators are applied to every request prior to any other decorators, this is
+	// intentionally not exposed in the provider block and is used to record/replay requests in tests
+	SendDecorators []autorest.SendDecorator
+
+	// RateLimiter optionally limits the rate of requests sent to Resource Manager, nil disables this
+	// NOTE: this is shared across all clients, since the ARM quota applies per Subscription
+	RateLimiter *RateLimiter
+
+	// ResourceProviderRegistrar optionally registers Resource Providers the first time they're used, nil disables this
+	ResourceProviderRegistrar *ResourceProviderRegistrar
+
+	// AuditLog optionally writes a (redacted) JSON line for each request/response, nil disables this
+	AuditLog *AuditLog
 }
 
 func (o ClientOptions) ConfigureClient(c *autorest.Client, authorizer autorest.Authorizer) {
 	setUserAgent(c, o.TerraformVersion, o.PartnerId, o.DisableTerraformPartnerID)
 
 	c.Authorizer = authorizer
-	c.Sender = sender.BuildSender("AzureRM")
-	c.SkipResourceProviderRegistration = o.SkipProviderReg
+	c.Sender = autorest.DecorateSender(sender.BuildSender("AzureRM"), o.SendDecorators...)
+	// the audit log is applied first, so that each attempt (including retries) is logged
+	if o.AuditLog != nil {
+		c.Sender = autorest.DecorateSender(c.Sender, withAuditLog(o.AuditLog))
+	}
+	if o.RateLimiter != nil {
+		c.Sender = autorest.DecorateSender(c.Sender, withRateLimiting(o.RateLimiter, o.ResourceManagerEndpoint))
+	}
+	// retries are applied after the rate limit, so that each attempt is subject to it
+	if o.Retry != nil {
+		c.Sender = autorest.DecorateSender(c.Sender, withRetries(*o.Retry))
+
+		// the SDK wraps each request in its own retries for the transient status codes (`DoRetryWithRegistration`
+		// and `DoRetryForStatusCodes`), which would multiply the attempts above and wait without the MaxDelay - so
+		// these decorators are replaced. This includes the SDK's automatic Resource Provider registration, which
+		// the ResourceProviderRegistrar below supersedes
+		c.SendDecorators = []autorest.SendDecorator{}
+	}
+	// Resource Providers are registered on-demand last, so that the request retried after registration is subject to the above
+	if o.ResourceProviderRegistrar != nil {
+		c.Sender = autorest.DecorateSender(c.Sender, withResourceProviderRegistration(o.ResourceProviderRegistrar, o.ResourceManagerEndpoint))
+	}
+	// the ResourceProviderRegistrar replaces the automatic registration built into the SDK, which returns unclear errors
+	c.SkipResourceProviderRegistration = o.SkipProviderReg || o.ResourceProviderRegistrar != nil
 	if !o.DisableCorrelationRequestID {
 		id := o.CustomCorrelationRequestID
 		if id == "" {
@@ -0,0 +1,175 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// RateLimitOptions configures the client-side rate limits applied to requests sent to
+// Azure Resource Manager, which are tracked separately for each Subscription - allowing
+// the Provider to throttle itself rather than exhausting the ARM quota and receiving 429's
+type RateLimitOptions struct {
+	// ReadsPerHour is the number of read (GET/HEAD) requests which can be sent per hour
+	ReadsPerHour int
+
+	// WritesPerHour is the number of write (PUT/PATCH/POST/DELETE) requests which can be sent per hour
+	WritesPerHour int
+
+	// Burst is the number of requests which can be sent in quick succession before being throttled
+	Burst int
+}
+
+// RateLimiter is a set of Token Buckets, keyed by Subscription and whether the request is a read or write
+type RateLimiter struct {
+	options RateLimitOptions
+
+	lock    sync.Mutex
+	buckets map[string]*tokenBucket
+
+	// now is overridable for testing purposes
+	now func() time.Time
+}
+
+// NewRateLimiter returns a RateLimiter using the specified RateLimitOptions
+func NewRateLimiter(options RateLimitOptions) *RateLimiter {
+	return &RateLimiter{
+		options: options,
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Wait blocks until a request of the specified method can be sent to the specified Subscription
+// (which can be empty for tenant-level requests), or until the Context is cancelled
+func (l *RateLimiter) Wait(ctx context.Context, subscriptionId string, method string) error {
+	kind := "read"
+	perHour := l.options.ReadsPerHour
+	if !isReadRequest(method) {
+		kind = "write"
+		perHour = l.options.WritesPerHour
+	}
+	if perHour <= 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf("%s/%s", strings.ToLower(subscriptionId), kind)
+	bucket := l.bucket(key, perHour)
+
+	waited := time.Duration(0)
+	for {
+		delay := bucket.take(l.now())
+		if delay == 0 {
+			if waited > 0 {
+				log.Printf("[DEBUG] Rate Limiter: waited %s to send a %s request for %q", waited, kind, subscriptionId)
+			}
+			return nil
+		}
+
+		select {
+		case <-time.After(delay):
+			waited += delay
+		case <-ctx.Done():
+			return fmt.Errorf("waiting %s for the client-side rate limit for %s requests for %q: %+v", waited, kind, subscriptionId, ctx.Err())
+		}
+	}
+}
+
+func (l *RateLimiter) bucket(key string, perHour int) *tokenBucket {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		capacity := float64(l.options.Burst)
+		if capacity < 1 {
+			capacity = 1
+		}
+		bucket = &tokenBucket{
+			capacity:        capacity,
+			tokens:          capacity,
+			refillPerSecond: float64(perHour) / time.Hour.Seconds(),
+			lastRefill:      l.now(),
+		}
+		l.buckets[key] = bucket
+	}
+	return bucket
+}
+
+type tokenBucket struct {
+	lock            sync.Mutex
+	capacity        float64
+	tokens          float64
+	refillPerSecond float64
+	lastRefill      time.Time
+}
+
+// take removes a token from the bucket if one is available and returns zero - otherwise
+// the duration until the next token becomes available is returned
+func (b *tokenBucket) take(now time.Time) time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if elapsed := now.Sub(b.lastRefill); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.refillPerSecond
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.lastRefill = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	delay := time.Duration((1 - b.tokens) / b.refillPerSecond * float64(time.Second))
+	if delay <= 0 {
+		delay = time.Millisecond
+	}
+	return delay
+}
+
+func isReadRequest(method string) bool {
+	return strings.EqualFold(method, http.MethodGet) || strings.EqualFold(method, http.MethodHead)
+}
+
+// subscriptionIdFromPath returns the Subscription ID from a Resource Manager URI, if present
+func subscriptionIdFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if strings.EqualFold(segments[i], "subscriptions") {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+// withRateLimiting returns a SendDecorator which waits for the RateLimiter prior to sending
+// requests to the Resource Manager endpoint - requests to other endpoints (e.g. data plane
+// API's) aren't subject to the ARM quota and are sent as-is
+func withRateLimiting(limiter *RateLimiter, resourceManagerEndpoint string) autorest.SendDecorator {
+	resourceManagerHost := ""
+	if u, err := url.Parse(resourceManagerEndpoint); err == nil {
+		resourceManagerHost = u.Host
+	}
+
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			if resourceManagerHost != "" && strings.EqualFold(r.URL.Host, resourceManagerHost) {
+				if err := limiter.Wait(r.Context(), subscriptionIdFromPath(r.URL.Path), r.Method); err != nil {
+					return nil, err
+				}
+			}
+
+			return s.Do(r)
+		})
+	}
+}
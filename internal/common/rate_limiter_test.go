@@ -0,0 +1,204 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+func TestRateLimiterBucketsAreIndependent(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(RateLimitOptions{
+		ReadsPerHour:  3600,
+		WritesPerHour: 3600,
+		Burst:         2,
+	})
+	limiter.now = func() time.Time {
+		return now
+	}
+
+	testData := []struct {
+		subscriptionId string
+		method         string
+		expectedDelay  bool
+	}{
+		{
+			subscriptionId: "11111111-1111-1111-1111-aaaaaaaaaaaa",
+			method:         http.MethodGet,
+		},
+		{
+			subscriptionId: "11111111-1111-1111-1111-aaaaaaaaaaaa",
+			method:         http.MethodHead,
+		},
+		{
+			// the read bucket for this subscription is now empty
+			subscriptionId: "11111111-1111-1111-1111-aaaaaaaaaaaa",
+			method:         http.MethodGet,
+			expectedDelay:  true,
+		},
+		{
+			// but writes are tracked separately
+			subscriptionId: "11111111-1111-1111-1111-aaaaaaaaaaaa",
+			method:         http.MethodPut,
+		},
+		{
+			// as are other subscriptions
+			subscriptionId: "22222222-2222-2222-2222-222222222222",
+			method:         http.MethodGet,
+		},
+		{
+			// and subscription ID's are case-insensitive
+			subscriptionId: "11111111-1111-1111-1111-AAAAAAAAAAAA",
+			method:         http.MethodDelete,
+		},
+		{
+			subscriptionId: "11111111-1111-1111-1111-aaaaaaaaaaaa",
+			method:         http.MethodPatch,
+			expectedDelay:  true,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %s for %q..", v.method, v.subscriptionId)
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		err := limiter.Wait(ctx, v.subscriptionId, v.method)
+		cancel()
+		if v.expectedDelay && err == nil {
+			t.Fatalf("expected the request to be throttled but it wasn't")
+		}
+		if !v.expectedDelay && err != nil {
+			t.Fatalf("expected the request not to be throttled but got: %+v", err)
+		}
+	}
+
+	// after a second a token is refilled
+	now = now.Add(time.Second)
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, "11111111-1111-1111-1111-aaaaaaaaaaaa", http.MethodGet); err != nil {
+		t.Fatalf("expected a token to have been refilled but got: %+v", err)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := &tokenBucket{
+		capacity:        2,
+		tokens:          2,
+		refillPerSecond: 0.5,
+		lastRefill:      now,
+	}
+
+	for i := 0; i < 2; i++ {
+		if delay := bucket.take(now); delay != 0 {
+			t.Fatalf("expected token %d to be available but got a delay of %s", i, delay)
+		}
+	}
+
+	if delay := bucket.take(now); delay != 2*time.Second {
+		t.Fatalf("expected a delay of 2s but got %s", delay)
+	}
+
+	now = now.Add(time.Second)
+	if delay := bucket.take(now); delay != time.Second {
+		t.Fatalf("expected a delay of 1s but got %s", delay)
+	}
+
+	// the bucket never exceeds its capacity
+	now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		if delay := bucket.take(now); delay != 0 {
+			t.Fatalf("expected token %d to be available but got a delay of %s", i, delay)
+		}
+	}
+	if delay := bucket.take(now); delay == 0 {
+		t.Fatalf("expected the bucket to be limited to its capacity")
+	}
+}
+
+func TestSubscriptionIdFromPath(t *testing.T) {
+	testData := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "/providers/Microsoft.Resources/operations",
+			expected: "",
+		},
+		{
+			input:    "/subscriptions/11111111-1111-1111-1111-111111111111",
+			expected: "11111111-1111-1111-1111-111111111111",
+		},
+		{
+			input:    "/Subscriptions/11111111-1111-1111-1111-111111111111/resourceGroups/example",
+			expected: "11111111-1111-1111-1111-111111111111",
+		},
+		{
+			input:    "/subscriptions/",
+			expected: "",
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.input)
+
+		if actual := subscriptionIdFromPath(v.input); actual != v.expected {
+			t.Fatalf("expected %q but got %q", v.expected, actual)
+		}
+	}
+}
+
+func TestConfigureClientRateLimiting(t *testing.T) {
+	server := &throttlingServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	limiter := NewRateLimiter(RateLimitOptions{
+		ReadsPerHour: 1,
+		Burst:        1,
+	})
+
+	testData := []struct {
+		name                    string
+		resourceManagerEndpoint string
+		expectedAttempts        int
+	}{
+		{
+			name:                    "other endpoints aren't rate limited",
+			resourceManagerEndpoint: "https://management.azure.com/",
+			expectedAttempts:        2,
+		},
+		{
+			name:                    "resource manager endpoint is rate limited",
+			resourceManagerEndpoint: httpServer.URL,
+			expectedAttempts:        3,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		client := autorest.NewClientWithUserAgent("")
+		ClientOptions{
+			DisableCorrelationRequestID: true,
+			ResourceManagerEndpoint:     v.resourceManagerEndpoint,
+			RateLimiter:                 limiter,
+		}.ConfigureClient(&client, nil)
+
+		for i := 0; i < 2; i++ {
+			ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+"/subscriptions/11111111-1111-1111-1111-111111111111", nil)
+			_, _ = autorest.SendWithSender(client, req)
+			cancel()
+		}
+
+		if actual := server.attempts(); actual != v.expectedAttempts {
+			t.Fatalf("expected %d requests to have been sent but got %d", v.expectedAttempts, actual)
+		}
+	}
+}
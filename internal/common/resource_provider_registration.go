@@ -0,0 +1,187 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// missingRegistrationNamespace matches the namespace within the message returned when a Resource Provider isn't registered,
+// e.g. "The subscription is not registered to use namespace 'Microsoft.Foo'. See https://aka.ms/rps-not-found for how to register subscriptions."
+var missingRegistrationNamespace = regexp.MustCompile(`namespace '([^']+)'`)
+
+// RegisterResourceProviderFunc registers the specified Resource Provider in the specified Subscription
+type RegisterResourceProviderFunc func(ctx context.Context, subscriptionId, namespace string) error
+
+// ResourceProviderRegistrar registers Resource Providers the first time they're used by a Resource (when they
+// aren't registered already) - rather than registering every Resource Provider which could be used, up-front
+type ResourceProviderRegistrar struct {
+	register RegisterResourceProviderFunc
+
+	lock          sync.Mutex
+	registrations map[string]*resourceProviderRegistration
+}
+
+// resourceProviderRegistration tracks the registration of a Resource Provider within a Subscription, so that
+// concurrent requests for the same Resource Provider only register it once
+type resourceProviderRegistration struct {
+	lock       sync.Mutex
+	registered bool
+}
+
+// NewResourceProviderRegistrar returns a ResourceProviderRegistrar which registers Resource Providers using the specified function
+func NewResourceProviderRegistrar(register RegisterResourceProviderFunc) *ResourceProviderRegistrar {
+	return &ResourceProviderRegistrar{
+		register:      register,
+		registrations: make(map[string]*resourceProviderRegistration),
+	}
+}
+
+// Register registers the specified Resource Provider in the specified Subscription, unless it's been registered already
+func (r *ResourceProviderRegistrar) Register(ctx context.Context, subscriptionId, namespace string) error {
+	key := strings.ToLower(fmt.Sprintf("%s/%s", subscriptionId, namespace))
+
+	r.lock.Lock()
+	registration, ok := r.registrations[key]
+	if !ok {
+		registration = &resourceProviderRegistration{}
+		r.registrations[key] = registration
+	}
+	r.lock.Unlock()
+
+	registration.lock.Lock()
+	defer registration.lock.Unlock()
+
+	if registration.registered {
+		return nil
+	}
+
+	// errors aren't cached, so that registration is retried (e.g. once permissions have been granted)
+	if err := r.register(ctx, subscriptionId, namespace); err != nil {
+		return err
+	}
+	registration.registered = true
+
+	return nil
+}
+
+// missingResourceProviderRegistration returns the namespace of the Resource Provider which requires registration,
+// if the response indicates the request failed since a Resource Provider isn't registered
+func missingResourceProviderRegistration(body []byte) string {
+	var response struct {
+		Error *struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Details []struct {
+				Target string `json:"target"`
+			} `json:"details"`
+		} `json:"error"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+
+	// some API's return the error at the top-level, rather than nested within `error`
+	code := response.Code
+	message := response.Message
+	if response.Error != nil {
+		code = response.Error.Code
+		message = response.Error.Message
+		if len(response.Error.Details) > 0 && response.Error.Details[0].Target != "" && strings.EqualFold(code, "MissingSubscriptionRegistration") {
+			return response.Error.Details[0].Target
+		}
+	}
+
+	if !strings.EqualFold(code, "MissingSubscriptionRegistration") {
+		return ""
+	}
+
+	if match := missingRegistrationNamespace.FindStringSubmatch(message); len(match) == 2 {
+		return match[1]
+	}
+
+	return ""
+}
+
+// withResourceProviderRegistration returns a SendDecorator which registers the Resource Provider (and then retries
+// the request) when a request to the Resource Manager endpoint fails since the Resource Provider isn't registered
+func withResourceProviderRegistration(registrar *ResourceProviderRegistrar, resourceManagerEndpoint string) autorest.SendDecorator {
+	resourceManagerHost := ""
+	if u, err := url.Parse(resourceManagerEndpoint); err == nil {
+		resourceManagerHost = u.Host
+	}
+
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			if resourceManagerHost == "" || !strings.EqualFold(r.URL.Host, resourceManagerHost) {
+				return s.Do(r)
+			}
+
+			// the request body is retained, so that the request can be retried once the Resource Provider is registered
+			requestBody, body, err := readBody(r.Body)
+			if err != nil {
+				return nil, fmt.Errorf("reading request body: %+v", err)
+			}
+			r.Body = body
+
+			resp, err := s.Do(r)
+			if err != nil || resp == nil || resp.StatusCode != http.StatusConflict {
+				return resp, err
+			}
+
+			responseBody, body, err := readBody(resp.Body)
+			if err != nil {
+				return resp, fmt.Errorf("reading response body: %+v", err)
+			}
+			resp.Body = body
+
+			namespace := missingResourceProviderRegistration(responseBody)
+			if namespace == "" {
+				return resp, nil
+			}
+
+			subscriptionId := subscriptionIdFromPath(r.URL.Path)
+			if subscriptionId == "" {
+				return resp, nil
+			}
+
+			log.Printf("[DEBUG] The Resource Provider %q isn't registered in Subscription %q - registering..", namespace, subscriptionId)
+			if err := registrar.Register(r.Context(), subscriptionId, namespace); err != nil {
+				return resp, err
+			}
+
+			if requestBody != nil {
+				r.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+			}
+			return s.Do(r)
+		})
+	}
+}
+
+// readBody reads the specified body, returning the contents and a replacement for the (consumed) body
+func readBody(body io.ReadCloser) ([]byte, io.ReadCloser, error) {
+	if body == nil || body == http.NoBody {
+		return nil, body, nil
+	}
+
+	contents, err := ioutil.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return contents, ioutil.NopCloser(bytes.NewReader(contents)), nil
+}
@@ -0,0 +1,192 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+func TestMissingResourceProviderRegistration(t *testing.T) {
+	testData := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "invalid json",
+			input:    "{",
+			expected: "",
+		},
+		{
+			name:     "other conflict",
+			input:    `{"error":{"code":"Conflict","message":"Another operation is in progress"}}`,
+			expected: "",
+		},
+		{
+			name:     "details target",
+			input:    `{"error":{"code":"MissingSubscriptionRegistration","message":"The subscription is not registered to use namespace 'Microsoft.Foo'.","details":[{"code":"MissingSubscriptionRegistration","target":"Microsoft.Bar"}]}}`,
+			expected: "Microsoft.Bar",
+		},
+		{
+			name:     "nested message",
+			input:    `{"error":{"code":"MissingSubscriptionRegistration","message":"The subscription is not registered to use namespace 'Microsoft.Foo'. See https://aka.ms/rps-not-found for how to register subscriptions."}}`,
+			expected: "Microsoft.Foo",
+		},
+		{
+			name:     "top-level message",
+			input:    `{"code":"MissingSubscriptionRegistration","message":"The subscription is not registered to use namespace 'Microsoft.Foo'."}`,
+			expected: "Microsoft.Foo",
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		if actual := missingResourceProviderRegistration([]byte(v.input)); actual != v.expected {
+			t.Fatalf("expected %q but got %q", v.expected, actual)
+		}
+	}
+}
+
+func TestResourceProviderRegistrarRegistersOnce(t *testing.T) {
+	var lock sync.Mutex
+	registrations := 0
+	registrar := NewResourceProviderRegistrar(func(ctx context.Context, subscriptionId, namespace string) error {
+		lock.Lock()
+		defer lock.Unlock()
+		registrations++
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := registrar.Register(context.TODO(), "11111111-1111-1111-1111-111111111111", "Microsoft.Foo"); err != nil {
+				t.Errorf("registering: %+v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// namespaces are case-insensitive
+	if err := registrar.Register(context.TODO(), "11111111-1111-1111-1111-111111111111", "microsoft.foo"); err != nil {
+		t.Fatalf("registering: %+v", err)
+	}
+	if registrations != 1 {
+		t.Fatalf("expected 1 registration but got %d", registrations)
+	}
+
+	// but each Subscription is registered separately
+	if err := registrar.Register(context.TODO(), "22222222-2222-2222-2222-222222222222", "Microsoft.Foo"); err != nil {
+		t.Fatalf("registering: %+v", err)
+	}
+	if registrations != 2 {
+		t.Fatalf("expected 2 registrations but got %d", registrations)
+	}
+}
+
+// unregisteredProviderServer returns a MissingSubscriptionRegistration error until the Resource Provider is registered
+type unregisteredProviderServer struct {
+	lock       sync.Mutex
+	registered bool
+	bodies     []string
+}
+
+func (s *unregisteredProviderServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	s.bodies = append(s.bodies, string(body))
+
+	if !s.registered {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error":{"code":"MissingSubscriptionRegistration","message":"The subscription is not registered to use namespace 'Microsoft.Foo'."}}`))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestConfigureClientResourceProviderRegistration(t *testing.T) {
+	server := &unregisteredProviderServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	testData := []struct {
+		name           string
+		registerErr    error
+		expectedStatus int
+		expectedError  bool
+	}{
+		{
+			name:          "registration denied",
+			registerErr:   fmt.Errorf("The Resource Provider \"Microsoft.Foo\" isn't registered"),
+			expectedError: true,
+		},
+		{
+			name:           "registered",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		server.bodies = nil
+		registrations := make([]string, 0)
+		registrar := NewResourceProviderRegistrar(func(ctx context.Context, subscriptionId, namespace string) error {
+			registrations = append(registrations, fmt.Sprintf("%s/%s", subscriptionId, namespace))
+			if v.registerErr != nil {
+				return v.registerErr
+			}
+			server.lock.Lock()
+			server.registered = true
+			server.lock.Unlock()
+			return nil
+		})
+
+		client := autorest.NewClientWithUserAgent("")
+		ClientOptions{
+			DisableCorrelationRequestID: true,
+			ResourceManagerEndpoint:     httpServer.URL,
+			ResourceProviderRegistrar:   registrar,
+		}.ConfigureClient(&client, nil)
+		if !client.SkipResourceProviderRegistration {
+			t.Fatalf("expected the SDK's Resource Provider Registration to be disabled")
+		}
+
+		req, _ := http.NewRequest(http.MethodPut, httpServer.URL+"/subscriptions/11111111-1111-1111-1111-111111111111/resourceGroups/example/providers/Microsoft.Foo/bars/example", strings.NewReader(`{"location":"westeurope"}`))
+		resp, err := autorest.SendWithSender(client, req)
+		if v.expectedError {
+			if err == nil || !strings.Contains(err.Error(), "isn't registered") {
+				t.Fatalf("expected the registration error but got %+v", err)
+			}
+		} else {
+			if err != nil {
+				t.Fatalf("sending request: %+v", err)
+			}
+			if resp.StatusCode != v.expectedStatus {
+				t.Fatalf("expected a %d but got %d", v.expectedStatus, resp.StatusCode)
+			}
+			// the request body is sent again when retrying
+			if len(server.bodies) != 2 || server.bodies[1] != `{"location":"westeurope"}` {
+				t.Fatalf("expected the request to be retried with the same body but got %+v", server.bodies)
+			}
+		}
+
+		if expected := []string{"11111111-1111-1111-1111-111111111111/Microsoft.Foo"}; len(registrations) != 1 || registrations[0] != expected[0] {
+			t.Fatalf("expected the registrations %+v but got %+v", expected, registrations)
+		}
+	}
+}
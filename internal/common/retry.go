@@ -0,0 +1,171 @@
+package common
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+const (
+	BackoffStrategyConstant    = "constant"
+	BackoffStrategyExponential = "exponential"
+	BackoffStrategyLinear      = "linear"
+)
+
+// BackoffFunc returns the duration to wait prior to the next attempt, where attempt
+// is the number of attempts made so far (starting at 1)
+type BackoffFunc func(attempt int, minDelay time.Duration, maxDelay time.Duration) time.Duration
+
+// BackoffStrategies is a map of the name of a Backoff Strategy to the BackoffFunc used to implement it
+var BackoffStrategies = map[string]BackoffFunc{
+	BackoffStrategyConstant:    ConstantBackoff,
+	BackoffStrategyExponential: ExponentialBackoff,
+	BackoffStrategyLinear:      LinearBackoff,
+}
+
+// PossibleBackoffStrategyValues returns the names of the available Backoff Strategies
+func PossibleBackoffStrategyValues() []string {
+	out := make([]string, 0)
+	for k := range BackoffStrategies {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ConstantBackoff waits for minDelay between each attempt
+func ConstantBackoff(_ int, minDelay time.Duration, _ time.Duration) time.Duration {
+	return minDelay
+}
+
+// LinearBackoff waits for minDelay multiplied by the number of attempts, up to maxDelay
+func LinearBackoff(attempt int, minDelay time.Duration, maxDelay time.Duration) time.Duration {
+	return capBackoff(minDelay*time.Duration(attempt), maxDelay)
+}
+
+// ExponentialBackoff doubles the delay after each attempt, starting at minDelay, up to maxDelay
+func ExponentialBackoff(attempt int, minDelay time.Duration, maxDelay time.Duration) time.Duration {
+	delay := minDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if maxDelay > 0 && delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return capBackoff(delay, maxDelay)
+}
+
+func capBackoff(delay time.Duration, maxDelay time.Duration) time.Duration {
+	if maxDelay > 0 && delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// RetryOptions configures how requests which are throttled (HTTP 429) or fail with a
+// transient error (HTTP 408 or 5xx) are retried
+type RetryOptions struct {
+	// MaxAttempts is the maximum number of times a request is sent, including the first attempt
+	MaxAttempts int
+
+	// Backoff determines how long to wait between attempts
+	Backoff BackoffFunc
+
+	// MinDelay and MaxDelay are the bounds passed to the Backoff
+	MinDelay time.Duration
+	MaxDelay time.Duration
+
+	// HonourRetryAfter specifies whether the duration within the `Retry-After` header
+	// returned by the API should be used in favour of the Backoff, when present (up to MaxDelay)
+	HonourRetryAfter bool
+}
+
+// statusCodesForRetry are the HTTP Status Codes which are considered to be transient
+var statusCodesForRetry = []int{
+	http.StatusRequestTimeout,      // 408
+	http.StatusTooManyRequests,     // 429
+	http.StatusInternalServerError, // 500
+	http.StatusBadGateway,          // 502
+	http.StatusServiceUnavailable,  // 503
+	http.StatusGatewayTimeout,      // 504
+}
+
+// withRetries returns a SendDecorator which retries requests that are throttled or fail
+// with a transient error according to the RetryOptions
+func withRetries(options RetryOptions) autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (resp *http.Response, err error) {
+			rr := autorest.NewRetriableRequest(r)
+			for attempt := 1; ; attempt++ {
+				if err = rr.Prepare(); err != nil {
+					return resp, err
+				}
+
+				resp, err = s.Do(rr.Request())
+				if err != nil || !autorest.ResponseHasStatusCode(resp, statusCodesForRetry...) || attempt >= options.MaxAttempts {
+					return resp, err
+				}
+
+				delay := options.delayFor(attempt, resp)
+				log.Printf("[DEBUG] %s %s returned %d (attempt %d of %d) - retrying in %s", r.Method, r.URL, resp.StatusCode, attempt, options.MaxAttempts, delay)
+
+				// the body needs to be drained and closed to allow the connection to be re-used
+				autorest.Respond(resp, autorest.ByDiscardingBody(), autorest.ByClosing())
+
+				select {
+				case <-time.After(delay):
+				case <-r.Context().Done():
+					return resp, fmt.Errorf("waiting to retry %s %s: %+v", r.Method, r.URL, r.Context().Err())
+				}
+			}
+		})
+	}
+}
+
+func (o RetryOptions) delayFor(attempt int, resp *http.Response) time.Duration {
+	// the `Retry-After` header is bounded by MaxDelay too, so that a misbehaving API can't
+	// cause a request to wait (almost) indefinitely
+	if o.HonourRetryAfter {
+		if delay, ok := retryAfter(resp); ok {
+			return capBackoff(delay, o.MaxDelay)
+		}
+	}
+
+	backoff := o.Backoff
+	if backoff == nil {
+		backoff = ExponentialBackoff
+	}
+	return backoff(attempt, o.MinDelay, o.MaxDelay)
+}
+
+// retryAfter returns the duration specified in the `Retry-After` header, which can
+// be either the number of seconds to wait or a date in RFC1123 format
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := time.Parse(time.RFC1123, v); err == nil {
+		delay := time.Until(t)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
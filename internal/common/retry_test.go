@@ -0,0 +1,468 @@
+package common
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+type throttlingServer struct {
+	// responses is a list of status codes returned for each request, after which
+	// a 200 OK is returned
+	responses  []int
+	retryAfter string
+
+	lock     sync.Mutex
+	requests []time.Time
+	bodies   []string
+}
+
+func (s *throttlingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	attempt := len(s.requests)
+	s.requests = append(s.requests, time.Now())
+	s.bodies = append(s.bodies, string(body))
+
+	if attempt < len(s.responses) {
+		if s.retryAfter != "" {
+			w.Header().Set("Retry-After", s.retryAfter)
+		}
+		w.WriteHeader(s.responses[attempt])
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *throttlingServer) attempts() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.requests)
+}
+
+func TestConfigureClientRetries(t *testing.T) {
+	testData := []struct {
+		name             string
+		responses        []int
+		retry            *RetryOptions
+		expectedStatus   int
+		expectedAttempts int
+	}{
+		{
+			name:             "retries disabled",
+			responses:        []int{http.StatusTooManyRequests},
+			retry:            nil,
+			expectedStatus:   http.StatusTooManyRequests,
+			expectedAttempts: 1,
+		},
+		{
+			name:      "throttled then succeeds",
+			responses: []int{http.StatusTooManyRequests, http.StatusTooManyRequests},
+			retry: &RetryOptions{
+				MaxAttempts: 5,
+				Backoff:     ConstantBackoff,
+				MinDelay:    time.Millisecond,
+			},
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 3,
+		},
+		{
+			name:      "transient server errors then succeeds",
+			responses: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+			retry: &RetryOptions{
+				MaxAttempts: 5,
+				Backoff:     ExponentialBackoff,
+				MinDelay:    time.Millisecond,
+				MaxDelay:    5 * time.Millisecond,
+			},
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 5,
+		},
+		{
+			name:      "exhausts the max attempts",
+			responses: []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests},
+			retry: &RetryOptions{
+				MaxAttempts: 2,
+				Backoff:     LinearBackoff,
+				MinDelay:    time.Millisecond,
+			},
+			expectedStatus:   http.StatusTooManyRequests,
+			expectedAttempts: 2,
+		},
+		{
+			name:      "non-transient errors aren't retried",
+			responses: []int{http.StatusBadRequest},
+			retry: &RetryOptions{
+				MaxAttempts: 5,
+				Backoff:     ConstantBackoff,
+				MinDelay:    time.Millisecond,
+			},
+			expectedStatus:   http.StatusBadRequest,
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		server := &throttlingServer{
+			responses: v.responses,
+		}
+		httpServer := httptest.NewServer(server)
+
+		client := autorest.NewClientWithUserAgent("")
+		ClientOptions{
+			DisableCorrelationRequestID: true,
+			Retry:                       v.retry,
+		}.ConfigureClient(&client, nil)
+
+		req, err := http.NewRequest(http.MethodPut, httpServer.URL, strings.NewReader(`{"hello":"world"}`))
+		if err != nil {
+			t.Fatalf("building request: %+v", err)
+		}
+		resp, err := autorest.SendWithSender(client, req)
+		httpServer.Close()
+		if err != nil {
+			t.Fatalf("sending request: %+v", err)
+		}
+
+		if resp.StatusCode != v.expectedStatus {
+			t.Fatalf("expected the status to be %d but got %d", v.expectedStatus, resp.StatusCode)
+		}
+		if actual := server.attempts(); actual != v.expectedAttempts {
+			t.Fatalf("expected %d attempts but got %d", v.expectedAttempts, actual)
+		}
+		for i, body := range server.bodies {
+			if body != `{"hello":"world"}` {
+				t.Fatalf("expected the body for attempt %d to be resent but got %q", i+1, body)
+			}
+		}
+	}
+}
+
+func TestConfigureClientRetriesHonoursRetryAfter(t *testing.T) {
+	server := &throttlingServer{
+		responses:  []int{http.StatusTooManyRequests},
+		retryAfter: "1",
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	client := autorest.NewClientWithUserAgent("")
+	ClientOptions{
+		DisableCorrelationRequestID: true,
+		Retry: &RetryOptions{
+			MaxAttempts:      2,
+			Backoff:          ConstantBackoff,
+			MinDelay:         time.Millisecond,
+			HonourRetryAfter: true,
+		},
+	}.ConfigureClient(&client, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, httpServer.URL, nil)
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		t.Fatalf("sending request: %+v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the status to be 200 but got %d", resp.StatusCode)
+	}
+
+	if len(server.requests) != 2 {
+		t.Fatalf("expected 2 attempts but got %d", len(server.requests))
+	}
+	if delay := server.requests[1].Sub(server.requests[0]); delay < time.Second {
+		t.Fatalf("expected the `Retry-After` header of 1s to be honoured but retried after %s", delay)
+	}
+}
+
+func TestConfigureClientRetriesCancelled(t *testing.T) {
+	server := &throttlingServer{
+		responses:  []int{http.StatusTooManyRequests},
+		retryAfter: "60",
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	client := autorest.NewClientWithUserAgent("")
+	ClientOptions{
+		DisableCorrelationRequestID: true,
+		Retry: &RetryOptions{
+			MaxAttempts:      2,
+			HonourRetryAfter: true,
+		},
+	}.ConfigureClient(&client, nil)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL, nil)
+	if _, err := autorest.SendWithSender(client, req); err == nil {
+		t.Fatalf("expected an error since the context was cancelled whilst waiting to retry but didn't get one")
+	}
+	if actual := server.attempts(); actual != 1 {
+		t.Fatalf("expected 1 attempt but got %d", actual)
+	}
+}
+
+func TestConfigureClientRetriesReplacesSDKRetries(t *testing.T) {
+	server := &throttlingServer{
+		responses: []int{
+			http.StatusServiceUnavailable,
+			http.StatusServiceUnavailable,
+			http.StatusServiceUnavailable,
+			http.StatusServiceUnavailable,
+			http.StatusServiceUnavailable,
+			http.StatusServiceUnavailable,
+			http.StatusServiceUnavailable,
+			http.StatusServiceUnavailable,
+		},
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	client := autorest.NewClientWithUserAgent("")
+	ClientOptions{
+		DisableCorrelationRequestID: true,
+		Retry: &RetryOptions{
+			MaxAttempts: 2,
+			Backoff:     ConstantBackoff,
+			MinDelay:    time.Millisecond,
+		},
+	}.ConfigureClient(&client, nil)
+
+	// this is how the SDK sends requests, which retries the transient status codes too
+	req, _ := http.NewRequest(http.MethodGet, httpServer.URL, nil)
+	resp, err := client.Send(req, azure.DoRetryWithRegistration(client))
+	if err != nil {
+		t.Fatalf("sending request: %+v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the status to be 503 but got %d", resp.StatusCode)
+	}
+	if actual := server.attempts(); actual != 2 {
+		t.Fatalf("expected 2 attempts but got %d", actual)
+	}
+}
+
+func TestConfigureClientRetriesCapsRetryAfterForSDKRequests(t *testing.T) {
+	server := &throttlingServer{
+		responses: []int{
+			http.StatusTooManyRequests,
+			http.StatusTooManyRequests,
+			http.StatusTooManyRequests,
+			http.StatusTooManyRequests,
+		},
+		retryAfter: "60",
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	client := autorest.NewClientWithUserAgent("")
+	ClientOptions{
+		DisableCorrelationRequestID: true,
+		Retry: &RetryOptions{
+			MaxAttempts:      3,
+			Backoff:          ConstantBackoff,
+			MinDelay:         time.Millisecond,
+			MaxDelay:         10 * time.Millisecond,
+			HonourRetryAfter: true,
+		},
+	}.ConfigureClient(&client, nil)
+
+	// the SDK's own retries would wait for the `Retry-After` of 60s, so a timeout ensures the cap applies to the whole call
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL, nil)
+	start := time.Now()
+	resp, err := client.Send(req, azure.DoRetryWithRegistration(client))
+	if err != nil {
+		t.Fatalf("sending request: %+v", err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected the status to be 429 but got %d", resp.StatusCode)
+	}
+	if actual := server.attempts(); actual != 3 {
+		t.Fatalf("expected 3 attempts but got %d", actual)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the `Retry-After` header to be capped at 10ms but the request took %s", elapsed)
+	}
+}
+
+func TestConfigureClientWithoutRetriesUsesSDKRetries(t *testing.T) {
+	client := autorest.NewClientWithUserAgent("")
+	ClientOptions{
+		DisableCorrelationRequestID: true,
+	}.ConfigureClient(&client, nil)
+	if client.RetryAttempts != autorest.DefaultRetryAttempts {
+		t.Fatalf("expected the SDK's Retry Attempts to be %d but got %d", autorest.DefaultRetryAttempts, client.RetryAttempts)
+	}
+}
+
+func TestRetryOptionsDelayFor(t *testing.T) {
+	testData := []struct {
+		name       string
+		retryAfter string
+		options    RetryOptions
+		expected   time.Duration
+	}{
+		{
+			name: "backoff",
+			options: RetryOptions{
+				Backoff:  ConstantBackoff,
+				MinDelay: 2 * time.Second,
+				MaxDelay: 10 * time.Second,
+			},
+			expected: 2 * time.Second,
+		},
+		{
+			name:       "retry after ignored",
+			retryAfter: "5",
+			options: RetryOptions{
+				Backoff:  ConstantBackoff,
+				MinDelay: 2 * time.Second,
+				MaxDelay: 10 * time.Second,
+			},
+			expected: 2 * time.Second,
+		},
+		{
+			name:       "retry after honoured",
+			retryAfter: "5",
+			options: RetryOptions{
+				Backoff:          ConstantBackoff,
+				MinDelay:         2 * time.Second,
+				MaxDelay:         10 * time.Second,
+				HonourRetryAfter: true,
+			},
+			expected: 5 * time.Second,
+		},
+		{
+			name:       "retry after capped at the max delay",
+			retryAfter: "3600",
+			options: RetryOptions{
+				Backoff:          ConstantBackoff,
+				MinDelay:         2 * time.Second,
+				MaxDelay:         10 * time.Second,
+				HonourRetryAfter: true,
+			},
+			expected: 10 * time.Second,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		resp := &http.Response{
+			Header: http.Header{},
+		}
+		if v.retryAfter != "" {
+			resp.Header.Set("Retry-After", v.retryAfter)
+		}
+
+		if actual := v.options.delayFor(1, resp); actual != v.expected {
+			t.Fatalf("expected %s but got %s", v.expected, actual)
+		}
+	}
+}
+
+func TestBackoffStrategies(t *testing.T) {
+	testData := []struct {
+		strategy string
+		attempt  int
+		expected time.Duration
+	}{
+		{
+			strategy: BackoffStrategyConstant,
+			attempt:  4,
+			expected: 2 * time.Second,
+		},
+		{
+			strategy: BackoffStrategyLinear,
+			attempt:  3,
+			expected: 6 * time.Second,
+		},
+		{
+			strategy: BackoffStrategyLinear,
+			attempt:  10,
+			expected: 10 * time.Second,
+		},
+		{
+			strategy: BackoffStrategyExponential,
+			attempt:  1,
+			expected: 2 * time.Second,
+		},
+		{
+			strategy: BackoffStrategyExponential,
+			attempt:  3,
+			expected: 8 * time.Second,
+		},
+		{
+			strategy: BackoffStrategyExponential,
+			attempt:  100,
+			expected: 10 * time.Second,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q (attempt %d)..", v.strategy, v.attempt)
+
+		actual := BackoffStrategies[v.strategy](v.attempt, 2*time.Second, 10*time.Second)
+		if actual != v.expected {
+			t.Fatalf("expected %s but got %s", v.expected, actual)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	testData := []struct {
+		input    string
+		expected time.Duration
+		ok       bool
+	}{
+		{
+			input: "",
+			ok:    false,
+		},
+		{
+			input: "invalid",
+			ok:    false,
+		},
+		{
+			input:    "30",
+			expected: 30 * time.Second,
+			ok:       true,
+		},
+		{
+			input:    "Mon, 02 Jan 2006 15:04:05 GMT",
+			expected: 0,
+			ok:       true,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.input)
+
+		resp := &http.Response{
+			Header: http.Header{},
+		}
+		resp.Header.Set("Retry-After", v.input)
+		actual, ok := retryAfter(resp)
+		if ok != v.ok {
+			t.Fatalf("expected ok to be %t but got %t", v.ok, ok)
+		}
+		if actual != v.expected {
+			t.Fatalf("expected %s but got %s", v.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+)
+
+const (
+	// tokenRefreshWithin is how long before a token expires that it should be refreshed
+	tokenRefreshWithin = 10 * time.Minute
+
+	// tokenRefreshJitter is the maximum random duration subtracted from the refresh time, so that
+	// the tokens for each audience aren't all refreshed at the same time
+	tokenRefreshJitter = 5 * time.Minute
+
+	// tokenRefreshInterval is how often tokens are checked in the background
+	tokenRefreshInterval = time.Minute
+)
+
+// TokenManager caches the Authorizers for each Tenant and Audience (e.g. Resource Manager, Graph, Storage) and
+// proactively refreshes their tokens before they expire - both when a request is sent and in the background, so
+// that long-running operations don't fail with an expired token. This is shared by all of the Authorizers.
+type TokenManager struct {
+	lock   sync.Mutex
+	tokens map[string]*managedToken
+
+	refreshWithin time.Duration
+	jitter        time.Duration
+
+	start sync.Once
+
+	// now is overridable for testing purposes
+	now func() time.Time
+}
+
+// refreshableToken is implemented by the token providers (e.g. adal.ServicePrincipalToken) which can be refreshed
+type refreshableToken interface {
+	adal.RefresherWithContext
+	Token() adal.Token
+}
+
+// managedToken is the Authorizer (and token, if it can be refreshed) for a Tenant and Audience
+type managedToken struct {
+	key        string
+	authorizer autorest.Authorizer
+
+	lock      sync.Mutex
+	refresher refreshableToken
+	refreshAt time.Time
+}
+
+// NewTokenManager returns a TokenManager which refreshes tokens between 10 and 15 minutes before they expire
+func NewTokenManager() *TokenManager {
+	return &TokenManager{
+		tokens:        make(map[string]*managedToken),
+		refreshWithin: tokenRefreshWithin,
+		jitter:        tokenRefreshJitter,
+		now:           time.Now,
+	}
+}
+
+// Authorizer returns the cached Authorizer for the specified Tenant and Audience, building it using
+// the specified function when it doesn't exist. Tokens are refreshed before each request when required.
+func (m *TokenManager) Authorizer(tenantId, audience string, build func() (autorest.Authorizer, error)) (autorest.Authorizer, error) {
+	token, err := m.token(tenantId, audience, build)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.refresher == nil {
+		return token.authorizer, nil
+	}
+
+	return &managedAuthorizer{
+		manager: m,
+		token:   token,
+	}, nil
+}
+
+// BearerAuthorizer returns the cached BearerAuthorizer for the specified Tenant and Audience, building it
+// using the specified function when it doesn't exist - refreshing the token first when required. This is
+// intended for use within a BearerAuthorizerCallback (e.g. for Key Vault) which is called for each request.
+func (m *TokenManager) BearerAuthorizer(ctx context.Context, tenantId, audience string, build func() (autorest.Authorizer, error)) (*autorest.BearerAuthorizer, error) {
+	token, err := m.token(tenantId, audience, build)
+	if err != nil {
+		return nil, err
+	}
+
+	bearer, ok := token.authorizer.(*autorest.BearerAuthorizer)
+	if !ok {
+		return nil, fmt.Errorf("converting %+v to a BearerAuthorizer", token.authorizer)
+	}
+
+	if err := m.ensureFresh(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return bearer, nil
+}
+
+// Start refreshes the tokens in the background until the Context is cancelled, this is only started once
+func (m *TokenManager) Start(ctx context.Context) {
+	m.start.Do(func() {
+		go func() {
+			ticker := time.NewTicker(tokenRefreshInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					m.refreshDue(ctx)
+				}
+			}
+		}()
+	})
+}
+
+// refreshDue refreshes any tokens which are due to be refreshed
+func (m *TokenManager) refreshDue(ctx context.Context) {
+	m.lock.Lock()
+	tokens := make([]*managedToken, 0, len(m.tokens))
+	for _, token := range m.tokens {
+		tokens = append(tokens, token)
+	}
+	m.lock.Unlock()
+
+	for _, token := range tokens {
+		if err := m.ensureFresh(ctx, token); err != nil {
+			// this is retried on the next request/interval, so isn't fatal here
+			log.Printf("[DEBUG] Refreshing token for %q in the background: %+v", token.key, err)
+		}
+	}
+}
+
+func (m *TokenManager) token(tenantId, audience string, build func() (autorest.Authorizer, error)) (*managedToken, error) {
+	key := fmt.Sprintf("%s/%s", strings.ToLower(tenantId), strings.TrimSuffix(strings.ToLower(audience), "/"))
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if existing, ok := m.tokens[key]; ok {
+		return existing, nil
+	}
+
+	authorizer, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	token := &managedToken{
+		key:        key,
+		authorizer: authorizer,
+	}
+	if bearer, ok := authorizer.(*autorest.BearerAuthorizer); ok {
+		if refresher, ok := bearer.TokenProvider().(refreshableToken); ok {
+			token.refresher = refresher
+			// tokens are obtained lazily, so this may be the zero value - in which case it's refreshed on first use
+			token.refreshAt = m.refreshAt(refresher.Token())
+		}
+	}
+	m.tokens[key] = token
+
+	return token, nil
+}
+
+// ensureFresh refreshes the token when it's due to be refreshed
+func (m *TokenManager) ensureFresh(ctx context.Context, token *managedToken) error {
+	if token.refresher == nil {
+		return nil
+	}
+
+	token.lock.Lock()
+	defer token.lock.Unlock()
+
+	if m.now().Before(token.refreshAt) {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Refreshing token for %q..", token.key)
+	if err := token.refresher.RefreshWithContext(ctx); err != nil {
+		return fmt.Errorf("refreshing token for %q: %+v", token.key, err)
+	}
+	token.refreshAt = m.refreshAt(token.refresher.Token())
+
+	return nil
+}
+
+// refreshAt returns when the specified token should be refreshed, which is before it expires (less some jitter)
+func (m *TokenManager) refreshAt(token adal.Token) time.Time {
+	if token.IsZero() {
+		return time.Time{}
+	}
+
+	now := m.now()
+	expires := token.Expires()
+
+	refreshWithin := m.refreshWithin
+	if m.jitter > 0 {
+		refreshWithin += time.Duration(rand.Int63n(int64(m.jitter))) // #nosec G404
+	}
+
+	// tokens with a shorter lifetime are refreshed half way through their lifetime
+	if lifetime := expires.Sub(now); lifetime < refreshWithin {
+		return now.Add(lifetime / 2)
+	}
+
+	return expires.Add(-refreshWithin)
+}
+
+// managedAuthorizer refreshes the token (when required) prior to authorizing each request
+type managedAuthorizer struct {
+	manager *TokenManager
+	token   *managedToken
+}
+
+func (a *managedAuthorizer) WithAuthorization() autorest.PrepareDecorator {
+	return func(p autorest.Preparer) autorest.Preparer {
+		return autorest.PreparerFunc(func(r *http.Request) (*http.Request, error) {
+			if err := a.manager.ensureFresh(r.Context(), a.token); err != nil {
+				return r, autorest.NewErrorWithError(err, "common.TokenManager", "WithAuthorization", nil, "Failed to refresh the Token for request to %s", r.URL)
+			}
+
+			return a.token.authorizer.WithAuthorization()(p).Prepare(r)
+		})
+	}
+}
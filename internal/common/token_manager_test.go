@@ -0,0 +1,247 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+)
+
+// fakeToken is a refreshableToken which issues a new token (valid for the specified lifetime) on each refresh
+type fakeToken struct {
+	now      func() time.Time
+	lifetime time.Duration
+
+	refreshes int
+	token     adal.Token
+}
+
+func (f *fakeToken) OAuthToken() string {
+	return f.token.AccessToken
+}
+
+func (f *fakeToken) Token() adal.Token {
+	return f.token
+}
+
+func (f *fakeToken) Refresh() error {
+	return f.RefreshWithContext(context.TODO())
+}
+
+func (f *fakeToken) RefreshWithContext(_ context.Context) error {
+	f.refreshes++
+	f.token = adal.Token{
+		AccessToken: fmt.Sprintf("token-%d", f.refreshes),
+		ExpiresOn:   json.Number(strconv.FormatInt(f.now().Add(f.lifetime).Unix(), 10)),
+	}
+	return nil
+}
+
+func (f *fakeToken) EnsureFreshWithContext(ctx context.Context) error {
+	if f.token.IsZero() || f.now().After(f.token.Expires()) {
+		return f.RefreshWithContext(ctx)
+	}
+	return nil
+}
+
+func (f *fakeToken) RefreshExchangeWithContext(ctx context.Context, _ string) error {
+	return f.RefreshWithContext(ctx)
+}
+
+func authorizationHeaderFor(t *testing.T, authorizer autorest.Authorizer) string {
+	req, err := http.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions", nil)
+	if err != nil {
+		t.Fatalf("building request: %+v", err)
+	}
+
+	req, err = autorest.Prepare(req, authorizer.WithAuthorization())
+	if err != nil {
+		t.Fatalf("preparing request: %+v", err)
+	}
+
+	return req.Header.Get("Authorization")
+}
+
+func TestTokenManagerCachesAuthorizers(t *testing.T) {
+	manager := NewTokenManager()
+
+	builds := 0
+	build := func() (autorest.Authorizer, error) {
+		builds++
+		return autorest.NewBearerAuthorizer(&fakeToken{now: time.Now, lifetime: time.Hour}), nil
+	}
+
+	testData := []struct {
+		tenantId string
+		audience string
+		builds   int
+	}{
+		{
+			tenantId: "11111111-1111-1111-1111-aaaaaaaaaaaa",
+			audience: "https://management.azure.com/",
+			builds:   1,
+		},
+		{
+			// the trailing slash and casing are ignored
+			tenantId: "11111111-1111-1111-1111-AAAAAAAAAAAA",
+			audience: "https://management.azure.com",
+			builds:   1,
+		},
+		{
+			tenantId: "11111111-1111-1111-1111-aaaaaaaaaaaa",
+			audience: "https://storage.azure.com/",
+			builds:   2,
+		},
+		{
+			tenantId: "22222222-2222-2222-2222-222222222222",
+			audience: "https://management.azure.com/",
+			builds:   3,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q for %q..", v.audience, v.tenantId)
+
+		if _, err := manager.Authorizer(v.tenantId, v.audience, build); err != nil {
+			t.Fatalf("building authorizer: %+v", err)
+		}
+		if builds != v.builds {
+			t.Fatalf("expected %d builds but got %d", v.builds, builds)
+		}
+	}
+}
+
+func TestTokenManagerRefreshesBeforeExpiry(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		return now
+	}
+
+	manager := NewTokenManager()
+	manager.now = clock
+	manager.jitter = 0
+
+	token := &fakeToken{now: clock, lifetime: time.Hour}
+	authorizer, err := manager.Authorizer("tenant", "https://management.azure.com/", func() (autorest.Authorizer, error) {
+		return autorest.NewBearerAuthorizer(token), nil
+	})
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+
+	testData := []struct {
+		elapsed  time.Duration
+		expected string
+	}{
+		{
+			// the token is obtained on first use
+			expected: "Bearer token-1",
+		},
+		{
+			elapsed:  49 * time.Minute,
+			expected: "Bearer token-1",
+		},
+		{
+			// within 10 minutes of expiry the token is refreshed
+			elapsed:  51 * time.Minute,
+			expected: "Bearer token-2",
+		},
+		{
+			elapsed:  51*time.Minute + 49*time.Minute,
+			expected: "Bearer token-2",
+		},
+		{
+			elapsed:  51*time.Minute + 51*time.Minute,
+			expected: "Bearer token-3",
+		},
+	}
+
+	start := now
+	for _, v := range testData {
+		now = start.Add(v.elapsed)
+		t.Logf("[DEBUG] Testing after %s..", v.elapsed)
+
+		if actual := authorizationHeaderFor(t, authorizer); actual != v.expected {
+			t.Fatalf("expected %q but got %q", v.expected, actual)
+		}
+	}
+}
+
+func TestTokenManagerRefreshDue(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		return now
+	}
+
+	manager := NewTokenManager()
+	manager.now = clock
+
+	token := &fakeToken{now: clock, lifetime: time.Hour}
+	authorizer, err := manager.Authorizer("tenant", "https://graph.windows.net/", func() (autorest.Authorizer, error) {
+		return autorest.NewBearerAuthorizer(token), nil
+	})
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	authorizationHeaderFor(t, authorizer)
+
+	// nothing is due yet
+	manager.refreshDue(context.TODO())
+	if token.refreshes != 1 {
+		t.Fatalf("expected 1 refresh but got %d", token.refreshes)
+	}
+
+	// the jitter means this is refreshed between 10 and 15 minutes before expiry
+	now = now.Add(50 * time.Minute)
+	manager.refreshDue(context.TODO())
+	if token.refreshes != 2 {
+		t.Fatalf("expected 2 refreshes but got %d", token.refreshes)
+	}
+}
+
+func TestTokenManagerShortLivedTokens(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	manager := NewTokenManager()
+	manager.now = func() time.Time {
+		return now
+	}
+
+	token := adal.Token{
+		AccessToken: "abc123",
+		ExpiresOn:   json.Number(strconv.FormatInt(now.Add(4*time.Minute).Unix(), 10)),
+	}
+	expected := now.Add(2 * time.Minute)
+	if actual := manager.refreshAt(token); !actual.Equal(expected) {
+		t.Fatalf("expected the token to be refreshed at %s but got %s", expected, actual)
+	}
+}
+
+func TestTokenManagerBearerAuthorizer(t *testing.T) {
+	manager := NewTokenManager()
+
+	builds := 0
+	build := func() (autorest.Authorizer, error) {
+		builds++
+		return autorest.NewBearerAuthorizer(&fakeToken{now: time.Now, lifetime: time.Hour}), nil
+	}
+
+	for i := 0; i < 3; i++ {
+		bearer, err := manager.BearerAuthorizer(context.TODO(), "tenant", "https://vault.azure.net", build)
+		if err != nil {
+			t.Fatalf("retrieving BearerAuthorizer: %+v", err)
+		}
+		if actual := bearer.TokenProvider().OAuthToken(); actual != "token-1" {
+			t.Fatalf("expected the token %q but got %q", "token-1", actual)
+		}
+	}
+
+	if builds != 1 {
+		t.Fatalf("expected 1 build but got %d", builds)
+	}
+}
@@ -13,6 +13,9 @@ import (
 //
 // This is enabled by default as of version 2.20 of the Azure Provider, and can be disabled by
 // setting the Environment Variable `ARM_PROVIDER_ENHANCED_VALIDATION` to `false`.
+//
+// When Azure is unreachable (for example in an air-gapped environment) the Locations and Resource
+// Providers can instead be loaded from a snapshot, see EnhancedValidationSnapshotPath.
 func EnhancedValidationEnabled() bool {
 	value := os.Getenv("ARM_PROVIDER_ENHANCED_VALIDATION")
 	if value == "" {
@@ -0,0 +1,69 @@
+package features
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// EnhancedValidationSnapshot contains the Locations and Resource Providers available within an Azure Environment,
+// which can be used for Enhanced Validation when the Azure MetaData Service/Resource Manager API is unreachable
+// (for example in an air-gapped environment) - this can be exported using `internal/tools/enhanced-validation-snapshot`.
+type EnhancedValidationSnapshot struct {
+	// Environment is the name of the Azure Environment this Snapshot was exported from, for informational purposes
+	Environment string `json:"environment"`
+
+	// Locations is a list of the Locations available within this Azure Environment
+	Locations []string `json:"locations"`
+
+	// ResourceProviders is a list of the Resource Providers available within this Azure Environment
+	ResourceProviders []string `json:"resourceProviders"`
+}
+
+// EnhancedValidationSnapshotPath returns the path to the Enhanced Validation Snapshot which should be used
+// rather than retrieving the Locations and Resource Providers from Azure, if any.
+//
+// This can be configured by setting the Environment Variable `ARM_PROVIDER_ENHANCED_VALIDATION_SNAPSHOT`
+// to the path of a JSON file containing an EnhancedValidationSnapshot.
+func EnhancedValidationSnapshotPath() string {
+	return os.Getenv("ARM_PROVIDER_ENHANCED_VALIDATION_SNAPSHOT")
+}
+
+// LoadEnhancedValidationSnapshot loads the Enhanced Validation Snapshot from the JSON file at the specified path
+func LoadEnhancedValidationSnapshot(path string) (*EnhancedValidationSnapshot, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading Enhanced Validation Snapshot from %q: %+v", path, err)
+	}
+
+	var snapshot EnhancedValidationSnapshot
+	if err := json.Unmarshal(contents, &snapshot); err != nil {
+		return nil, fmt.Errorf("parsing Enhanced Validation Snapshot from %q: %+v", path, err)
+	}
+
+	if len(snapshot.Locations) == 0 && len(snapshot.ResourceProviders) == 0 {
+		return nil, fmt.Errorf("the Enhanced Validation Snapshot %q contains no Locations or Resource Providers", path)
+	}
+
+	return &snapshot, nil
+}
+
+// Save writes the Enhanced Validation Snapshot as JSON to the specified path
+func (s EnhancedValidationSnapshot) Save(path string) error {
+	// sorting these means that Snapshots are stable, and so can be diff'd/checked in
+	sort.Strings(s.Locations)
+	sort.Strings(s.ResourceProviders)
+
+	contents, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serializing Enhanced Validation Snapshot: %+v", err)
+	}
+
+	if err := ioutil.WriteFile(path, append(contents, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing Enhanced Validation Snapshot to %q: %+v", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package features
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEnhancedValidationSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	snapshot := EnhancedValidationSnapshot{
+		Environment:       "usgovernment",
+		Locations:         []string{"usgovvirginia", "usgovarizona"},
+		ResourceProviders: []string{"Microsoft.Storage", "Microsoft.Compute"},
+	}
+	if err := snapshot.Save(path); err != nil {
+		t.Fatalf("saving snapshot: %+v", err)
+	}
+
+	actual, err := LoadEnhancedValidationSnapshot(path)
+	if err != nil {
+		t.Fatalf("loading snapshot: %+v", err)
+	}
+
+	expected := &EnhancedValidationSnapshot{
+		Environment:       "usgovernment",
+		Locations:         []string{"usgovarizona", "usgovvirginia"},
+		ResourceProviders: []string{"Microsoft.Compute", "Microsoft.Storage"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %+v but got %+v", expected, actual)
+	}
+}
+
+func TestLoadEnhancedValidationSnapshotInvalid(t *testing.T) {
+	testData := []struct {
+		name     string
+		contents string
+	}{
+		{
+			name:     "invalid json",
+			contents: "{",
+		},
+		{
+			name:     "empty",
+			contents: `{"environment": "public"}`,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		path := filepath.Join(t.TempDir(), "snapshot.json")
+		if err := ioutil.WriteFile(path, []byte(v.contents), 0644); err != nil {
+			t.Fatalf("writing snapshot: %+v", err)
+		}
+
+		if _, err := LoadEnhancedValidationSnapshot(path); err == nil {
+			t.Fatalf("expected an error but didn't get one")
+		}
+	}
+
+	if _, err := LoadEnhancedValidationSnapshot(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatalf("expected an error for a missing file but didn't get one")
+	}
+}
@@ -2,6 +2,7 @@ package location
 
 import (
 	"context"
+	"fmt"
 	"log"
 
 	"github.com/Azure/go-autorest/autorest/azure"
@@ -21,3 +22,29 @@ func CacheSupportedLocations(ctx context.Context, env *azure.Environment) {
 
 	supportedLocations = locs.Locations
 }
+
+// CacheLocations caches the specified Locations (for example from an Enhanced Validation Snapshot)
+// for use in enhanced validation, rather than retrieving them from the Azure MetaData Service
+func CacheLocations(locations []string) {
+	if len(locations) == 0 {
+		log.Printf("[DEBUG] no locations were specified. Enhanced validation will be unavailable")
+		return
+	}
+
+	supportedLocations = &locations
+}
+
+// AvailableLocations returns the Locations available in the specified Azure Environment, as
+// returned from the Azure MetaData Service - which can be used to build an Enhanced Validation Snapshot
+func AvailableLocations(ctx context.Context, env *azure.Environment) ([]string, error) {
+	locs, err := availableAzureLocations(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if locs.Locations == nil {
+		return nil, fmt.Errorf("the Azure MetaData Service didn't return any locations for %q", env.ResourceManagerEndpoint)
+	}
+
+	return *locs.Locations, nil
+}
@@ -176,6 +176,28 @@ func TestEnhancedValidationEnabled(t *testing.T) {
 	}
 }
 
+func TestEnhancedValidationCachedLocations(t *testing.T) {
+	enhancedEnabled = true
+	defer func() {
+		enhancedEnabled = features.EnhancedValidationEnabled()
+		supportedLocations = nil
+	}()
+
+	// an empty list means enhanced validation is unavailable
+	CacheLocations([]string{})
+	if supportedLocations != nil {
+		t.Fatalf("expected no locations to be cached")
+	}
+
+	CacheLocations([]string{"usgovvirginia", "usgovarizona"})
+	if _, errors := EnhancedValidate("US Gov Virginia", "location"); len(errors) > 0 {
+		t.Fatalf("expected %q to be valid but got %+v", "US Gov Virginia", errors)
+	}
+	if _, errors := EnhancedValidate("usgovvirgina", "location"); len(errors) == 0 {
+		t.Fatalf("expected %q to be invalid", "usgovvirgina")
+	}
+}
+
 var (
 	chinaLocations  = []string{"chinaeast", "chinanorth", "chinanorth2", "chinaeast2"}
 	publicLocations = []string{
@@ -1,5 +1,12 @@
 package locks
 
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
 // armMutexKV is the instance of MutexKV for ARM resources
 var armMutexKV = NewMutexKV()
 
@@ -7,20 +14,55 @@ func ByID(id string) {
 	armMutexKV.Lock(id)
 }
 
+// LockByIDCtx locks the specified ID, returning an error if the Context is cancelled
+// (for example when the operation times out) before the lock can be acquired, or if
+// acquiring the lock would deadlock. UnlockByID must be called if no error is returned.
+func LockByIDCtx(ctx context.Context, id string) error {
+	return armMutexKV.LockCtx(ctx, id)
+}
+
 // handle the case of using the same name for different kinds of resources
 func ByName(name string, resourceType string) {
 	updatedName := resourceType + "." + name
 	armMutexKV.Lock(updatedName)
 }
 
-func MultipleByName(names *[]string, resourceType string) {
-	newSlice := removeDuplicatesFromStringArray(*names)
+// LockByNameCtx is a context-aware version of ByName, see LockByIDCtx.
+// UnlockByName must be called if no error is returned.
+func LockByNameCtx(ctx context.Context, name string, resourceType string) error {
+	updatedName := resourceType + "." + name
+	return armMutexKV.LockCtx(ctx, updatedName)
+}
 
-	for _, name := range newSlice {
+// MultipleByName locks each of the specified names in a canonical (sorted) order, so
+// that callers locking an overlapping set of names can't deadlock one another
+func MultipleByName(names *[]string, resourceType string) {
+	for _, name := range canonicalNames(names) {
 		ByName(name, resourceType)
 	}
 }
 
+// LockMultipleByNameCtx is a context-aware version of MultipleByName, see LockByIDCtx.
+// If any of the locks can't be acquired, those already acquired are released prior to
+// returning an error. UnlockMultipleByName must be called if no error is returned.
+//
+// Each of the locks is acquired on behalf of the Owner specified in the Context (see
+// WithNewOwner) - or where the Context doesn't specify one, a new Owner for this call.
+func LockMultipleByNameCtx(ctx context.Context, names *[]string, resourceType string) error {
+	ctx = withOwnerIfUnset(ctx)
+	acquired := make([]string, 0)
+	for _, name := range canonicalNames(names) {
+		if err := LockByNameCtx(ctx, name, resourceType); err != nil {
+			UnlockMultipleByName(&acquired, resourceType)
+			return fmt.Errorf("locking %s %q: %+v", resourceType, name, err)
+		}
+
+		acquired = append(acquired, name)
+	}
+
+	return nil
+}
+
 func UnlockByID(id string) {
 	armMutexKV.Unlock(id)
 }
@@ -37,3 +79,25 @@ func UnlockMultipleByName(names *[]string, resourceType string) {
 		UnlockByName(name, resourceType)
 	}
 }
+
+// ReportWaitsLongerThan configures the duration after which the Wait-For Graph (which
+// Owners are waiting on which locks, and who holds them) is logged when waiting for a lock.
+// A duration of zero disables this, which is the default.
+func ReportWaitsLongerThan(threshold time.Duration) {
+	armMutexKV.lock.Lock()
+	defer armMutexKV.lock.Unlock()
+	armMutexKV.waitThreshold = threshold
+}
+
+// WaitForGraph returns a human-readable representation of which Owners are currently
+// waiting on which locks, and which Owner holds each of those locks
+func WaitForGraph() string {
+	return armMutexKV.WaitForGraph()
+}
+
+// canonicalNames returns the unique names in the order in which they should be locked
+func canonicalNames(names *[]string) []string {
+	newSlice := removeDuplicatesFromStringArray(*names)
+	sort.Strings(newSlice)
+	return newSlice
+}
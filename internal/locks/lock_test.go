@@ -0,0 +1,84 @@
+package locks
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockMultipleByNameCtxUsesASingleOwner(t *testing.T) {
+	holder := WithOwner(context.TODO(), "holder")
+	if err := LockByNameCtx(holder, "second", "single-owner"); err != nil {
+		t.Fatalf("locking second: %+v", err)
+	}
+
+	// the Context doesn't specify an Owner, so one is derived for this call - which is waiting
+	// on `second` whilst holding `first`..
+	waiting := make(chan error)
+	go func() {
+		waiting <- LockMultipleByNameCtx(context.TODO(), &[]string{"second", "first"}, "single-owner")
+	}()
+	for !strings.Contains(WaitForGraph(), `for "single-owner.second"`) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// .. so the holder of `second` waiting on `first` would deadlock
+	ctx, cancel := context.WithTimeout(holder, 5*time.Second)
+	defer cancel()
+	err := LockByNameCtx(ctx, "first", "single-owner")
+	if err == nil {
+		t.Fatalf("expected a deadlock to be detected but didn't get an error")
+	}
+	if !strings.Contains(err.Error(), "would deadlock") {
+		t.Fatalf("expected the error to be a deadlock rather than a timeout but got %q", err.Error())
+	}
+
+	UnlockByName("second", "single-owner")
+	if err := <-waiting; err != nil {
+		t.Fatalf("locking: %+v", err)
+	}
+	UnlockMultipleByName(&[]string{"first", "second"}, "single-owner")
+}
+
+func TestLockByNameCtxInvertedCallPaths(t *testing.T) {
+	// the Network Interface previously locked the Subnets and then the Virtual Networks..
+	networkInterface := WithNewOwner(context.TODO(), "networkInterface")
+	subnets := []string{"internal"}
+	virtualNetworks := []string{"example"}
+	if err := LockMultipleByNameCtx(networkInterface, &subnets, "inverted-subnet"); err != nil {
+		t.Fatalf("locking Subnets: %+v", err)
+	}
+
+	// .. whereas the Subnet locks the Virtual Network and then the Subnet
+	subnet := WithNewOwner(context.TODO(), "subnet")
+	if err := LockByNameCtx(subnet, "example", "inverted-virtual-network"); err != nil {
+		t.Fatalf("locking Virtual Network: %+v", err)
+	}
+
+	waiting := make(chan error)
+	go func() {
+		waiting <- LockMultipleByNameCtx(networkInterface, &virtualNetworks, "inverted-virtual-network")
+	}()
+	for !strings.Contains(WaitForGraph(), `for "inverted-virtual-network.example"`) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(subnet, 5*time.Second)
+	defer cancel()
+	err := LockByNameCtx(ctx, "internal", "inverted-subnet")
+	if err == nil {
+		t.Fatalf("expected a deadlock to be detected but didn't get an error")
+	}
+	if !strings.Contains(err.Error(), "would deadlock") {
+		t.Fatalf("expected the error to be a deadlock rather than a timeout but got %q", err.Error())
+	}
+
+	// the Subnet then fails, releasing the Virtual Network - allowing the Network Interface to continue
+	UnlockByName("example", "inverted-virtual-network")
+	if err := <-waiting; err != nil {
+		t.Fatalf("locking Virtual Networks: %+v", err)
+	}
+	UnlockMultipleByName(&virtualNetworks, "inverted-virtual-network")
+	UnlockMultipleByName(&subnets, "inverted-subnet")
+}
@@ -1,48 +1,295 @@
 package locks
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // mutexKV is a simple key/value store for arbitrary mutexes. It can be used to
 // serialize changes across arbitrary collaborators that share knowledge of the
 // keys they must serialize on.
+//
+// Each lock is acquired on behalf of an Owner (see WithOwner) - which allows a lock
+// held by one Owner whilst waiting on a lock held by another to be detected as a
+// deadlock, rather than blocking forever.
 type mutexKV struct {
 	lock  sync.Mutex
-	store map[string]*sync.Mutex
+	store map[string]*keyLock
+
+	// waiting is a map of Owner to the keys that Owner is waiting to acquire
+	waiting map[string]map[string]time.Time
+
+	// waitThreshold is the duration after which the wait-for graph is logged
+	// when waiting to acquire a lock, zero disables this
+	waitThreshold time.Duration
+}
+
+type keyLock struct {
+	held      bool
+	holder    string
+	heldSince time.Time
+
+	// released is closed (and replaced) each time this lock is released
+	released chan struct{}
 }
 
 // Locks the mutex for the given key. Caller is responsible for calling Unlock
 // for the same key
 func (m *mutexKV) Lock(key string) {
-	log.Printf("[DEBUG] Locking %q", key)
-	m.get(key).Lock()
-	log.Printf("[DEBUG] Locked %q", key)
+	// since each call without an Owner is assigned a unique Owner, and the Background
+	// context can't be cancelled, this can't return an error - but just in case
+	if err := m.LockCtx(context.Background(), key); err != nil {
+		panic(fmt.Sprintf("locking %q: %+v", key, err))
+	}
+}
+
+// LockCtx locks the mutex for the given key, returning an error if the Context is
+// cancelled/times out before the lock is acquired, or if waiting for this lock would
+// deadlock. Caller is responsible for calling Unlock for the same key if no error is returned
+func (m *mutexKV) LockCtx(ctx context.Context, key string) error {
+	owner := ownerFromContext(ctx)
+	log.Printf("[DEBUG] Locking %q (owner %q)", key, owner)
+
+	var waitingSince *time.Time
+	var threshold time.Duration
+	var report <-chan time.Time
+	for {
+		m.lock.Lock()
+		kl := m.get(key)
+		if !kl.held {
+			kl.held = true
+			kl.holder = owner
+			kl.heldSince = time.Now()
+			m.stopWaiting(owner, key)
+			m.lock.Unlock()
+
+			if waitingSince != nil {
+				log.Printf("[DEBUG] Locked %q (owner %q) after waiting %s", key, owner, time.Since(*waitingSince))
+			} else {
+				log.Printf("[DEBUG] Locked %q (owner %q)", key, owner)
+			}
+			return nil
+		}
+
+		if cycle := m.findCycle(owner, key); cycle != nil {
+			m.stopWaiting(owner, key)
+			m.lock.Unlock()
+			return fmt.Errorf("acquiring the lock %q would deadlock: %s", key, strings.Join(cycle, " -> "))
+		}
+
+		if waitingSince == nil {
+			now := time.Now()
+			waitingSince = &now
+			log.Printf("[DEBUG] Waiting to lock %q (owner %q) - held by %q since %s", key, owner, kl.holder, kl.heldSince.Format(time.RFC3339))
+
+			threshold = m.waitThreshold
+			if threshold > 0 {
+				timer := time.NewTimer(threshold)
+				defer timer.Stop()
+				report = timer.C
+			}
+		}
+		m.startWaiting(owner, key, *waitingSince)
+		released := kl.released
+		holder := kl.holder
+		m.lock.Unlock()
+
+		select {
+		case <-released:
+			// try again
+
+		case <-report:
+			log.Printf("[WARN] Waited longer than %s to lock %q (owner %q) - Wait-For Graph:\n%s", threshold, key, owner, m.WaitForGraph())
+			report = nil
+
+		case <-ctx.Done():
+			m.lock.Lock()
+			m.stopWaiting(owner, key)
+			m.lock.Unlock()
+			return fmt.Errorf("waiting %s for the lock %q held by %q: %+v", time.Since(*waitingSince), key, holder, ctx.Err())
+		}
+	}
 }
 
 // Unlock the mutex for the given key. Caller must have called Lock for the same key first
 func (m *mutexKV) Unlock(key string) {
 	log.Printf("[DEBUG] Unlocking %q", key)
-	m.get(key).Unlock()
-	log.Printf("[DEBUG] Unlocked %q", key)
+	m.lock.Lock()
+	kl := m.get(key)
+	if !kl.held {
+		m.lock.Unlock()
+		panic(fmt.Sprintf("unlock of unlocked key %q", key))
+	}
+
+	heldFor := time.Since(kl.heldSince)
+	holder := kl.holder
+	kl.held = false
+	kl.holder = ""
+	close(kl.released)
+	kl.released = make(chan struct{})
+	m.lock.Unlock()
+	log.Printf("[DEBUG] Unlocked %q (owner %q) after holding it for %s", key, holder, heldFor)
 }
 
-// Returns a mutex for the given key, no guarantee of its lock status
-func (m *mutexKV) get(key string) *sync.Mutex {
+// WaitForGraph returns a human-readable representation of which Owners are waiting
+// on which locks, and which Owner is currently holding each of those locks
+func (m *mutexKV) WaitForGraph() string {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	mutex, ok := m.store[key]
+
+	owners := make([]string, 0)
+	for owner := range m.waiting {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	lines := make([]string, 0)
+	for _, owner := range owners {
+		keys := make([]string, 0)
+		for key := range m.waiting[owner] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			line := fmt.Sprintf("%q waiting %s for %q", owner, time.Since(m.waiting[owner][key]).Round(time.Millisecond), key)
+			if kl, ok := m.store[key]; ok && kl.held {
+				line += fmt.Sprintf(" held by %q for %s", kl.holder, time.Since(kl.heldSince).Round(time.Millisecond))
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) == 0 {
+		return "(no Owners are waiting)"
+	}
+	return strings.Join(lines, "\n")
+}
+
+// findCycle determines whether owner waiting on key would result in a cycle
+// in the wait-for graph, returning the cycle if so. Caller must hold m.lock
+func (m *mutexKV) findCycle(owner string, key string) []string {
+	visited := make(map[string]struct{})
+
+	var walk func(key string, path []string) []string
+	walk = func(key string, path []string) []string {
+		kl, ok := m.store[key]
+		if !ok || !kl.held {
+			return nil
+		}
+
+		path = append(path, fmt.Sprintf("%q (held by %q)", key, kl.holder))
+		if kl.holder == owner {
+			return path
+		}
+
+		if _, seen := visited[kl.holder]; seen {
+			return nil
+		}
+		visited[kl.holder] = struct{}{}
+
+		waitingOn := make([]string, 0)
+		for k := range m.waiting[kl.holder] {
+			waitingOn = append(waitingOn, k)
+		}
+		sort.Strings(waitingOn)
+		for _, k := range waitingOn {
+			if cycle := walk(k, path); cycle != nil {
+				return cycle
+			}
+		}
+
+		return nil
+	}
+
+	return walk(key, []string{fmt.Sprintf("%q", owner)})
+}
+
+// startWaiting records that owner is waiting on key. Caller must hold m.lock
+func (m *mutexKV) startWaiting(owner, key string, since time.Time) {
+	if _, ok := m.waiting[owner]; !ok {
+		m.waiting[owner] = make(map[string]time.Time)
+	}
+	m.waiting[owner][key] = since
+}
+
+// stopWaiting records that owner is no longer waiting on key. Caller must hold m.lock
+func (m *mutexKV) stopWaiting(owner, key string) {
+	delete(m.waiting[owner], key)
+	if len(m.waiting[owner]) == 0 {
+		delete(m.waiting, owner)
+	}
+}
+
+// Returns the lock for the given key, no guarantee of its lock status. Caller must hold m.lock
+func (m *mutexKV) get(key string) *keyLock {
+	kl, ok := m.store[key]
 	if !ok {
-		mutex = &sync.Mutex{}
-		m.store[key] = mutex
+		kl = &keyLock{
+			released: make(chan struct{}),
+		}
+		m.store[key] = kl
 	}
-	return mutex
+	return kl
 }
 
 // Returns a properly initialized mutexKV
 func NewMutexKV() *mutexKV {
 	return &mutexKV{
-		store: make(map[string]*sync.Mutex),
+		store:   make(map[string]*keyLock),
+		waiting: make(map[string]map[string]time.Time),
+	}
+}
+
+type ownerContextKey struct{}
+
+// ownerSequence is used to generate a unique Owner, see WithNewOwner
+var ownerSequence uint64
+
+// WithOwner returns a copy of the Context which acquires locks on behalf of the specified
+// Owner (for example the Resource ID being modified) - allowing deadlocks between Owners
+// to be detected and surfaced in the logs.
+//
+// NOTE: locks are not re-entrant, attempting to acquire a lock already held by the same
+// Owner returns an error rather than blocking forever.
+func WithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerContextKey{}, owner)
+}
+
+// WithNewOwner returns a copy of the Context which acquires locks on behalf of a new, unique
+// Owner described by name (for example the Resource ID being modified). This should be called
+// once per operation, so that each of the locks acquired during that operation share an Owner.
+//
+// Unlike WithOwner, operations which share a name (such as two associations which use the same
+// Resource ID) are distinct Owners - and so won't be reported as re-entrant.
+func WithNewOwner(ctx context.Context, name string) context.Context {
+	return WithOwner(ctx, newOwner(name))
+}
+
+// withOwnerIfUnset returns a copy of the Context with a new Owner if the Context doesn't
+// already specify one, otherwise the Context is returned unchanged
+func withOwnerIfUnset(ctx context.Context) context.Context {
+	if v, ok := ctx.Value(ownerContextKey{}).(string); ok && v != "" {
+		return ctx
 	}
+
+	return WithOwner(ctx, newOwner("anonymous"))
+}
+
+func newOwner(name string) string {
+	return fmt.Sprintf("%s-%d", name, atomic.AddUint64(&ownerSequence, 1))
+}
+
+func ownerFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(ownerContextKey{}).(string); ok && v != "" {
+		return v
+	}
+
+	return newOwner("anonymous")
 }
@@ -0,0 +1,166 @@
+package locks
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMutexKVLockCtxTimesOut(t *testing.T) {
+	m := NewMutexKV()
+	if err := m.LockCtx(WithOwner(context.TODO(), "first"), "example"); err != nil {
+		t.Fatalf("locking: %+v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(WithOwner(context.TODO(), "second"), 50*time.Millisecond)
+	defer cancel()
+	err := m.LockCtx(ctx, "example")
+	if err == nil {
+		t.Fatalf("expected an error since the lock is held by another owner but didn't get one")
+	}
+	if !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Fatalf("expected the error to contain %q but got %q", context.DeadlineExceeded.Error(), err.Error())
+	}
+
+	// the waiter should have been removed, so once released the lock can be acquired again
+	m.Unlock("example")
+	if err := m.LockCtx(WithOwner(context.TODO(), "second"), "example"); err != nil {
+		t.Fatalf("locking after release: %+v", err)
+	}
+	if graph := m.WaitForGraph(); graph != "(no Owners are waiting)" {
+		t.Fatalf("expected no Owners to be waiting but got %q", graph)
+	}
+}
+
+func TestMutexKVLockCtxWaitsForRelease(t *testing.T) {
+	m := NewMutexKV()
+	m.Lock("example")
+
+	acquired := make(chan error)
+	go func() {
+		acquired <- m.LockCtx(context.TODO(), "example")
+	}()
+
+	select {
+	case err := <-acquired:
+		t.Fatalf("expected the lock to still be held but got %+v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	m.Unlock("example")
+
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("locking: %+v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for the lock to be acquired")
+	}
+}
+
+func TestMutexKVLockCtxReEntrant(t *testing.T) {
+	m := NewMutexKV()
+	ctx := WithOwner(context.TODO(), "first")
+	if err := m.LockCtx(ctx, "example"); err != nil {
+		t.Fatalf("locking: %+v", err)
+	}
+
+	if err := m.LockCtx(ctx, "example"); err == nil {
+		t.Fatalf("expected an error since the lock is already held by this owner but didn't get one")
+	}
+}
+
+func TestMutexKVLockCtxDetectsDeadlock(t *testing.T) {
+	m := NewMutexKV()
+	first := WithOwner(context.TODO(), "first")
+	second := WithOwner(context.TODO(), "second")
+
+	if err := m.LockCtx(first, "a"); err != nil {
+		t.Fatalf("locking a: %+v", err)
+	}
+	if err := m.LockCtx(second, "b"); err != nil {
+		t.Fatalf("locking b: %+v", err)
+	}
+
+	// first now waits on b, which is held by second..
+	waiting := make(chan error)
+	go func() {
+		waiting <- m.LockCtx(first, "b")
+	}()
+	for !strings.Contains(m.WaitForGraph(), `"first" waiting`) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// .. so second waiting on a (held by first) would deadlock
+	err := m.LockCtx(second, "a")
+	if err == nil {
+		t.Fatalf("expected a deadlock to be detected but didn't get an error")
+	}
+	expected := `"second" -> "a" (held by "first") -> "b" (held by "second")`
+	if !strings.Contains(err.Error(), expected) {
+		t.Fatalf("expected the error to contain %q but got %q", expected, err.Error())
+	}
+
+	// releasing b allows first to continue
+	m.Unlock("b")
+	if err := <-waiting; err != nil {
+		t.Fatalf("locking b: %+v", err)
+	}
+}
+
+func TestMutexKVWaitForGraph(t *testing.T) {
+	m := NewMutexKV()
+	if err := m.LockCtx(WithOwner(context.TODO(), "holder"), "example"); err != nil {
+		t.Fatalf("locking: %+v", err)
+	}
+
+	ctx, cancel := context.WithCancel(WithOwner(context.TODO(), "waiter"))
+	done := make(chan struct{})
+	go func() {
+		_ = m.LockCtx(ctx, "example")
+		close(done)
+	}()
+	for !strings.Contains(m.WaitForGraph(), `"waiter" waiting`) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	graph := m.WaitForGraph()
+	if !strings.Contains(graph, `for "example" held by "holder"`) {
+		t.Fatalf("expected the graph to contain the holder but got %q", graph)
+	}
+
+	cancel()
+	<-done
+}
+
+func TestCanonicalNames(t *testing.T) {
+	testData := []struct {
+		input    []string
+		expected []string
+	}{
+		{
+			input:    []string{},
+			expected: []string{},
+		},
+		{
+			input:    []string{"vnet2", "vnet1"},
+			expected: []string{"vnet1", "vnet2"},
+		},
+		{
+			input:    []string{"vnet2", "vnet1", "vnet2", "vnet3"},
+			expected: []string{"vnet1", "vnet2", "vnet3"},
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %+v..", v.input)
+
+		actual := canonicalNames(&v.input)
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Fatalf("expected %+v but got %+v", v.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package lro
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+// privateStateKey is the key within the Private State for a Resource which the Pending Operation is persisted to
+const privateStateKey = "azurerm_pending_operation"
+
+const (
+	OperationCreate = pluginsdk.TimeoutCreate
+	OperationUpdate = pluginsdk.TimeoutUpdate
+)
+
+// PendingOperation is a Long Running Operation which was in-flight when the Provider was interrupted (for example
+// when Terraform is cancelled) - which is persisted into the Private State for the Resource so that it can be resumed
+type PendingOperation struct {
+	// ResourceID is the ID of the Resource which the operation is for
+	ResourceID string `json:"resourceId"`
+
+	// Operation is the operation which was interrupted, either `create` or `update`
+	Operation string `json:"operation"`
+
+	// Future is the serialized Future used to poll the operation, containing the polling URL (from the
+	// Azure-AsyncOperation/Location headers) - which is omitted when the Resource is polled using a custom poller
+	Future json.RawMessage `json:"future,omitempty"`
+}
+
+var (
+	// pendingOperations are the Pending Operations for each Resource, keyed by the (lower-cased) Resource ID,
+	// these are tracked when an operation is interrupted or restored from the Private State for a Resource
+	pendingOperations     = map[string]PendingOperation{}
+	pendingOperationsLock = &sync.Mutex{}
+)
+
+// WasInterrupted returns whether the Context was cancelled since the Provider was interrupted, rather than timing out
+func WasInterrupted(ctx context.Context) bool {
+	return ctx.Err() == context.Canceled
+}
+
+// Track tracks the interrupted operation for the Resource, so that it's persisted into the Private State for the Resource
+// and can be resumed when the Resource is next read. The Future can be nil when the Resource uses a custom poller.
+//
+// NOTE: this is only possible when the Provider is interrupted gracefully (e.g. Terraform is cancelled) - when the Provider
+// is killed the Resource isn't persisted into the State, as such the Resource should be adopted in Create whilst it's
+// still being provisioned (see IsProvisioning and WaitForProvisioning).
+func Track(resourceId, operation string, future azure.FutureAPI) {
+	pending := PendingOperation{
+		ResourceID: resourceId,
+		Operation:  operation,
+	}
+
+	if future != nil {
+		serialized, err := future.MarshalJSON()
+		if err != nil {
+			// the operation can still be resumed using the Resource's provisioning state
+			log.Printf("[DEBUG] serializing the Future for the %s of %q: %+v", operation, resourceId, err)
+		} else {
+			pending.Future = serialized
+		}
+	}
+
+	log.Printf("[DEBUG] The %s of %q was interrupted - tracking so this can be resumed..", operation, resourceId)
+	Restore(pending)
+}
+
+// Interrupted tracks the interrupted operation for the Resource (see Track) and returns an error for it - the Resource
+// should still set its ID, so that both the Resource and the operation are persisted into the State, however the apply
+// needs to fail since the Resource hasn't been fully created/updated yet
+func Interrupted(resourceId, operation string, future azure.FutureAPI) error {
+	Track(resourceId, operation, future)
+	return fmt.Errorf("the %s of %q was interrupted and will be resumed on the next refresh", operation, resourceId)
+}
+
+// Restore tracks the Pending Operation, for example when it's been loaded from the Private State for a Resource
+func Restore(pending PendingOperation) {
+	pendingOperationsLock.Lock()
+	defer pendingOperationsLock.Unlock()
+
+	pendingOperations[strings.ToLower(pending.ResourceID)] = pending
+}
+
+// Take returns the Pending Operation for the Resource (if any) and stops tracking it
+func Take(resourceId string) *PendingOperation {
+	pendingOperationsLock.Lock()
+	defer pendingOperationsLock.Unlock()
+
+	key := strings.ToLower(resourceId)
+	pending, ok := pendingOperations[key]
+	if !ok {
+		return nil
+	}
+
+	delete(pendingOperations, key)
+	return &pending
+}
+
+// Resume checks whether the operation which was interrupted for this Resource (if any) has completed, using the
+// persisted Future - see ResumeFunc
+func Resume(ctx context.Context, d *pluginsdk.ResourceData, client autorest.Client) error {
+	return ResumeFunc(ctx, d, func(ctx context.Context, pending PendingOperation) (bool, error) {
+		if len(pending.Future) == 0 {
+			return false, fmt.Errorf("the Future for the %s wasn't persisted", pending.Operation)
+		}
+
+		var future azure.Future
+		if err := future.UnmarshalJSON(pending.Future); err != nil {
+			return false, fmt.Errorf("deserializing the Future for the %s: %+v", pending.Operation, err)
+		}
+
+		return future.DoneWithContext(ctx, client)
+	})
+}
+
+// ResumeFunc checks whether the operation which was interrupted for this Resource (if any) has completed using the
+// specified function, which polls the operation once - returning whether it's completed, and if so whether it failed.
+//
+// This intentionally doesn't wait for the operation to complete, since this is called when the Resource is read
+// (including during a plan) and the operation can take considerably longer than the Read timeout. Whilst the operation
+// is still in progress it remains tracked (so that it's checked again when the Resource is next read) and the Resource
+// is read in its current state. When the creation fails the Resource is removed from the State, so that it's recreated.
+func ResumeFunc(ctx context.Context, d *pluginsdk.ResourceData, check func(ctx context.Context, pending PendingOperation) (bool, error)) error {
+	pending := Take(d.Id())
+	if pending == nil {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Checking the interrupted %s of %q..", pending.Operation, pending.ResourceID)
+	done, err := check(ctx, *pending)
+	if !done {
+		// the operation is still in progress (or couldn't be checked), so it's tracked again to be checked next time
+		Restore(*pending)
+		if err != nil {
+			return fmt.Errorf("checking the interrupted %s of %q: %+v", pending.Operation, pending.ResourceID, err)
+		}
+
+		log.Printf("[WARN] The interrupted %s of %q is still in progress - the Resource will be checked again when it's next read", pending.Operation, pending.ResourceID)
+		return nil
+	}
+
+	if err != nil {
+		// a failed update is reflected when the Resource is read, however a failed creation needs to be recreated
+		if pending.Operation != OperationCreate {
+			log.Printf("[WARN] The interrupted %s of %q failed: %+v", pending.Operation, pending.ResourceID, err)
+			return nil
+		}
+
+		log.Printf("[WARN] The interrupted %s of %q failed - removing from state so this is recreated: %+v", pending.Operation, pending.ResourceID, err)
+		d.SetId("")
+		return nil
+	}
+
+	log.Printf("[DEBUG] The interrupted %s of %q has completed.", pending.Operation, pending.ResourceID)
+	return nil
+}
+
+// IsProvisioning returns whether the specified Provisioning State means that the Resource is still being created (for
+// example when the Provider was killed part-way through creating it) - other non-terminal states (e.g. `Deleting`
+// or `Upgrading`) aren't included, since these are for operations on a Resource which has already been provisioned
+func IsProvisioning(provisioningState string) bool {
+	switch strings.ToLower(provisioningState) {
+	case "accepted", "activating", "created", "creating", "inprogress", "provisioning":
+		return true
+	}
+
+	return false
+}
+
+// provisioningPollInterval is the interval at which the Provisioning State is polled, which is overridable for testing
+var provisioningPollInterval = 30 * time.Second
+
+// WaitForProvisioning waits for a Resource which is being provisioned to reach a terminal Provisioning State, using the
+// specified function to retrieve the current Provisioning State - returning an error when the provisioning didn't succeed
+func WaitForProvisioning(ctx context.Context, timeout time.Duration, provisioningState func(ctx context.Context) (string, error)) error {
+	const provisioning = "Provisioning"
+	const succeeded = "Succeeded"
+
+	stateConf := &pluginsdk.StateChangeConf{
+		Pending: []string{provisioning},
+		Target:  []string{succeeded},
+		Refresh: func() (interface{}, string, error) {
+			state, err := provisioningState(ctx)
+			if err != nil {
+				return nil, "", err
+			}
+
+			if IsProvisioning(state) {
+				return state, provisioning, nil
+			}
+			if strings.EqualFold(state, succeeded) {
+				return state, succeeded, nil
+			}
+			return state, state, nil
+		},
+		PollInterval: provisioningPollInterval,
+		Timeout:      timeout,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+// FromPrivateState returns the Pending Operation persisted in the Private State for a Resource (if any)
+func FromPrivateState(private []byte) (*PendingOperation, error) {
+	if len(private) == 0 {
+		return nil, nil
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(private, &values); err != nil {
+		return nil, fmt.Errorf("deserializing the Private State: %+v", err)
+	}
+
+	raw, ok := values[privateStateKey]
+	if !ok || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var pending PendingOperation
+	if err := json.Unmarshal(raw, &pending); err != nil {
+		return nil, fmt.Errorf("deserializing the Pending Operation: %+v", err)
+	}
+
+	return &pending, nil
+}
+
+// WithPrivateState returns the Private State for a Resource including the Pending Operation - or without
+// the Pending Operation when it's nil
+func WithPrivateState(private []byte, pending *PendingOperation) ([]byte, error) {
+	var values map[string]json.RawMessage
+	if len(private) > 0 {
+		if err := json.Unmarshal(private, &values); err != nil {
+			return nil, fmt.Errorf("deserializing the Private State: %+v", err)
+		}
+	}
+	if values == nil {
+		values = map[string]json.RawMessage{}
+	}
+
+	if pending == nil {
+		if _, ok := values[privateStateKey]; !ok {
+			return private, nil
+		}
+		delete(values, privateStateKey)
+	} else {
+		serialized, err := json.Marshal(pending)
+		if err != nil {
+			return nil, fmt.Errorf("serializing the Pending Operation: %+v", err)
+		}
+		values[privateStateKey] = serialized
+	}
+
+	return json.Marshal(values)
+}
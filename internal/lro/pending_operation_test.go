@@ -0,0 +1,289 @@
+package lro
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+const testResourceId = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Web/hostingEnvironments/environment1"
+
+func TestPrivateState(t *testing.T) {
+	pending := &PendingOperation{
+		ResourceID: testResourceId,
+		Operation:  OperationCreate,
+		Future:     json.RawMessage(`{"method":"PUT","pollingMethod":"AsyncOperation","pollingURI":"https://management.azure.com/operations/1"}`),
+	}
+
+	testData := []struct {
+		Name    string
+		Private []byte
+	}{
+		{
+			Name:    "Empty",
+			Private: nil,
+		},
+		{
+			Name:    "Null",
+			Private: []byte("null"),
+		},
+		{
+			Name:    "Existing Values",
+			Private: []byte(`{"schema_version":"1","e2bfb730-ecaa-11e6-8f88-34363bc7c4c0":{"create":5400000000000}}`),
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		existing, err := FromPrivateState(v.Private)
+		if err != nil {
+			t.Fatalf("reading the existing Private State: %+v", err)
+		}
+		if existing != nil {
+			t.Fatalf("expected no Pending Operation but got %+v", existing)
+		}
+
+		withPending, err := WithPrivateState(v.Private, pending)
+		if err != nil {
+			t.Fatalf("adding the Pending Operation: %+v", err)
+		}
+
+		actual, err := FromPrivateState(withPending)
+		if err != nil {
+			t.Fatalf("reading the Pending Operation: %+v", err)
+		}
+		if actual == nil {
+			t.Fatalf("expected a Pending Operation but didn't get one")
+		}
+		if actual.ResourceID != pending.ResourceID || actual.Operation != pending.Operation || string(actual.Future) != string(pending.Future) {
+			t.Fatalf("expected %+v but got %+v", *pending, *actual)
+		}
+
+		withoutPending, err := WithPrivateState(withPending, nil)
+		if err != nil {
+			t.Fatalf("removing the Pending Operation: %+v", err)
+		}
+		if actual, _ := FromPrivateState(withoutPending); actual != nil {
+			t.Fatalf("expected the Pending Operation to be removed but got %+v", actual)
+		}
+
+		// the existing values should be retained
+		if len(v.Private) > 0 && string(v.Private) != "null" {
+			var expected, result map[string]interface{}
+			if err := json.Unmarshal(v.Private, &expected); err != nil {
+				t.Fatalf("unmarshalling: %+v", err)
+			}
+			if err := json.Unmarshal(withoutPending, &result); err != nil {
+				t.Fatalf("unmarshalling: %+v", err)
+			}
+			if !reflect.DeepEqual(expected, result) {
+				t.Fatalf("expected %+v but got %+v", expected, result)
+			}
+		}
+	}
+}
+
+func TestInterrupted(t *testing.T) {
+	if err := Interrupted(testResourceId, OperationCreate, nil); err == nil {
+		t.Fatalf("expected an error for an interrupted operation but didn't get one")
+	}
+
+	pending := Take(testResourceId)
+	if pending == nil {
+		t.Fatalf("expected the interrupted operation to be tracked")
+	}
+	if pending.Operation != OperationCreate {
+		t.Fatalf("expected the operation to be %q but got %q", OperationCreate, pending.Operation)
+	}
+}
+
+func TestResumeFunc(t *testing.T) {
+	testData := []struct {
+		Name            string
+		Pending         bool
+		Operation       string
+		Check           func(ctx context.Context) (bool, error)
+		ExpectedError   bool
+		ExpectedId      string
+		ExpectedTracked bool
+		ExpectedChecked bool
+	}{
+		{
+			Name:       "No Pending Operation",
+			ExpectedId: testResourceId,
+		},
+		{
+			Name:    "Completed",
+			Pending: true,
+			Check: func(ctx context.Context) (bool, error) {
+				return true, nil
+			},
+			ExpectedId:      testResourceId,
+			ExpectedChecked: true,
+		},
+		{
+			Name:    "Failed",
+			Pending: true,
+			Check: func(ctx context.Context) (bool, error) {
+				return true, fmt.Errorf("provisioning failed")
+			},
+			ExpectedId:      "",
+			ExpectedChecked: true,
+		},
+		{
+			Name:      "Update Failed",
+			Pending:   true,
+			Operation: OperationUpdate,
+			Check: func(ctx context.Context) (bool, error) {
+				return true, fmt.Errorf("provisioning failed")
+			},
+			ExpectedId:      testResourceId,
+			ExpectedChecked: true,
+		},
+		{
+			Name:    "Still In Progress",
+			Pending: true,
+			Check: func(ctx context.Context) (bool, error) {
+				return false, nil
+			},
+			ExpectedId:      testResourceId,
+			ExpectedTracked: true,
+			ExpectedChecked: true,
+		},
+		{
+			Name:    "Unable To Check",
+			Pending: true,
+			Check: func(ctx context.Context) (bool, error) {
+				return false, fmt.Errorf("connection reset")
+			},
+			ExpectedError:   true,
+			ExpectedId:      testResourceId,
+			ExpectedTracked: true,
+			ExpectedChecked: true,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		d := schema.TestResourceDataRaw(t, map[string]*pluginsdk.Schema{}, map[string]interface{}{})
+		d.SetId(testResourceId)
+
+		if v.Pending {
+			operation := OperationCreate
+			if v.Operation != "" {
+				operation = v.Operation
+			}
+			Track(testResourceId, operation, nil)
+		}
+
+		checked := false
+		err := ResumeFunc(context.TODO(), d, func(ctx context.Context, pending PendingOperation) (bool, error) {
+			checked = true
+			if pending.ResourceID != testResourceId {
+				t.Fatalf("expected the Pending Operation for %q but got %q", testResourceId, pending.ResourceID)
+			}
+			return v.Check(ctx)
+		})
+
+		if v.ExpectedError && err == nil {
+			t.Fatalf("expected an error but didn't get one")
+		}
+		if !v.ExpectedError && err != nil {
+			t.Fatalf("resuming: %+v", err)
+		}
+		if checked != v.ExpectedChecked {
+			t.Fatalf("expected checked to be %t but got %t", v.ExpectedChecked, checked)
+		}
+		if d.Id() != v.ExpectedId {
+			t.Fatalf("expected the ID to be %q but got %q", v.ExpectedId, d.Id())
+		}
+
+		tracked := Take(testResourceId) != nil
+		if tracked != v.ExpectedTracked {
+			t.Fatalf("expected tracked to be %t but got %t", v.ExpectedTracked, tracked)
+		}
+	}
+}
+
+func TestIsProvisioning(t *testing.T) {
+	testData := map[string]bool{
+		"":            false,
+		"Succeeded":   false,
+		"succeeded":   false,
+		"Failed":      false,
+		"Canceled":    false,
+		"Deleting":    false,
+		"Terminating": false,
+		"Upgrading":   false,
+		"Created":     true,
+		"Creating":    true,
+		"InProgress":  true,
+		"Activating":  true,
+	}
+
+	for state, expected := range testData {
+		t.Logf("[DEBUG] Testing %q..", state)
+
+		if actual := IsProvisioning(state); actual != expected {
+			t.Fatalf("expected %t but got %t", expected, actual)
+		}
+	}
+}
+
+func TestWaitForProvisioning(t *testing.T) {
+	provisioningPollInterval = 10 * time.Millisecond
+	defer func() {
+		provisioningPollInterval = 30 * time.Second
+	}()
+
+	testData := []struct {
+		Name          string
+		States        []string
+		ExpectedError bool
+	}{
+		{
+			Name:   "Succeeded",
+			States: []string{"Creating", "Creating", "Succeeded"},
+		},
+		{
+			Name:   "Succeeded Lower-Cased",
+			States: []string{"InProgress", "succeeded"},
+		},
+		{
+			Name:          "Failed",
+			States:        []string{"Activating", "Failed"},
+			ExpectedError: true,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		i := 0
+		err := WaitForProvisioning(context.TODO(), time.Minute, func(ctx context.Context) (string, error) {
+			state := v.States[i]
+			if i < len(v.States)-1 {
+				i++
+			}
+			return state, nil
+		})
+
+		if v.ExpectedError && err == nil {
+			t.Fatalf("expected an error but didn't get one")
+		}
+		if !v.ExpectedError && err != nil {
+			t.Fatalf("waiting for provisioning: %+v", err)
+		}
+		if i != len(v.States)-1 {
+			t.Fatalf("expected all of the states to be polled but only polled %d", i+1)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/common"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/validation"
+)
+
+func schemaAuditLog() *pluginsdk.Schema {
+	return &pluginsdk.Schema{
+		Type:     pluginsdk.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &pluginsdk.Resource{
+			Schema: map[string]*pluginsdk.Schema{
+				"path": {
+					Type:         pluginsdk.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+
+				"redacted_fields": {
+					Type:     pluginsdk.TypeList,
+					Optional: true,
+					Elem: &pluginsdk.Schema{
+						Type:         pluginsdk.TypeString,
+						ValidateFunc: validation.StringIsNotEmpty,
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandAuditLog(input []interface{}) *common.AuditLogOptions {
+	// the audit log is only enabled when the block is specified
+	if len(input) == 0 || input[0] == nil {
+		return nil
+	}
+
+	val := input[0].(map[string]interface{})
+
+	options := common.AuditLogOptions{
+		Path:           val["path"].(string),
+		RedactedFields: make([]string, 0),
+	}
+
+	if v, ok := val["redacted_fields"].([]interface{}); ok {
+		for _, field := range v {
+			options.RedactedFields = append(options.RedactedFields, field.(string))
+		}
+	}
+
+	return &options
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/common"
+)
+
+func TestExpandAuditLog(t *testing.T) {
+	testData := []struct {
+		Name     string
+		Input    []interface{}
+		Expected *common.AuditLogOptions
+	}{
+		{
+			Name:     "Omitted",
+			Input:    []interface{}{},
+			Expected: nil,
+		},
+		{
+			Name: "Path Only",
+			Input: []interface{}{
+				map[string]interface{}{
+					"path":            "/tmp/audit.log",
+					"redacted_fields": []interface{}{},
+				},
+			},
+			Expected: &common.AuditLogOptions{
+				Path:           "/tmp/audit.log",
+				RedactedFields: []string{},
+			},
+		},
+		{
+			Name: "Complete",
+			Input: []interface{}{
+				map[string]interface{}{
+					"path": "/tmp/audit.log",
+					"redacted_fields": []interface{}{
+						"customerManagedKey",
+						"licenseKey",
+					},
+				},
+			},
+			Expected: &common.AuditLogOptions{
+				Path: "/tmp/audit.log",
+				RedactedFields: []string{
+					"customerManagedKey",
+					"licenseKey",
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testData {
+		t.Run(testCase.Name, func(t *testing.T) {
+			result := expandAuditLog(testCase.Input)
+			if !reflect.DeepEqual(result, testCase.Expected) {
+				t.Fatalf("Expected %+v but got %+v", testCase.Expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandDefaultTags(t *testing.T) {
+	testData := []struct {
+		Name     string
+		Input    []interface{}
+		Expected map[string]string
+	}{
+		{
+			Name:     "Omitted",
+			Input:    []interface{}{},
+			Expected: map[string]string{},
+		},
+		{
+			Name:     "Empty Block",
+			Input:    []interface{}{nil},
+			Expected: map[string]string{},
+		},
+		{
+			Name: "Complete",
+			Input: []interface{}{
+				map[string]interface{}{
+					"tags": map[string]interface{}{
+						"cost-center": "1234",
+						"env":         "prod",
+						"owner":       "platform",
+					},
+				},
+			},
+			Expected: map[string]string{
+				"cost-center": "1234",
+				"env":         "prod",
+				"owner":       "platform",
+			},
+		},
+	}
+
+	for _, testCase := range testData {
+		t.Run(testCase.Name, func(t *testing.T) {
+			result := expandDefaultTags(testCase.Input)
+			if !reflect.DeepEqual(result, testCase.Expected) {
+				t.Fatalf("expected %+v but got %+v", testCase.Expected, result)
+			}
+		})
+	}
+}
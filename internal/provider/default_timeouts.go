@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/timeouts"
+)
+
+func schemaDefaultTimeouts() *pluginsdk.Schema {
+	return &pluginsdk.Schema{
+		Type:     pluginsdk.TypeList,
+		Optional: true,
+		Elem: &pluginsdk.Resource{
+			Schema: map[string]*pluginsdk.Schema{
+				"resource_type": {
+					Type:         pluginsdk.TypeString,
+					Required:     true,
+					ValidateFunc: timeouts.ValidateResourceTypeGlob,
+				},
+
+				"create": {
+					Type:         pluginsdk.TypeString,
+					Optional:     true,
+					ValidateFunc: validateDefaultTimeout,
+				},
+
+				"read": {
+					Type:         pluginsdk.TypeString,
+					Optional:     true,
+					ValidateFunc: validateDefaultTimeout,
+				},
+
+				"update": {
+					Type:         pluginsdk.TypeString,
+					Optional:     true,
+					ValidateFunc: validateDefaultTimeout,
+				},
+
+				"delete": {
+					Type:         pluginsdk.TypeString,
+					Optional:     true,
+					ValidateFunc: validateDefaultTimeout,
+				},
+			},
+		},
+	}
+}
+
+func validateDefaultTimeout(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected %q to be a string", k))
+		return
+	}
+
+	duration, err := time.ParseDuration(v)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a duration (e.g. `90m`): %+v", k, err))
+		return
+	}
+
+	if duration <= 0 {
+		errors = append(errors, fmt.Errorf("%q must be greater than zero", k))
+	}
+
+	return
+}
+
+func expandDefaultTimeouts(input []interface{}) ([]timeouts.DefaultTimeouts, error) {
+	output := make([]timeouts.DefaultTimeouts, 0)
+
+	for _, raw := range input {
+		if raw == nil {
+			continue
+		}
+		val := raw.(map[string]interface{})
+
+		defaults := timeouts.DefaultTimeouts{
+			ResourceType: val["resource_type"].(string),
+		}
+
+		operations := map[string]**time.Duration{
+			"create": &defaults.Create,
+			"read":   &defaults.Read,
+			"update": &defaults.Update,
+			"delete": &defaults.Delete,
+		}
+		for key, field := range operations {
+			v, ok := val[key].(string)
+			if !ok || v == "" {
+				continue
+			}
+
+			duration, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("parsing `%s` for %q as a duration: %+v", key, defaults.ResourceType, err)
+			}
+			*field = &duration
+		}
+
+		output = append(output, defaults)
+	}
+
+	return output, nil
+}
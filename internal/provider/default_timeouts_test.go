@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/timeouts"
+)
+
+func TestExpandDefaultTimeouts(t *testing.T) {
+	duration := func(input time.Duration) *time.Duration {
+		return &input
+	}
+
+	testData := []struct {
+		Name     string
+		Input    []interface{}
+		Expected []timeouts.DefaultTimeouts
+		Error    bool
+	}{
+		{
+			Name:     "Omitted",
+			Input:    []interface{}{},
+			Expected: []timeouts.DefaultTimeouts{},
+		},
+		{
+			Name: "Some Operations",
+			Input: []interface{}{
+				map[string]interface{}{
+					"resource_type": "azurerm_kubernetes_*",
+					"create":        "3h",
+					"read":          "",
+					"update":        "",
+					"delete":        "90m",
+				},
+			},
+			Expected: []timeouts.DefaultTimeouts{
+				{
+					ResourceType: "azurerm_kubernetes_*",
+					Create:       duration(3 * time.Hour),
+					Delete:       duration(90 * time.Minute),
+				},
+			},
+		},
+		{
+			Name: "Multiple",
+			Input: []interface{}{
+				map[string]interface{}{
+					"resource_type": "*",
+					"create":        "",
+					"read":          "10m",
+					"update":        "",
+					"delete":        "",
+				},
+				map[string]interface{}{
+					"resource_type": "azurerm_resource_group",
+					"create":        "1h",
+					"read":          "1h",
+					"update":        "1h",
+					"delete":        "2h",
+				},
+			},
+			Expected: []timeouts.DefaultTimeouts{
+				{
+					ResourceType: "*",
+					Read:         duration(10 * time.Minute),
+				},
+				{
+					ResourceType: "azurerm_resource_group",
+					Create:       duration(time.Hour),
+					Read:         duration(time.Hour),
+					Update:       duration(time.Hour),
+					Delete:       duration(2 * time.Hour),
+				},
+			},
+		},
+		{
+			Name: "Invalid Duration",
+			Input: []interface{}{
+				map[string]interface{}{
+					"resource_type": "*",
+					"create":        "an hour",
+				},
+			},
+			Error: true,
+		},
+	}
+
+	for _, testCase := range testData {
+		t.Run(testCase.Name, func(t *testing.T) {
+			result, err := expandDefaultTimeouts(testCase.Input)
+			if err != nil {
+				if testCase.Error {
+					return
+				}
+				t.Fatalf("expanding: %+v", err)
+			}
+			if testCase.Error {
+				t.Fatalf("expected an error but didn't get one")
+			}
+
+			if !reflect.DeepEqual(result, testCase.Expected) {
+				t.Fatalf("expected %+v but got %+v", testCase.Expected, result)
+			}
+		})
+	}
+}
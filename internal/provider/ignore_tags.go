@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tags"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/validation"
+)
+
+func schemaIgnoreTags() *pluginsdk.Schema {
+	return &pluginsdk.Schema{
+		Type:     pluginsdk.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &pluginsdk.Resource{
+			Schema: map[string]*pluginsdk.Schema{
+				"keys": {
+					Type:     pluginsdk.TypeSet,
+					Optional: true,
+					Elem: &pluginsdk.Schema{
+						Type:         pluginsdk.TypeString,
+						ValidateFunc: validation.StringIsNotEmpty,
+					},
+					Set: pluginsdk.HashString,
+				},
+
+				"key_prefixes": {
+					Type:     pluginsdk.TypeSet,
+					Optional: true,
+					Elem: &pluginsdk.Schema{
+						Type:         pluginsdk.TypeString,
+						ValidateFunc: validation.StringIsNotEmpty,
+					},
+					Set: pluginsdk.HashString,
+				},
+			},
+		},
+	}
+}
+
+func expandIgnoreTags(input []interface{}) tags.IgnoreTags {
+	output := tags.IgnoreTags{
+		Keys:        make([]string, 0),
+		KeyPrefixes: make([]string, 0),
+	}
+	if len(input) == 0 || input[0] == nil {
+		return output
+	}
+
+	val := input[0].(map[string]interface{})
+	if v, ok := val["keys"].(*pluginsdk.Set); ok {
+		for _, key := range v.List() {
+			output.Keys = append(output.Keys, key.(string))
+		}
+	}
+	if v, ok := val["key_prefixes"].(*pluginsdk.Set); ok {
+		for _, prefix := range v.List() {
+			output.KeyPrefixes = append(output.KeyPrefixes, prefix.(string))
+		}
+	}
+
+	return output
+}
+
+// ignoreTagsFromMeta returns the Tags ignored for the Provider which built the specified meta
+func ignoreTagsFromMeta(meta interface{}) tags.IgnoreTags {
+	client, ok := meta.(*clients.Client)
+	if !ok || client == nil {
+		return tags.IgnoreTags{}
+	}
+
+	return client.IgnoreTags
+}
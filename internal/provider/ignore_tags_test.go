@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tags"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+func TestExpandIgnoreTags(t *testing.T) {
+	testData := []struct {
+		Name     string
+		Input    []interface{}
+		Expected tags.IgnoreTags
+	}{
+		{
+			Name:  "Omitted",
+			Input: []interface{}{},
+			Expected: tags.IgnoreTags{
+				Keys:        []string{},
+				KeyPrefixes: []string{},
+			},
+		},
+		{
+			Name:  "Empty Block",
+			Input: []interface{}{nil},
+			Expected: tags.IgnoreTags{
+				Keys:        []string{},
+				KeyPrefixes: []string{},
+			},
+		},
+		{
+			Name: "Complete",
+			Input: []interface{}{
+				map[string]interface{}{
+					"keys":         pluginsdk.NewSet(pluginsdk.HashString, []interface{}{"ms-resource-usage", "CreatedOnDate"}),
+					"key_prefixes": pluginsdk.NewSet(pluginsdk.HashString, []interface{}{"hidden-link:"}),
+				},
+			},
+			Expected: tags.IgnoreTags{
+				Keys:        []string{"CreatedOnDate", "ms-resource-usage"},
+				KeyPrefixes: []string{"hidden-link:"},
+			},
+		},
+	}
+
+	for _, testCase := range testData {
+		t.Run(testCase.Name, func(t *testing.T) {
+			result := expandIgnoreTags(testCase.Input)
+			sort.Strings(result.Keys)
+			sort.Strings(result.KeyPrefixes)
+			if !reflect.DeepEqual(result, testCase.Expected) {
+				t.Fatalf("expected %+v but got %+v", testCase.Expected, result)
+			}
+		})
+	}
+}
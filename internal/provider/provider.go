@@ -6,15 +6,21 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/hashicorp/go-azure-helpers/authentication"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/common"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/features"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/locks"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/resourceproviders"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tags"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/timeouts"
 	"github.com/hashicorp/terraform-provider-azurerm/utils"
 )
 
@@ -26,6 +32,14 @@ func TestAzureProvider() *schema.Provider {
 	return azureProvider(true)
 }
 
+// TestAzureProviderWithSendDecorators returns the Provider used for the Acceptance Tests, where
+// the specified SendDecorators are applied to every request - for example to record/replay requests
+func TestAzureProviderWithSendDecorators(sendDecorators ...autorest.SendDecorator) *schema.Provider {
+	p := azureProvider(true)
+	p.ConfigureContextFunc = providerConfigure(p, sendDecorators...)
+	return p
+}
+
 func azureProvider(supportLegacyTestSuite bool) *schema.Provider {
 	// avoids this showing up in test output
 	debugLog := func(f string, v ...interface{}) {
@@ -61,6 +75,24 @@ func azureProvider(supportLegacyTestSuite bool) *schema.Provider {
 			dataSources[key] = dataSource
 		}
 
+		if v, ok := service.(sdk.TypedServiceRegistrationWithListDataSources); ok {
+			debugLog("[DEBUG] Registering List Data Sources for %q..", service.Name())
+			for _, ds := range v.ListDataSources() {
+				key := ds.ResourceType()
+				if existing := dataSources[key]; existing != nil {
+					panic(fmt.Sprintf("An existing Data Source exists for %q", key))
+				}
+
+				wrapper := sdk.NewListDataSourceWrapper(ds)
+				dataSource, err := wrapper.DataSource()
+				if err != nil {
+					panic(fmt.Errorf("creating Wrapper for List Data Source %q: %+v", key, err))
+				}
+
+				dataSources[key] = dataSource
+			}
+		}
+
 		debugLog("[DEBUG] Registering Resources for %q..", service.Name())
 		for _, r := range service.Resources() {
 			key := r.ResourceType()
@@ -189,6 +221,40 @@ func azureProvider(supportLegacyTestSuite bool) *schema.Provider {
 				Description: "The path to a custom endpoint for Managed Service Identity - in most circumstances this should be detected automatically. ",
 			},
 
+			// OIDC specific fields
+			"use_oidc": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ARM_USE_OIDC", false),
+				Description: "Allow OIDC to be used for Authentication.",
+			},
+			"oidc_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("ARM_OIDC_TOKEN", ""),
+				Description: "The OIDC ID token for use when authenticating as a Service Principal using OpenID Connect.",
+			},
+			"oidc_token_file_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ARM_OIDC_TOKEN_FILE_PATH", ""),
+				Description: "The path to a file containing an OIDC ID token for use when authenticating as a Service Principal using OpenID Connect.",
+			},
+			"oidc_request_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"ARM_OIDC_REQUEST_URL", "ACTIONS_ID_TOKEN_REQUEST_URL"}, ""),
+				Description: "The URL for the OIDC provider from which to request an ID token. For use when authenticating as a Service Principal using OpenID Connect.",
+			},
+			"oidc_request_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"ARM_OIDC_REQUEST_TOKEN", "ACTIONS_ID_TOKEN_REQUEST_TOKEN"}, ""),
+				Description: "The bearer token for the request to the OIDC provider. For use when authenticating as a Service Principal using OpenID Connect.",
+			},
+
 			// Managed Tracking GUID for User-agent
 			"partner_id": {
 				Type:         schema.TypeString,
@@ -214,6 +280,18 @@ func azureProvider(supportLegacyTestSuite bool) *schema.Provider {
 
 			"features": schemaFeatures(supportLegacyTestSuite),
 
+			"default_tags": schemaDefaultTags(),
+
+			"default_timeouts": schemaDefaultTimeouts(),
+
+			"ignore_tags": schemaIgnoreTags(),
+
+			"rate_limit": schemaRateLimit(),
+
+			"audit_log": schemaAuditLog(),
+
+			"retry": schemaRetry(),
+
 			// Advanced feature flags
 			"skip_provider_registration": {
 				Type:        schema.TypeBool,
@@ -222,6 +300,24 @@ func azureProvider(supportLegacyTestSuite bool) *schema.Provider {
 				Description: "Should the AzureRM Provider skip registering all of the Resource Providers that it supports, if they're not already registered?",
 			},
 
+			"resource_provider_registrations": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ARM_RESOURCE_PROVIDER_REGISTRATIONS", resourceproviders.RegistrationSetExtended),
+				ValidateFunc: validation.StringInSlice(resourceproviders.RegistrationSets(), false),
+				Description:  "The set of Resource Providers which should be registered up-front, if they're not already registered. Possible values are `core`, `extended` and `none`. Other Resource Providers are registered the first time they're used.",
+			},
+
+			"resource_providers_to_register": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: resourceproviders.EnhancedValidate,
+				},
+				Description: "A list of additional Resource Providers which should be registered up-front, if they're not already registered.",
+			},
+
 			"storage_use_azuread": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -234,6 +330,14 @@ func azureProvider(supportLegacyTestSuite bool) *schema.Provider {
 		ResourcesMap:   resources,
 	}
 
+	// finally apply the `default_tags` and `ignore_tags` from the Provider block to each Resource supporting Tags
+	for _, resource := range resources {
+		if tags.SupportsProviderTags(resource) {
+			tags.AddDefaultsSupport(resource, defaultTagsFromMeta, ignoreTagsFromMeta)
+			tags.AddIgnoredSupport(resource, ignoreTagsFromMeta, p.Meta)
+		}
+	}
+
 	if !features.ThreePointOh() {
 		p.Schema["skip_credentials_validation"] = &schema.Schema{
 			Type:        schema.TypeBool,
@@ -248,7 +352,11 @@ func azureProvider(supportLegacyTestSuite bool) *schema.Provider {
 	return p
 }
 
-func providerConfigure(p *schema.Provider) schema.ConfigureContextFunc {
+func providerConfigure(p *schema.Provider, sendDecorators ...autorest.SendDecorator) schema.ConfigureContextFunc {
+	// the `default_timeouts` are applied to the Resources of this Provider only, since each Provider (e.g. an alias)
+	// has its own Resources - and so can configure different Default Timeouts
+	providerDefaultTimeouts := timeouts.NewDefaults(p.ResourcesMap)
+
 	return func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		var auxTenants []string
 		if v, ok := d.Get("auxiliary_tenant_ids").([]interface{}); ok && len(v) > 0 {
@@ -293,7 +401,22 @@ func providerConfigure(p *schema.Provider) schema.ConfigureContextFunc {
 			ClientSecretDocsLink: "https://registry.terraform.io/providers/hashicorp/azurerm/latest/docs/guides/service_principal_client_secret",
 		}
 
-		config, err := builder.Build()
+		useOIDC := d.Get("use_oidc").(bool)
+		var oidc *clients.OIDCAuth
+		var config *authentication.Config
+		var err error
+		if useOIDC {
+			// OIDC isn't supported by the authentication.Builder, so the config is built separately
+			oidc = &clients.OIDCAuth{
+				Token:         d.Get("oidc_token").(string),
+				TokenFilePath: d.Get("oidc_token_file_path").(string),
+				RequestURL:    d.Get("oidc_request_url").(string),
+				RequestToken:  d.Get("oidc_request_token").(string),
+			}
+			config, err = oidc.BuildConfig(*builder)
+		} else {
+			config, err = builder.Build()
+		}
 		if err != nil {
 			return nil, diag.FromErr(fmt.Errorf("Error building AzureRM Client: %s", err))
 		}
@@ -306,21 +429,70 @@ func providerConfigure(p *schema.Provider) schema.ConfigureContextFunc {
 		}
 
 		skipProviderRegistration := d.Get("skip_provider_registration").(bool)
+		requiredResourceProviders, err := resourceproviders.ForRegistrationSet(d.Get("resource_provider_registrations").(string), *utils.ExpandStringSlice(d.Get("resource_providers_to_register").([]interface{})))
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		if skipProviderRegistration {
+			requiredResourceProviders = make(map[string]struct{})
+		}
+
+		retry, err := expandRetry(d.Get("retry").([]interface{}))
+		if err != nil {
+			return nil, diag.FromErr(fmt.Errorf("expanding `retry`: %+v", err))
+		}
+
 		clientBuilder := clients.ClientBuilder{
 			AuthConfig:                  config,
 			SkipProviderRegistration:    skipProviderRegistration,
+			ResourceProvidersToRegister: requiredResourceProviders,
 			TerraformVersion:            terraformVersion,
 			PartnerId:                   d.Get("partner_id").(string),
 			DisableCorrelationRequestID: d.Get("disable_correlation_request_id").(bool),
 			DisableTerraformPartnerID:   d.Get("disable_terraform_partner_id").(bool),
 			Features:                    expandFeatures(d.Get("features").([]interface{})),
+			DefaultTags:                 expandDefaultTags(d.Get("default_tags").([]interface{})),
+			IgnoreTags:                  expandIgnoreTags(d.Get("ignore_tags").([]interface{})),
 			StorageUseAzureAD:           d.Get("storage_use_azuread").(bool),
+			RateLimit:                   expandRateLimit(d.Get("rate_limit").([]interface{})),
+			AuditLog:                    expandAuditLog(d.Get("audit_log").([]interface{})),
+			Retry:                       retry,
+			SendDecorators:              sendDecorators,
+			UseOIDC:                     useOIDC,
+			OIDC:                        oidc,
 
 			// this field is intentionally not exposed in the provider block, since it's only used for
 			// platform level tracing
 			CustomCorrelationRequestID: os.Getenv("ARM_CORRELATION_REQUEST_ID"),
 		}
 
+		// the audit log can also be enabled without changing the configuration, e.g. when reproducing an issue
+		if clientBuilder.AuditLog == nil {
+			if v := os.Getenv("ARM_AUDIT_LOG_PATH"); v != "" {
+				clientBuilder.AuditLog = &common.AuditLogOptions{
+					Path: v,
+				}
+			}
+		}
+
+		defaultTimeouts, err := expandDefaultTimeouts(d.Get("default_timeouts").([]interface{}))
+		if err != nil {
+			return nil, diag.FromErr(fmt.Errorf("expanding `default_timeouts`: %+v", err))
+		}
+		if err := providerDefaultTimeouts.Configure(defaultTimeouts); err != nil {
+			return nil, diag.FromErr(fmt.Errorf("configuring `default_timeouts`: %+v", err))
+		}
+
+		// this is intentionally not exposed in the provider block, since it's only used for
+		// diagnosing lock contention between resources
+		if v := os.Getenv("ARM_LOCK_WAIT_REPORT_THRESHOLD"); v != "" {
+			threshold, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, diag.FromErr(fmt.Errorf("parsing `ARM_LOCK_WAIT_REPORT_THRESHOLD` %q as a duration: %+v", v, err))
+			}
+			locks.ReportWaitsLongerThan(threshold)
+		}
+
 		stopCtx, ok := schema.StopContext(ctx) //nolint:SA1019
 		if !ok {
 			stopCtx = ctx
@@ -344,7 +516,6 @@ func providerConfigure(p *schema.Provider) schema.ConfigureContextFunc {
 			}
 
 			availableResourceProviders := providerList.Values()
-			requiredResourceProviders := resourceproviders.Required()
 
 			if err := resourceproviders.EnsureRegistered(ctx, *client.Resource.ProvidersClient, availableResourceProviders, requiredResourceProviders); err != nil {
 				return nil, diag.FromErr(fmt.Errorf(resourceProviderRegistrationErrorFmt, err))
@@ -360,8 +531,11 @@ const resourceProviderRegistrationErrorFmt = `Error ensuring Resource Providers
 Terraform automatically attempts to register the Resource Providers it supports to
 ensure it's able to provision resources.
 
-If you don't have permission to register Resource Providers you may wish to use the
-"skip_provider_registration" flag in the Provider block to disable this functionality.
+If you don't have permission to register all of these Resource Providers you may wish
+to register a smaller set of Resource Providers up-front using the
+"resource_provider_registrations" field in the Provider block (setting this to "none"
+means Resource Providers are only registered the first time they're used), or to use
+the "skip_provider_registration" flag in the Provider block to disable this functionality.
 
 Please note that if you opt out of Resource Provider Registration and Terraform tries
 to provision a resource from a Resource Provider which is unregistered, then the errors
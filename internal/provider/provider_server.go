@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/lro"
+)
+
+// providerServer wraps the gRPC Provider Server from the Plugin SDK, persisting any Long Running Operations which
+// were interrupted into the Private State for the Resource - so that these can be resumed when it's next read.
+//
+// This is necessary since the Plugin SDK doesn't expose the Private State to Resources.
+type providerServer struct {
+	tfprotov5.ProviderServer
+
+	provider *schema.Provider
+}
+
+// NewProviderServer returns the gRPC Provider Server for the specified Provider
+func NewProviderServer(provider *schema.Provider) tfprotov5.ProviderServer {
+	return &providerServer{
+		ProviderServer: schema.NewGRPCProviderServer(provider),
+		provider:       provider,
+	}
+}
+
+func (s *providerServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	pending := s.restorePendingOperation(req.TypeName, req.Private)
+
+	resp, err := s.ProviderServer.ReadResource(ctx, req)
+	if err != nil || resp == nil || pending == nil {
+		return resp, err
+	}
+
+	resp.Private = s.withPendingOperation(req.TypeName, resp.Private, lro.Take(pending.ResourceID))
+	return resp, nil
+}
+
+func (s *providerServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	resp, err := s.ProviderServer.PlanResourceChange(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	// the Plugin SDK only retains its own values in the Private State, so this needs to be carried over
+	if pending, err := lro.FromPrivateState(req.PriorPrivate); err == nil && pending != nil {
+		resp.PlannedPrivate = s.withPendingOperation(req.TypeName, resp.PlannedPrivate, pending)
+	}
+
+	return resp, nil
+}
+
+func (s *providerServer) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	s.restorePendingOperation(req.TypeName, req.PlannedPrivate)
+
+	resp, err := s.ProviderServer.ApplyResourceChange(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	if resourceId := s.resourceId(req.TypeName, resp.NewState); resourceId != "" {
+		resp.Private = s.withPendingOperation(req.TypeName, resp.Private, lro.Take(resourceId))
+	}
+
+	return resp, nil
+}
+
+// restorePendingOperation tracks the Pending Operation persisted in the Private State (if any)
+func (s *providerServer) restorePendingOperation(typeName string, private []byte) *lro.PendingOperation {
+	pending, err := lro.FromPrivateState(private)
+	if err != nil {
+		log.Printf("[DEBUG] reading the Pending Operation for %q: %+v", typeName, err)
+		return nil
+	}
+
+	if pending != nil {
+		lro.Restore(*pending)
+	}
+
+	return pending
+}
+
+// withPendingOperation returns the Private State including the Pending Operation (or without it, when nil)
+func (s *providerServer) withPendingOperation(typeName string, private []byte, pending *lro.PendingOperation) []byte {
+	output, err := lro.WithPrivateState(private, pending)
+	if err != nil {
+		log.Printf("[DEBUG] updating the Pending Operation for %q: %+v", typeName, err)
+		return private
+	}
+
+	return output
+}
+
+// resourceId returns the ID of the Resource from the specified State, if it exists
+func (s *providerServer) resourceId(typeName string, state *tfprotov5.DynamicValue) string {
+	resource, ok := s.provider.ResourcesMap[typeName]
+	if !ok || state == nil || len(state.MsgPack) == 0 {
+		return ""
+	}
+
+	value, err := msgpack.Unmarshal(state.MsgPack, resource.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		log.Printf("[DEBUG] reading the State for %q: %+v", typeName, err)
+		return ""
+	}
+
+	if value.IsNull() || !value.IsKnown() {
+		return ""
+	}
+
+	id := value.GetAttr("id")
+	if id.IsNull() || !id.IsKnown() {
+		return ""
+	}
+
+	return id.AsString()
+}
@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/lro"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+func TestProviderServerResourceId(t *testing.T) {
+	provider := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"azurerm_example": {
+				Schema: map[string]*pluginsdk.Schema{
+					"name": {
+						Type:     pluginsdk.TypeString,
+						Required: true,
+					},
+				},
+			},
+		},
+	}
+	server := NewProviderServer(provider).(*providerServer)
+
+	stateType := provider.ResourcesMap["azurerm_example"].CoreConfigSchema().ImpliedType()
+	state := func(t *testing.T, value cty.Value) *tfprotov5.DynamicValue {
+		raw, err := msgpack.Marshal(value, stateType)
+		if err != nil {
+			t.Fatalf("marshalling state: %+v", err)
+		}
+		return &tfprotov5.DynamicValue{MsgPack: raw}
+	}
+
+	testData := []struct {
+		Name     string
+		TypeName string
+		State    func(t *testing.T) *tfprotov5.DynamicValue
+		Expected string
+	}{
+		{
+			Name:     "No State",
+			TypeName: "azurerm_example",
+			State: func(t *testing.T) *tfprotov5.DynamicValue {
+				return nil
+			},
+			Expected: "",
+		},
+		{
+			Name:     "Null State",
+			TypeName: "azurerm_example",
+			State: func(t *testing.T) *tfprotov5.DynamicValue {
+				return state(t, cty.NullVal(stateType))
+			},
+			Expected: "",
+		},
+		{
+			Name:     "Unknown Resource",
+			TypeName: "azurerm_other",
+			State: func(t *testing.T) *tfprotov5.DynamicValue {
+				return state(t, cty.NullVal(stateType))
+			},
+			Expected: "",
+		},
+		{
+			Name:     "Existing",
+			TypeName: "azurerm_example",
+			State: func(t *testing.T) *tfprotov5.DynamicValue {
+				return state(t, cty.ObjectVal(map[string]cty.Value{
+					"id":   cty.StringVal("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1"),
+					"name": cty.StringVal("group1"),
+				}))
+			},
+			Expected: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1",
+		},
+	}
+
+	for _, testCase := range testData {
+		t.Run(testCase.Name, func(t *testing.T) {
+			actual := server.resourceId(testCase.TypeName, testCase.State(t))
+			if actual != testCase.Expected {
+				t.Fatalf("expected %q but got %q", testCase.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestProviderServerResumesInterruptedOperation(t *testing.T) {
+	const resourceId = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Example/examples/example1"
+
+	provisioned := false
+	checks := 0
+	provider := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"azurerm_example": {
+				CreateContext: func(_ context.Context, d *pluginsdk.ResourceData, _ interface{}) diag.Diagnostics {
+					// the Provider is interrupted whilst waiting for the creation to complete
+					d.SetId(resourceId)
+					return diag.FromErr(lro.Interrupted(resourceId, lro.OperationCreate, nil))
+				},
+				ReadContext: func(ctx context.Context, d *pluginsdk.ResourceData, _ interface{}) diag.Diagnostics {
+					return diag.FromErr(lro.ResumeFunc(ctx, d, func(_ context.Context, _ lro.PendingOperation) (bool, error) {
+						checks++
+						return provisioned, nil
+					}))
+				},
+				DeleteContext: func(_ context.Context, _ *pluginsdk.ResourceData, _ interface{}) diag.Diagnostics {
+					return nil
+				},
+				Schema: map[string]*pluginsdk.Schema{
+					"name": {
+						Type:     pluginsdk.TypeString,
+						Required: true,
+						ForceNew: true,
+					},
+				},
+			},
+		},
+	}
+	server := NewProviderServer(provider)
+
+	stateType := provider.ResourcesMap["azurerm_example"].CoreConfigSchema().ImpliedType()
+	dynamicValue := func(t *testing.T, value cty.Value) *tfprotov5.DynamicValue {
+		raw, err := msgpack.Marshal(value, stateType)
+		if err != nil {
+			t.Fatalf("marshalling value: %+v", err)
+		}
+		return &tfprotov5.DynamicValue{MsgPack: raw}
+	}
+	config := dynamicValue(t, cty.ObjectVal(map[string]cty.Value{
+		"id":   cty.NullVal(cty.String),
+		"name": cty.StringVal("example1"),
+	}))
+	pendingOperation := func(t *testing.T, private []byte) *lro.PendingOperation {
+		pending, err := lro.FromPrivateState(private)
+		if err != nil {
+			t.Fatalf("reading the Pending Operation: %+v", err)
+		}
+		return pending
+	}
+
+	plan, err := server.PlanResourceChange(context.TODO(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         "azurerm_example",
+		PriorState:       dynamicValue(t, cty.NullVal(stateType)),
+		ProposedNewState: config,
+		Config:           config,
+	})
+	if err != nil {
+		t.Fatalf("planning: %+v", err)
+	}
+	if len(plan.Diagnostics) > 0 {
+		t.Fatalf("planning: %+v", plan.Diagnostics[0])
+	}
+
+	// the creation is interrupted, which fails the apply but retains the Resource and the Pending Operation
+	apply, err := server.ApplyResourceChange(context.TODO(), &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:       "azurerm_example",
+		PriorState:     dynamicValue(t, cty.NullVal(stateType)),
+		PlannedState:   plan.PlannedState,
+		Config:         config,
+		PlannedPrivate: plan.PlannedPrivate,
+	})
+	if err != nil {
+		t.Fatalf("applying: %+v", err)
+	}
+	if len(apply.Diagnostics) != 1 || apply.Diagnostics[0].Severity != tfprotov5.DiagnosticSeverityError || !strings.Contains(apply.Diagnostics[0].Summary, "interrupted") {
+		t.Fatalf("expected the apply to fail since the creation was interrupted but got %+v", apply.Diagnostics)
+	}
+	if actual := server.(*providerServer).resourceId("azurerm_example", apply.NewState); actual != resourceId {
+		t.Fatalf("expected the Resource ID %q to be retained in the State but got %q", resourceId, actual)
+	}
+	if pending := pendingOperation(t, apply.Private); pending == nil || pending.ResourceID != resourceId {
+		t.Fatalf("expected the Pending Operation to be persisted into the Private State but got %+v", pending)
+	}
+	if pending := lro.Take(resourceId); pending != nil {
+		t.Fatalf("expected the Pending Operation to be taken from the tracked operations but got %+v", pending)
+	}
+
+	// the creation is still in progress, so remains in the Private State
+	read, err := server.ReadResource(context.TODO(), &tfprotov5.ReadResourceRequest{
+		TypeName:     "azurerm_example",
+		CurrentState: apply.NewState,
+		Private:      apply.Private,
+	})
+	if err != nil {
+		t.Fatalf("reading: %+v", err)
+	}
+	if len(read.Diagnostics) > 0 {
+		t.Fatalf("reading: %+v", read.Diagnostics[0])
+	}
+	if checks != 1 {
+		t.Fatalf("expected the Pending Operation to be checked once but got %d", checks)
+	}
+	if pending := pendingOperation(t, read.Private); pending == nil {
+		t.Fatalf("expected the Pending Operation to remain in the Private State whilst it's in progress")
+	}
+
+	// the creation has completed, so is removed from the Private State
+	provisioned = true
+	read, err = server.ReadResource(context.TODO(), &tfprotov5.ReadResourceRequest{
+		TypeName:     "azurerm_example",
+		CurrentState: read.NewState,
+		Private:      read.Private,
+	})
+	if err != nil {
+		t.Fatalf("reading: %+v", err)
+	}
+	if len(read.Diagnostics) > 0 {
+		t.Fatalf("reading: %+v", read.Diagnostics[0])
+	}
+	if checks != 2 {
+		t.Fatalf("expected the Pending Operation to be checked twice but got %d", checks)
+	}
+	if actual := server.(*providerServer).resourceId("azurerm_example", read.NewState); actual != resourceId {
+		t.Fatalf("expected the Resource ID %q to be in the State but got %q", resourceId, actual)
+	}
+	if pending := pendingOperation(t, read.Private); pending != nil {
+		t.Fatalf("expected the Pending Operation to be removed from the Private State once completed but got %+v", pending)
+	}
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/common"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/validation"
+)
+
+func schemaRateLimit() *pluginsdk.Schema {
+	return &pluginsdk.Schema{
+		Type:     pluginsdk.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &pluginsdk.Resource{
+			Schema: map[string]*pluginsdk.Schema{
+				"reads_per_hour": {
+					Type:         pluginsdk.TypeInt,
+					Optional:     true,
+					Default:      12000,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+
+				"writes_per_hour": {
+					Type:         pluginsdk.TypeInt,
+					Optional:     true,
+					Default:      1200,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+
+				"burst": {
+					Type:         pluginsdk.TypeInt,
+					Optional:     true,
+					Default:      100,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func expandRateLimit(input []interface{}) *common.RateLimitOptions {
+	// rate limiting is only enabled when the block is specified
+	if len(input) == 0 {
+		return nil
+	}
+
+	// an empty `rate_limit {}` block is returned as a nil element, which uses the default rates below
+	val := map[string]interface{}{}
+	if input[0] != nil {
+		val = input[0].(map[string]interface{})
+	}
+
+	options := common.RateLimitOptions{
+		ReadsPerHour:  12000,
+		WritesPerHour: 1200,
+		Burst:         100,
+	}
+
+	if v, ok := val["reads_per_hour"]; ok {
+		options.ReadsPerHour = v.(int)
+	}
+	if v, ok := val["writes_per_hour"]; ok {
+		options.WritesPerHour = v.(int)
+	}
+	if v, ok := val["burst"]; ok {
+		options.Burst = v.(int)
+	}
+
+	return &options
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/common"
+)
+
+func TestExpandRateLimit(t *testing.T) {
+	testData := []struct {
+		Name     string
+		Input    []interface{}
+		Expected *common.RateLimitOptions
+	}{
+		{
+			Name:     "Omitted",
+			Input:    []interface{}{},
+			Expected: nil,
+		},
+		{
+			Name:  "Empty Block",
+			Input: []interface{}{nil},
+			Expected: &common.RateLimitOptions{
+				ReadsPerHour:  12000,
+				WritesPerHour: 1200,
+				Burst:         100,
+			},
+		},
+		{
+			Name: "Complete",
+			Input: []interface{}{
+				map[string]interface{}{
+					"reads_per_hour":  6000,
+					"writes_per_hour": 600,
+					"burst":           10,
+				},
+			},
+			Expected: &common.RateLimitOptions{
+				ReadsPerHour:  6000,
+				WritesPerHour: 600,
+				Burst:         10,
+			},
+		},
+	}
+
+	for _, testCase := range testData {
+		t.Run(testCase.Name, func(t *testing.T) {
+			result := expandRateLimit(testCase.Input)
+			if !reflect.DeepEqual(result, testCase.Expected) {
+				t.Fatalf("Expected %+v but got %+v", testCase.Expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/common"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/validation"
+)
+
+func schemaRetry() *pluginsdk.Schema {
+	return &pluginsdk.Schema{
+		Type:     pluginsdk.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &pluginsdk.Resource{
+			Schema: map[string]*pluginsdk.Schema{
+				"max_attempts": {
+					Type:         pluginsdk.TypeInt,
+					Optional:     true,
+					Default:      5,
+					ValidateFunc: validation.IntBetween(1, 30),
+				},
+
+				"backoff": {
+					Type:         pluginsdk.TypeString,
+					Optional:     true,
+					Default:      common.BackoffStrategyExponential,
+					ValidateFunc: validation.StringInSlice(common.PossibleBackoffStrategyValues(), false),
+				},
+
+				"min_delay_in_seconds": {
+					Type:         pluginsdk.TypeInt,
+					Optional:     true,
+					Default:      2,
+					ValidateFunc: validation.IntBetween(0, 300),
+				},
+
+				"max_delay_in_seconds": {
+					Type:         pluginsdk.TypeInt,
+					Optional:     true,
+					Default:      60,
+					ValidateFunc: validation.IntBetween(1, 3600),
+				},
+
+				"honour_retry_after": {
+					Type:     pluginsdk.TypeBool,
+					Optional: true,
+					Default:  true,
+				},
+			},
+		},
+	}
+}
+
+func expandRetry(input []interface{}) (*common.RetryOptions, error) {
+	if len(input) == 0 {
+		return nil, nil
+	}
+
+	val := map[string]interface{}{}
+	if input[0] != nil {
+		val = input[0].(map[string]interface{})
+	}
+
+	options := common.RetryOptions{
+		MaxAttempts:      5,
+		Backoff:          common.ExponentialBackoff,
+		MinDelay:         2 * time.Second,
+		MaxDelay:         60 * time.Second,
+		HonourRetryAfter: true,
+	}
+
+	if v, ok := val["max_attempts"]; ok {
+		options.MaxAttempts = v.(int)
+	}
+	if v, ok := val["backoff"]; ok {
+		if backoff, exists := common.BackoffStrategies[v.(string)]; exists {
+			options.Backoff = backoff
+		}
+	}
+	if v, ok := val["min_delay_in_seconds"]; ok {
+		options.MinDelay = time.Duration(v.(int)) * time.Second
+	}
+	if v, ok := val["max_delay_in_seconds"]; ok {
+		options.MaxDelay = time.Duration(v.(int)) * time.Second
+	}
+	if v, ok := val["honour_retry_after"]; ok {
+		options.HonourRetryAfter = v.(bool)
+	}
+
+	if options.MinDelay > options.MaxDelay {
+		return nil, fmt.Errorf("`min_delay_in_seconds` (%s) must be less than or equal to `max_delay_in_seconds` (%s)", options.MinDelay, options.MaxDelay)
+	}
+
+	return &options, nil
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpandRetry(t *testing.T) {
+	testData := []struct {
+		Name             string
+		Input            []interface{}
+		ExpectNil        bool
+		ExpectError      bool
+		MaxAttempts      int
+		MinDelay         time.Duration
+		MaxDelay         time.Duration
+		HonourRetryAfter bool
+		BackoffAttempt3  time.Duration
+	}{
+		{
+			Name:      "Omitted",
+			Input:     []interface{}{},
+			ExpectNil: true,
+		},
+		{
+			Name:             "Empty Block",
+			Input:            []interface{}{nil},
+			MaxAttempts:      5,
+			MinDelay:         2 * time.Second,
+			MaxDelay:         60 * time.Second,
+			HonourRetryAfter: true,
+			BackoffAttempt3:  8 * time.Second,
+		},
+		{
+			Name: "Complete",
+			Input: []interface{}{
+				map[string]interface{}{
+					"max_attempts":         10,
+					"backoff":              "linear",
+					"min_delay_in_seconds": 5,
+					"max_delay_in_seconds": 120,
+					"honour_retry_after":   false,
+				},
+			},
+			MaxAttempts:      10,
+			MinDelay:         5 * time.Second,
+			MaxDelay:         120 * time.Second,
+			HonourRetryAfter: false,
+			BackoffAttempt3:  15 * time.Second,
+		},
+		{
+			Name: "Min Delay Greater Than Max Delay",
+			Input: []interface{}{
+				map[string]interface{}{
+					"min_delay_in_seconds": 120,
+					"max_delay_in_seconds": 60,
+				},
+			},
+			ExpectError: true,
+		},
+	}
+
+	for _, testCase := range testData {
+		t.Run(testCase.Name, func(t *testing.T) {
+			result, err := expandRetry(testCase.Input)
+			if err != nil {
+				if testCase.ExpectError {
+					return
+				}
+
+				t.Fatalf("expanding: %+v", err)
+			}
+			if testCase.ExpectError {
+				t.Fatalf("expected an error but didn't get one")
+			}
+
+			if testCase.ExpectNil {
+				if result != nil {
+					t.Fatalf("expected no Retry Options but got %+v", *result)
+				}
+				return
+			}
+
+			if result == nil {
+				t.Fatalf("expected Retry Options but got nil")
+			}
+			if result.MaxAttempts != testCase.MaxAttempts {
+				t.Fatalf("expected `max_attempts` to be %d but got %d", testCase.MaxAttempts, result.MaxAttempts)
+			}
+			if result.MinDelay != testCase.MinDelay {
+				t.Fatalf("expected `min_delay_in_seconds` to be %s but got %s", testCase.MinDelay, result.MinDelay)
+			}
+			if result.MaxDelay != testCase.MaxDelay {
+				t.Fatalf("expected `max_delay_in_seconds` to be %s but got %s", testCase.MaxDelay, result.MaxDelay)
+			}
+			if result.HonourRetryAfter != testCase.HonourRetryAfter {
+				t.Fatalf("expected `honour_retry_after` to be %t but got %t", testCase.HonourRetryAfter, result.HonourRetryAfter)
+			}
+			if actual := result.Backoff(3, result.MinDelay, result.MaxDelay); actual != testCase.BackoffAttempt3 {
+				t.Fatalf("expected the backoff for the 3rd attempt to be %s but got %s", testCase.BackoffAttempt3, actual)
+			}
+		})
+	}
+}
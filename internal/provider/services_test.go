@@ -12,6 +12,10 @@ func TestTypedDataSourcesContainValidModelObjects(t *testing.T) {
 		for _, resource := range service.DataSources() {
 			t.Logf("- DataSources %q..", resource.ResourceType())
 			obj := resource.ModelObject()
+			if obj == nil {
+				continue
+			}
+
 			if err := sdk.ValidateModelObject(&obj); err != nil {
 				t.Fatalf("validating model: %+v", err)
 			}
@@ -19,15 +23,53 @@ func TestTypedDataSourcesContainValidModelObjects(t *testing.T) {
 	}
 }
 
+func TestTypedDataSourcesModelObjectsMatchSchema(t *testing.T) {
+	for _, service := range SupportedTypedServices() {
+		t.Logf("Service %q..", service.Name())
+		for _, resource := range service.DataSources() {
+			t.Logf("- DataSources %q..", resource.ResourceType())
+			obj := resource.ModelObject()
+			if obj == nil {
+				continue
+			}
+
+			if err := sdk.ValidateModelObjectMatchesSchema(&obj, resource.Arguments(), resource.Attributes()); err != nil {
+				t.Fatalf("validating model for Data Source %q matches the schema: %+v", resource.ResourceType(), err)
+			}
+		}
+	}
+}
+
 func TestTypedResourcesContainValidModelObjects(t *testing.T) {
 	for _, service := range SupportedTypedServices() {
 		t.Logf("Service %q..", service.Name())
 		for _, resource := range service.Resources() {
 			t.Logf("- Resource %q..", resource.ResourceType())
 			obj := resource.ModelObject()
+			if obj == nil {
+				continue
+			}
+
 			if err := sdk.ValidateModelObject(&obj); err != nil {
 				t.Fatalf("validating model: %+v", err)
 			}
 		}
 	}
 }
+
+func TestTypedResourcesModelObjectsMatchSchema(t *testing.T) {
+	for _, service := range SupportedTypedServices() {
+		t.Logf("Service %q..", service.Name())
+		for _, resource := range service.Resources() {
+			t.Logf("- Resource %q..", resource.ResourceType())
+			obj := resource.ModelObject()
+			if obj == nil {
+				continue
+			}
+
+			if err := sdk.ValidateModelObjectMatchesSchema(&obj, resource.Arguments(), resource.Attributes()); err != nil {
+				t.Fatalf("validating model for Resource %q matches the schema: %+v", resource.ResourceType(), err)
+			}
+		}
+	}
+}
@@ -21,3 +21,25 @@ func CacheSupportedProviders(ctx context.Context, client *resources.ProvidersCli
 
 	cachedResourceProviders = providers
 }
+
+// CacheProviders caches the specified Resource Providers (for example from an Enhanced Validation Snapshot)
+// for use in enhanced validation, rather than retrieving them from the Resource Manager API
+func CacheProviders(providers []string) {
+	if len(providers) == 0 {
+		log.Printf("[DEBUG] no providers were specified. Enhanced validation will be unavailable")
+		return
+	}
+
+	cachedResourceProviders = &providers
+}
+
+// AvailableProviders returns the Resource Providers available in the Subscription, as returned from
+// the Resource Manager API - which can be used to build an Enhanced Validation Snapshot
+func AvailableProviders(ctx context.Context, client *resources.ProvidersClient) ([]string, error) {
+	providers, err := availableResourceProviders(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return *providers, nil
+}
@@ -2,10 +2,14 @@ package resourceproviders
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/2017-03-09/resources/mgmt/resources"
 	"github.com/hashicorp/go-azure-helpers/resourceproviders"
+	"github.com/hashicorp/terraform-provider-azurerm/utils"
 )
 
 func EnsureRegistered(ctx context.Context, client resources.ProvidersClient, availableRPs []resources.Provider, requiredRPs map[string]struct{}) error {
@@ -23,3 +27,43 @@ func EnsureRegistered(ctx context.Context, client resources.ProvidersClient, ava
 
 	return nil
 }
+
+const registrationDeniedErrorFmt = `The Resource Provider %[1]q isn't registered in the Subscription %[2]q and
+couldn't be registered automatically, since the credentials being used by Terraform
+don't have permission to register Resource Providers.
+
+This Resource Provider needs to be registered by someone with permission to do so,
+for example using the Azure CLI:
+
+$ az provider register --namespace %[1]s --subscription %[2]s
+
+Original Error: %[3]s`
+
+// RegisterAndWait registers the specified Resource Provider in the Subscription and waits for it to become registered
+func RegisterAndWait(ctx context.Context, client resources.ProvidersClient, subscriptionId, namespace string) error {
+	log.Printf("[DEBUG] Registering Resource Provider %q in Subscription %q..", namespace, subscriptionId)
+	resp, err := client.Register(ctx, namespace)
+	if err != nil {
+		if utils.ResponseWasForbidden(resp.Response) {
+			return fmt.Errorf(registrationDeniedErrorFmt, namespace, subscriptionId, err)
+		}
+		return fmt.Errorf("registering Resource Provider %q in Subscription %q: %+v", namespace, subscriptionId, err)
+	}
+
+	for {
+		provider, err := client.Get(ctx, namespace, "")
+		if err != nil {
+			return fmt.Errorf("retrieving Resource Provider %q in Subscription %q: %+v", namespace, subscriptionId, err)
+		}
+		if provider.RegistrationState != nil && strings.EqualFold(*provider.RegistrationState, "Registered") {
+			log.Printf("[DEBUG] Registered Resource Provider %q in Subscription %q", namespace, subscriptionId)
+			return nil
+		}
+
+		select {
+		case <-time.After(10 * time.Second):
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for Resource Provider %q to be registered in Subscription %q: %+v", namespace, subscriptionId, ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package resourceproviders
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// RegistrationSetCore registers the Resource Providers used by the most commonly used Resources
+	RegistrationSetCore = "core"
+
+	// RegistrationSetExtended registers all of the Resource Providers used by the AzureRM Provider
+	RegistrationSetExtended = "extended"
+
+	// RegistrationSetNone doesn't register any Resource Providers up-front
+	RegistrationSetNone = "none"
+)
+
+// RegistrationSets returns the names of the sets of Resource Providers which can be registered
+func RegistrationSets() []string {
+	return []string{
+		RegistrationSetCore,
+		RegistrationSetExtended,
+		RegistrationSetNone,
+	}
+}
+
+// Core returns the Resource Providers used by the most commonly used Resources - other Resource Providers
+// are registered the first time they're used, if they're not registered already
+func Core() map[string]struct{} {
+	// NOTE: Resource Providers in this list are case sensitive
+	return map[string]struct{}{
+		"Microsoft.Authorization":       {},
+		"Microsoft.Compute":             {},
+		"Microsoft.ContainerService":    {},
+		"Microsoft.KeyVault":            {},
+		"Microsoft.ManagedIdentity":     {},
+		"Microsoft.Network":             {},
+		"Microsoft.OperationalInsights": {},
+		"Microsoft.Resources":           {},
+		"Microsoft.Storage":             {},
+		"Microsoft.Web":                 {},
+		"microsoft.insights":            {},
+	}
+}
+
+// ForRegistrationSet returns the Resource Providers which should be registered for the specified Registration Set,
+// together with any additional Resource Providers which should be registered
+func ForRegistrationSet(set string, additional []string) (map[string]struct{}, error) {
+	var providers map[string]struct{}
+	switch strings.ToLower(set) {
+	case RegistrationSetCore:
+		providers = Core()
+	case RegistrationSetExtended:
+		providers = Required()
+	case RegistrationSetNone:
+		providers = make(map[string]struct{})
+	default:
+		return nil, fmt.Errorf("unsupported Resource Provider Registration Set %q - possible values are %s", set, strings.Join(RegistrationSets(), ", "))
+	}
+
+	for _, v := range additional {
+		providers[v] = struct{}{}
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,79 @@
+package resourceproviders
+
+import (
+	"testing"
+)
+
+func TestForRegistrationSet(t *testing.T) {
+	testCases := []struct {
+		set        string
+		additional []string
+		expected   int
+		contains   []string
+		error      bool
+	}{
+		{
+			set:      RegistrationSetNone,
+			expected: 0,
+		},
+		{
+			set:        RegistrationSetNone,
+			additional: []string{"Microsoft.Storage", "Microsoft.Sql"},
+			expected:   2,
+			contains:   []string{"Microsoft.Storage", "Microsoft.Sql"},
+		},
+		{
+			set:      RegistrationSetCore,
+			expected: len(Core()),
+			contains: []string{"Microsoft.Compute", "Microsoft.Network"},
+		},
+		{
+			// additional Resource Providers already in the set are only included once
+			set:        "Core",
+			additional: []string{"Microsoft.Compute", "Microsoft.Sql"},
+			expected:   len(Core()) + 1,
+			contains:   []string{"Microsoft.Compute", "Microsoft.Sql"},
+		},
+		{
+			set:      RegistrationSetExtended,
+			expected: len(Required()),
+		},
+		{
+			set:   "all",
+			error: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Logf("Testing %q with %+v..", testCase.set, testCase.additional)
+
+		actual, err := ForRegistrationSet(testCase.set, testCase.additional)
+		if err != nil {
+			if testCase.error {
+				continue
+			}
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if testCase.error {
+			t.Fatalf("expected an error but didn't get one")
+		}
+
+		if len(actual) != testCase.expected {
+			t.Fatalf("expected %d Resource Providers but got %d", testCase.expected, len(actual))
+		}
+		for _, v := range testCase.contains {
+			if _, ok := actual[v]; !ok {
+				t.Fatalf("expected %q to be included", v)
+			}
+		}
+	}
+}
+
+func TestCoreIsSubsetOfRequired(t *testing.T) {
+	required := Required()
+	for provider := range Core() {
+		if _, ok := required[provider]; !ok {
+			t.Fatalf("the Core Resource Provider %q isn't included in the Required Resource Providers", provider)
+		}
+	}
+}
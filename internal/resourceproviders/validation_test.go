@@ -84,3 +84,25 @@ func TestEnhancedValidationEnabled(t *testing.T) {
 		}
 	}
 }
+
+func TestEnhancedValidationCachedProviders(t *testing.T) {
+	enhancedEnabled = true
+	defer func() {
+		enhancedEnabled = features.EnhancedValidationEnabled()
+		cachedResourceProviders = nil
+	}()
+
+	// an empty list means enhanced validation is unavailable
+	CacheProviders([]string{})
+	if cachedResourceProviders != nil {
+		t.Fatalf("expected no providers to be cached")
+	}
+
+	CacheProviders([]string{"Microsoft.Compute", "Microsoft.Storage"})
+	if _, errors := EnhancedValidate("Microsoft.Storage", "name"); len(errors) > 0 {
+		t.Fatalf("expected %q to be valid but got %+v", "Microsoft.Storage", errors)
+	}
+	if _, errors := EnhancedValidate("Microsoft.Storag", "name"); len(errors) == 0 {
+		t.Fatalf("expected %q to be invalid", "Microsoft.Storag")
+	}
+}
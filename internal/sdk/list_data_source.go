@@ -0,0 +1,109 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// A List Data Source is an object which looks up information about multiple existing resources
+// (for example, all of the Key Vaults within a Resource Group) and returns this information
+// for use elsewhere
+//
+// The results can be filtered using the common `name_regex`, `location` and `tags` arguments
+// which are added to the Schema automatically - in addition to any service-specific Arguments
+type ListDataSource interface {
+	// Arguments is a list of the service-specific arguments used to look up the resources
+	// NOTE: the common `name_regex`, `location` and `tags` filters are added automatically
+	Arguments() map[string]*schema.Schema
+
+	// ModelObject is an instance of the object the Arguments are decoded into
+	ModelObject() interface{}
+
+	// ItemAttributes is a list of read-only attributes exposed for each of the resources found
+	ItemAttributes() map[string]*schema.Schema
+
+	// ItemModelObject is an instance of the object each of the resources found is encoded from
+	ItemModelObject() interface{}
+
+	// ItemsAttributeName is the name of the attribute containing the resources found
+	// for example `key_vaults`
+	ItemsAttributeName() string
+
+	// ResourceType is the exposed name of this data source (e.g. `azurerm_examples`)
+	ResourceType() string
+
+	// List is a ListDataSourceFunc which looks up each of the resources
+	// NOTE: the results are filtered using the common filters after this has been called
+	List() ListDataSourceFunc
+}
+
+// ListItem is a single resource found by a List Data Source, containing the common
+// fields used to filter the results client-side in addition to the Model itself
+type ListItem struct {
+	// Name is the name of this resource, which is matched against the `name_regex` filter
+	Name string
+
+	// Location is the location of this resource, which is matched against the `location` filter
+	Location string
+
+	// Tags are the tags assigned to this resource, which are matched against the `tags` filter
+	Tags map[string]string
+
+	// Model is an instance of the ItemModelObject which is encoded into the State
+	Model interface{}
+}
+
+// ListDataSourceRunFunc is the function which looks up the resources for a List Data Source
+// ctx provides a Context instance with the user-provided timeout
+// metadata is a reference to an object containing the Client, ResourceData and a Logger
+type ListDataSourceRunFunc func(ctx context.Context, metadata ResourceMetaData) ([]ListItem, error)
+
+type ListDataSourceFunc struct {
+	// Func is the function which should be called to look up the resources
+	Func ListDataSourceRunFunc
+
+	// Timeout is the default timeout, which can be overridden by users
+	// for this method - in-turn used for the Azure API
+	Timeout time.Duration
+}
+
+// Paginator is implemented by the Pages (and Iterators) returned from the List
+// operations within the Azure SDK, for example `*keyvault.VaultListResultPage`
+type Paginator interface {
+	// NotDone returns true if the page enumeration should be started or is not yet complete
+	NotDone() bool
+
+	// NextWithContext advances to the next page of values
+	NextWithContext(ctx context.Context) error
+}
+
+// ListAllPages calls pageFunc for the current page of the Paginator, advancing to the
+// next page until the results have been exhausted
+//
+// Example Usage:
+//
+//	page, err := client.ListByResourceGroup(ctx, resourceGroup)
+//	if err != nil { .. }
+//	items := make([]sdk.ListItem, 0)
+//	err = sdk.ListAllPages(ctx, &page, func() error {
+//		for _, v := range page.Values() {
+//			items = append(items, ..)
+//		}
+//		return nil
+//	})
+func ListAllPages(ctx context.Context, paginator Paginator, pageFunc func() error) error {
+	for paginator.NotDone() {
+		if err := pageFunc(); err != nil {
+			return err
+		}
+
+		if err := paginator.NextWithContext(ctx); err != nil {
+			return fmt.Errorf("retrieving the next page of results: %+v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+)
+
+var _ Paginator = &TestPaginator{}
+
+// TestPaginator is a Paginator over a fixed number of pages, which Services can use to unit test
+// the pagination logic within a List Data Source without calling the Azure API
+type TestPaginator struct {
+	// Pages is the number of pages of results available
+	Pages int
+
+	// FailOnPage optionally returns an error when advancing to this (zero-indexed) page
+	FailOnPage *int
+
+	currentPage int
+}
+
+// CurrentPage returns the (zero-indexed) page the TestPaginator is currently on
+func (p *TestPaginator) CurrentPage() int {
+	return p.currentPage
+}
+
+func (p *TestPaginator) NotDone() bool {
+	return p.currentPage < p.Pages
+}
+
+func (p *TestPaginator) NextWithContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if p.FailOnPage != nil && *p.FailOnPage == p.currentPage+1 {
+		return fmt.Errorf("retrieving page %d", p.currentPage+1)
+	}
+
+	p.currentPage++
+	return nil
+}
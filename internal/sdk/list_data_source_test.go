@@ -0,0 +1,75 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+)
+
+func TestListAllPages(t *testing.T) {
+	failOnPage := func(i int) *int {
+		return &i
+	}
+	testData := []struct {
+		name          string
+		paginator     *TestPaginator
+		expectedPages []int
+		expectError   bool
+	}{
+		{
+			name:          "no results",
+			paginator:     &TestPaginator{},
+			expectedPages: []int{},
+		},
+		{
+			name: "single page",
+			paginator: &TestPaginator{
+				Pages: 1,
+			},
+			expectedPages: []int{0},
+		},
+		{
+			name: "multiple pages",
+			paginator: &TestPaginator{
+				Pages: 3,
+			},
+			expectedPages: []int{0, 1, 2},
+		},
+		{
+			name: "error retrieving the next page",
+			paginator: &TestPaginator{
+				Pages:      3,
+				FailOnPage: failOnPage(2),
+			},
+			expectError: true,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		pages := make([]int, 0)
+		err := ListAllPages(context.TODO(), v.paginator, func() error {
+			pages = append(pages, v.paginator.CurrentPage())
+			return nil
+		})
+		if err != nil {
+			if v.expectError {
+				continue
+			}
+
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if v.expectError {
+			t.Fatalf("expected an error but didn't get one")
+		}
+
+		if len(pages) != len(v.expectedPages) {
+			t.Fatalf("expected %d pages but got %d", len(v.expectedPages), len(pages))
+		}
+		for i, page := range pages {
+			if page != v.expectedPages[i] {
+				t.Fatalf("expected page %d to be %d but got %d", i, v.expectedPages[i], page)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package sdk
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -63,9 +64,30 @@ type Resource interface {
 	IDValidationFunc() pluginsdk.SchemaValidateFunc
 }
 
-// TODO: ResourceWithCustomizeDiff
-// TODO: ResourceWithStateMigration
-// TODO: a generic state migration for updating ID's
+// ResourceWithCustomizeDiff is an optional interface
+//
+// Resources implementing this interface can inspect (and modify) the Plan
+// before it's presented to the user, for example to validate that a
+// combination of fields is valid, or to mark a field as ForceNew conditionally.
+type ResourceWithCustomizeDiff interface {
+	Resource
+
+	// CustomizeDiff returns a ResourceFunc which is run during the Plan
+	// NOTE: the ResourceMetaData passed into this function exposes the ResourceDiff
+	// (rather than the ResourceData), which Decode will use to retrieve values
+	CustomizeDiff() ResourceFunc
+}
+
+// ResourceWithStateMigration is an optional interface
+//
+// Resources implementing this interface can declare the current Schema Version
+// and the State Upgrades required to migrate the State from prior versions.
+type ResourceWithStateMigration interface {
+	Resource
+
+	// StateUpgraders returns the Schema Version and the State Upgrades for this Resource
+	StateUpgraders() StateUpgradeData
+}
 
 type ResourceWithCustomImporter interface {
 	Resource
@@ -127,12 +149,21 @@ type ResourceMetaData struct {
 	// for example, to determine if a field has changes
 	ResourceData *schema.ResourceData
 
+	// ResourceDiff is a reference to the ResourceDiff object from Terraform's Plugin SDK
+	// This is only populated during CustomizeDiff (at which point ResourceData is nil)
+	// and can be used to determine which fields have changes, or to mark fields as ForceNew
+	ResourceDiff *schema.ResourceDiff
+
 	// serializationDebugLogger is used for testing purposes
 	serializationDebugLogger Logger
 }
 
 // MarkAsGone marks this resource as removed in the Remote API, so this is no longer available
 func (rmd ResourceMetaData) MarkAsGone(idFormatter resourceid.Formatter) error {
+	if rmd.ResourceData == nil {
+		return fmt.Errorf("a resource can't be marked as gone during CustomizeDiff")
+	}
+
 	rmd.Logger.Infof("[DEBUG] %s was not found - removing from state", idFormatter)
 	rmd.ResourceData.SetId("")
 	return nil
@@ -3,6 +3,7 @@ package sdk
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -18,7 +19,20 @@ import (
 // }
 // var person Person
 // if err := metadata.Decode(&person); err != nil { .. }
+//
+// NOTE: when called from within CustomizeDiff this decodes the values from the Plan
+//
+// Pointer fields (e.g. `*string`) are only populated when a value has been specified,
+// allowing an unset field to be distinguished from the zero value. Within nested blocks
+// this is determined per nested path (e.g. `sku.0.capacity`) - however since the Plugin SDK
+// stores the zero value for unset fields within a nested block, this is only possible from
+// the Configuration/Plan (e.g. during Create or CustomizeDiff) rather than from the State,
+// and isn't possible for fields within a Set.
 func (rmd ResourceMetaData) Decode(input interface{}) error {
+	if rmd.ResourceDiff != nil {
+		return decodeReflectedType(input, rmd.ResourceDiff, rmd.serializationDebugLogger)
+	}
+
 	return decodeReflectedType(input, rmd.ResourceData, rmd.serializationDebugLogger)
 }
 
@@ -48,8 +62,7 @@ func decodeReflectedType(input interface{}, stateRetriever stateRetriever, debug
 			debugLogger.Infof("TFSchemaValue: ", tfschemaValue)
 			debugLogger.Infof("Input Type: ", reflect.ValueOf(input).Elem().Field(i).Type())
 
-			fieldName := reflect.ValueOf(input).Elem().Field(i).String()
-			if err := setValue(input, tfschemaValue, i, fieldName, debugLogger); err != nil {
+			if err := setValue(input, tfschemaValue, i, field.Name, val, stateRetriever, debugLogger); err != nil {
 				return err
 			}
 		}
@@ -57,7 +70,10 @@ func decodeReflectedType(input interface{}, stateRetriever stateRetriever, debug
 	return nil
 }
 
-func setValue(input, tfschemaValue interface{}, index int, fieldName string, debugLogger Logger) (errOut error) {
+// setValue sets the value for the field at the specified index, where path is the path to this field
+// within the Schema (e.g. `sku.0.capacity`) - which is used to determine whether fields within nested
+// blocks have been specified. An empty path means this can't be determined (e.g. within a Set)
+func setValue(input, tfschemaValue interface{}, index int, fieldName, path string, stateRetriever stateRetriever, debugLogger Logger) (errOut error) {
 	debugLogger.Infof("setting list value for %q..", fieldName)
 	defer func() {
 		if r := recover(); r != nil {
@@ -71,35 +87,63 @@ func setValue(input, tfschemaValue interface{}, index int, fieldName string, deb
 		}
 	}()
 
+	field := reflect.ValueOf(input).Elem().Field(index)
+	return setFieldValue(field, tfschemaValue, fieldName, path, stateRetriever, debugLogger)
+}
+
+func setFieldValue(field reflect.Value, tfschemaValue interface{}, fieldName, path string, stateRetriever stateRetriever, debugLogger Logger) error {
+	if field.Kind() == reflect.Ptr {
+		// a nil value means this field wasn't specified, so we leave the pointer as nil
+		// to allow distinguishing between the field being unset and the zero value
+		if tfschemaValue == nil {
+			return nil
+		}
+
+		// within a nested block the zero value is returned for unset fields, so check the path
+		// was specified - this is unnecessary at the top-level, since this is checked by Decode
+		if strings.Contains(path, ".") {
+			if _, exists := stateRetriever.GetOkExists(path); !exists {
+				return nil
+			}
+		}
+
+		debugLogger.Infof("[POINTER] Decode %+v", tfschemaValue)
+		value := reflect.New(field.Type().Elem())
+		if err := setFieldValue(value.Elem(), tfschemaValue, fieldName, path, stateRetriever, debugLogger); err != nil {
+			return err
+		}
+
+		field.Set(value)
+		return nil
+	}
+
 	if v, ok := tfschemaValue.(string); ok {
 		debugLogger.Infof("[String] Decode %+v", v)
-		debugLogger.Infof("Input %+v", reflect.ValueOf(input))
-		debugLogger.Infof("Input Elem %+v", reflect.ValueOf(input).Elem())
-		reflect.ValueOf(input).Elem().Field(index).SetString(v)
+		field.SetString(v)
 		return nil
 	}
 
 	if v, ok := tfschemaValue.(int); ok {
 		debugLogger.Infof("[INT] Decode %+v", v)
-		reflect.ValueOf(input).Elem().Field(index).SetInt(int64(v))
+		field.SetInt(int64(v))
 		return nil
 	}
 
 	if v, ok := tfschemaValue.(int32); ok {
 		debugLogger.Infof("[INT] Decode %+v", v)
-		reflect.ValueOf(input).Elem().Field(index).SetInt(int64(v))
+		field.SetInt(int64(v))
 		return nil
 	}
 
 	if v, ok := tfschemaValue.(int64); ok {
 		debugLogger.Infof("[INT] Decode %+v", v)
-		reflect.ValueOf(input).Elem().Field(index).SetInt(v)
+		field.SetInt(v)
 		return nil
 	}
 
 	if v, ok := tfschemaValue.(float64); ok {
 		debugLogger.Infof("[Float] Decode %+v", v)
-		reflect.ValueOf(input).Elem().Field(index).SetFloat(v)
+		field.SetFloat(v)
 		return nil
 	}
 
@@ -107,98 +151,110 @@ func setValue(input, tfschemaValue interface{}, index int, fieldName string, deb
 	if v, ok := tfschemaValue.(bool); ok {
 		debugLogger.Infof("[BOOL] Decode %+v", v)
 
-		reflect.ValueOf(input).Elem().Field(index).SetBool(v)
+		field.SetBool(v)
 		return nil
 	}
 
 	if v, ok := tfschemaValue.(*schema.Set); ok {
-		return setListValue(input, index, fieldName, v.List(), debugLogger)
+		// the items within a Set are addressed by their hash, so the paths within it aren't known
+		return setListValue(field, fieldName, "", v.List(), stateRetriever, debugLogger)
 	}
 
 	if mapConfig, ok := tfschemaValue.(map[string]interface{}); ok {
-		mapOutput := reflect.MakeMap(reflect.ValueOf(input).Elem().Field(index).Type())
+		mapOutput := reflect.MakeMap(field.Type())
 		for key, val := range mapConfig {
 			mapOutput.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(val))
 		}
 
-		reflect.ValueOf(input).Elem().Field(index).Set(mapOutput)
+		field.Set(mapOutput)
 		return nil
 	}
 
 	if v, ok := tfschemaValue.([]interface{}); ok {
-		return setListValue(input, index, fieldName, v, debugLogger)
+		return setListValue(field, fieldName, path, v, stateRetriever, debugLogger)
 	}
 
 	return nil
 }
 
-func setListValue(input interface{}, index int, fieldName string, v []interface{}, debugLogger Logger) error {
-	switch fieldType := reflect.ValueOf(input).Elem().Field(index).Type(); fieldType {
+func setListValue(field reflect.Value, fieldName, path string, v []interface{}, stateRetriever stateRetriever, debugLogger Logger) error {
+	switch fieldType := field.Type(); fieldType {
 	case reflect.TypeOf([]string{}):
 		stringSlice := reflect.MakeSlice(reflect.TypeOf([]string{}), len(v), len(v))
 		for i, stringVal := range v {
 			stringSlice.Index(i).SetString(stringVal.(string))
 		}
-		reflect.ValueOf(input).Elem().Field(index).Set(stringSlice)
+		field.Set(stringSlice)
 
 	case reflect.TypeOf([]int{}):
 		iSlice := reflect.MakeSlice(reflect.TypeOf([]int{}), len(v), len(v))
 		for i, iVal := range v {
 			iSlice.Index(i).SetInt(int64(iVal.(int)))
 		}
-		reflect.ValueOf(input).Elem().Field(index).Set(iSlice)
+		field.Set(iSlice)
 
 	case reflect.TypeOf([]float64{}):
 		fSlice := reflect.MakeSlice(reflect.TypeOf([]float64{}), len(v), len(v))
 		for i, fVal := range v {
 			fSlice.Index(i).SetFloat(fVal.(float64))
 		}
-		reflect.ValueOf(input).Elem().Field(index).Set(fSlice)
+		field.Set(fSlice)
 
 	case reflect.TypeOf([]bool{}):
 		bSlice := reflect.MakeSlice(reflect.TypeOf([]bool{}), len(v), len(v))
 		for i, bVal := range v {
 			bSlice.Index(i).SetBool(bVal.(bool))
 		}
-		reflect.ValueOf(input).Elem().Field(index).Set(bSlice)
+		field.Set(bSlice)
 
 	default:
-		valueToSet := reflect.MakeSlice(reflect.ValueOf(input).Elem().Field(index).Type(), 0, 0)
+		if fieldType.Kind() != reflect.Slice {
+			return fmt.Errorf("unable to decode a list into %q since this is a %s", fieldName, fieldType)
+		}
+
+		// nested blocks can be either a slice of structs, or a slice of pointers to structs
+		elemType := fieldType.Elem()
+		elemIsPointer := elemType.Kind() == reflect.Ptr
+		if elemIsPointer {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() != reflect.Struct {
+			return fmt.Errorf("unable to decode a list into %q since %s is not a struct", fieldName, elemType)
+		}
+
+		valueToSet := reflect.MakeSlice(fieldType, 0, len(v))
 		debugLogger.Infof("List Type", valueToSet.Type())
 
-		for _, mapVal := range v {
+		for i, mapVal := range v {
 			if test, ok := mapVal.(map[string]interface{}); ok && test != nil {
-				elem := reflect.New(fieldType.Elem())
+				elem := reflect.New(elemType)
 				debugLogger.Infof("element ", elem)
-				for j := 0; j < elem.Type().Elem().NumField(); j++ {
-					nestedField := elem.Type().Elem().Field(j)
+				for j := 0; j < elemType.NumField(); j++ {
+					nestedField := elemType.Field(j)
 					debugLogger.Infof("nestedField ", nestedField)
 
 					if val, exists := nestedField.Tag.Lookup("tfschema"); exists {
 						nestedTFSchemaValue := test[val]
-						if err := setValue(elem.Interface(), nestedTFSchemaValue, j, fieldName, debugLogger); err != nil {
+						nestedPath := ""
+						if path != "" {
+							nestedPath = fmt.Sprintf("%s.%d.%s", path, i, val)
+						}
+						if err := setValue(elem.Interface(), nestedTFSchemaValue, j, nestedField.Name, nestedPath, stateRetriever, debugLogger); err != nil {
 							return err
 						}
 					}
 				}
 
-				if !elem.CanSet() {
+				if !elemIsPointer {
 					elem = elem.Elem()
 				}
-
-				if valueToSet.Kind() == reflect.Ptr {
-					valueToSet.Elem().Set(reflect.Append(valueToSet.Elem(), elem))
-				} else {
-					valueToSet = reflect.Append(valueToSet, elem)
-				}
+				valueToSet = reflect.Append(valueToSet, elem)
 
 				debugLogger.Infof("value to set type after changes", valueToSet.Type())
 			}
 		}
 
-		valueToSet = reflect.Indirect(valueToSet)
-		fieldToSet := reflect.ValueOf(input).Elem().Field(index)
-		fieldToSet.Set(reflect.Indirect(valueToSet))
+		field.Set(valueToSet)
 	}
 
 	return nil
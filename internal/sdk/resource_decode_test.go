@@ -2,7 +2,11 @@ package sdk
 
 import (
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 type decodeTestData struct {
@@ -698,6 +702,155 @@ func TestResourceDecode_NestedThreeLevelsDeepMultipleItems(t *testing.T) {
 	}.test(t)
 }
 
+func TestDecode_TopLevelPointers(t *testing.T) {
+	type SimpleType struct {
+		String       *string                `tfschema:"string"`
+		Number       *int                   `tfschema:"number"`
+		Price        *float64               `tfschema:"price"`
+		Enabled      *bool                  `tfschema:"enabled"`
+		EmptyString  *string                `tfschema:"empty_string"`
+		ZeroNumber   *int                   `tfschema:"zero_number"`
+		Disabled     *bool                  `tfschema:"disabled"`
+		Unset        *string                `tfschema:"unset"`
+		MapOfAnyType map[string]interface{} `tfschema:"map_of_any_type"`
+	}
+	str := "world"
+	number := 42
+	price := 129.99
+	enabled := true
+	emptyString := ""
+	zeroNumber := 0
+	disabled := false
+	decodeTestData{
+		State: map[string]interface{}{
+			"string":       "world",
+			"number":       int64(42),
+			"price":        float64(129.99),
+			"enabled":      true,
+			"empty_string": "",
+			"zero_number":  0,
+			"disabled":     false,
+			"map_of_any_type": map[string]interface{}{
+				"hello": "world",
+				"count": 2,
+			},
+		},
+		Input: &SimpleType{},
+		Expected: &SimpleType{
+			String:      &str,
+			Number:      &number,
+			Price:       &price,
+			Enabled:     &enabled,
+			EmptyString: &emptyString,
+			ZeroNumber:  &zeroNumber,
+			Disabled:    &disabled,
+			Unset:       nil,
+			MapOfAnyType: map[string]interface{}{
+				"hello": "world",
+				"count": 2,
+			},
+		},
+	}.test(t)
+}
+
+func TestResourceDecode_NestedPointers(t *testing.T) {
+	type Inner struct {
+		Value    string  `tfschema:"value"`
+		Optional *string `tfschema:"optional"`
+		Number   *int    `tfschema:"number"`
+	}
+	type Type struct {
+		NestedObject []*Inner `tfschema:"inner"`
+	}
+	optional := "bingo"
+	number := 0
+	decodeTestData{
+		State: map[string]interface{}{
+			"inner": []interface{}{
+				map[string]interface{}{
+					"value":    "first",
+					"optional": "bingo",
+					"number":   0,
+				},
+				map[string]interface{}{
+					"value": "second",
+				},
+			},
+		},
+		Input: &Type{},
+		Expected: &Type{
+			NestedObject: []*Inner{
+				{
+					Value:    "first",
+					Optional: &optional,
+					Number:   &number,
+				},
+				{
+					Value: "second",
+				},
+			},
+		},
+	}.test(t)
+}
+
+func TestResourceDecode_NestedSetOfObjects(t *testing.T) {
+	type InnerSet struct {
+		Name    string `tfschema:"name"`
+		Enabled bool   `tfschema:"enabled"`
+	}
+	type Inner struct {
+		Value string     `tfschema:"value"`
+		Set   []InnerSet `tfschema:"set"`
+	}
+	type Type struct {
+		NestedObject []Inner `tfschema:"inner"`
+	}
+	decodeTestData{
+		State: map[string]interface{}{
+			"inner": schema.NewSet(func(_ interface{}) int { return 1 }, []interface{}{
+				map[string]interface{}{
+					"value": "first",
+					"set": schema.NewSet(func(_ interface{}) int { return 2 }, []interface{}{
+						map[string]interface{}{
+							"name":    "bingo",
+							"enabled": true,
+						},
+					}),
+				},
+			}),
+		},
+		Input: &Type{},
+		Expected: &Type{
+			NestedObject: []Inner{
+				{
+					Value: "first",
+					Set: []InnerSet{
+						{
+							Name:    "bingo",
+							Enabled: true,
+						},
+					},
+				},
+			},
+		},
+	}.test(t)
+}
+
+func TestResourceDecode_ListIntoUnsupportedType(t *testing.T) {
+	type Type struct {
+		NestedObject []*string `tfschema:"inner"`
+	}
+	decodeTestData{
+		State: map[string]interface{}{
+			"inner": []interface{}{
+				"hello",
+			},
+		},
+		Input:       &Type{},
+		ExpectError: true,
+	}.test(t)
+}
+
 func (testData decodeTestData) test(t *testing.T) {
 	debugLogger := ConsoleLogger{}
 	state := testData.stateWrapper()
@@ -738,7 +891,96 @@ func (td testDataGetter) GetOk(key string) (interface{}, bool) {
 }
 
 func (td testDataGetter) GetOkExists(key string) (interface{}, bool) {
-	// for the purposes of this test this should be sufficient, maybe?
-	val, ok := td.values[key]
-	return val, ok
+	// nested paths (e.g. `inner.0.value`) are looked up by walking the lists/maps
+	var val interface{} = td.values
+	for _, segment := range strings.Split(key, ".") {
+		switch v := val.(type) {
+		case map[string]interface{}:
+			item, ok := v[segment]
+			if !ok {
+				return nil, false
+			}
+			val = item
+
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index >= len(v) {
+				return nil, false
+			}
+			val = v[index]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return val, true
+}
+
+func TestResourceDecode_NestedPointersFromConfig(t *testing.T) {
+	type Inner struct {
+		Value    string  `tfschema:"value"`
+		Optional *string `tfschema:"optional"`
+		Number   *int    `tfschema:"number"`
+	}
+	type Type struct {
+		Inner []Inner `tfschema:"inner"`
+	}
+
+	resourceSchema := map[string]*schema.Schema{
+		"inner": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"value": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"optional": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"number": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+				},
+			},
+		},
+	}
+	// the Plugin SDK returns the zero value for the unset fields within a nested block
+	// so these should be determined from the Configuration
+	d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{
+		"inner": []interface{}{
+			map[string]interface{}{
+				"value":  "first",
+				"number": 0,
+			},
+			map[string]interface{}{
+				"value": "second",
+			},
+		},
+	})
+
+	var actual Type
+	if err := decodeReflectedType(&actual, d, ConsoleLogger{}); err != nil {
+		t.Fatalf("decoding: %+v", err)
+	}
+
+	number := 0
+	expected := Type{
+		Inner: []Inner{
+			{
+				Value:  "first",
+				Number: &number,
+			},
+			{
+				Value: "second",
+			},
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("\nExpected: %+v\n\n Received %+v\n\n", expected, actual)
+	}
 }
@@ -8,11 +8,18 @@ import (
 // Encode will encode the specified object into the Terraform State
 // NOTE: this requires that the object passed in is a pointer and
 // all fields contain `tfschema` struct tags
+//
+// Nil pointer fields are unset in the Terraform State - and nested blocks can be
+// represented as either a slice of structs, or a slice of pointers to structs
 func (rmd ResourceMetaData) Encode(input interface{}) error {
 	if reflect.TypeOf(input).Kind() != reflect.Ptr {
 		return fmt.Errorf("need a pointer")
 	}
 
+	if rmd.ResourceData == nil {
+		return fmt.Errorf("values can't be encoded into the state during CustomizeDiff")
+	}
+
 	objType := reflect.TypeOf(input).Elem()
 	objVal := reflect.ValueOf(input).Elem()
 
@@ -49,93 +56,116 @@ func recurse(objType reflect.Type, objVal reflect.Value, fieldName string, debug
 		field := objType.Field(i)
 		fieldVal := objVal.Field(i)
 		if tfschemaTag, exists := field.Tag.Lookup("tfschema"); exists {
-			switch field.Type.Kind() {
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				iv := fieldVal.Int()
-				debugLogger.Infof("Setting %q to %d", tfschemaTag, iv)
-				output[tfschemaTag] = iv
-
-			case reflect.Float32, reflect.Float64:
-				fv := fieldVal.Float()
-				debugLogger.Infof("Setting %q to %f", tfschemaTag, fv)
-				output[tfschemaTag] = fv
-
-			case reflect.String:
-				sv := fieldVal.String()
-				debugLogger.Infof("Setting %q to %q", tfschemaTag, sv)
-				output[tfschemaTag] = sv
-
-			case reflect.Bool:
-				bv := fieldVal.Bool()
-				debugLogger.Infof("Setting %q to %t", tfschemaTag, bv)
-				output[tfschemaTag] = bv
-
-			case reflect.Map:
-				iter := fieldVal.MapRange()
-				attr := make(map[string]interface{})
-				for iter.Next() {
-					attr[iter.Key().String()] = iter.Value().Interface()
-				}
-				output[tfschemaTag] = attr
-
-			case reflect.Slice:
-				sv := fieldVal.Slice(0, fieldVal.Len())
-				attr := make([]interface{}, sv.Len())
-				switch sv.Type() {
-				case reflect.TypeOf([]string{}):
-					debugLogger.Infof("Setting %q to []string", tfschemaTag)
-					if sv.Len() > 0 {
-						output[tfschemaTag] = sv.Interface()
-					} else {
-						output[tfschemaTag] = make([]string, 0)
-					}
+			serialized, err := encodeValue(tfschemaTag, field.Name, fieldVal, debugLogger)
+			if err != nil {
+				return output, err
+			}
 
-				case reflect.TypeOf([]int{}):
-					debugLogger.Infof("Setting %q to []int", tfschemaTag)
-					if sv.Len() > 0 {
-						output[tfschemaTag] = sv.Interface()
-					} else {
-						output[tfschemaTag] = make([]int, 0)
-					}
+			output[tfschemaTag] = serialized
+		}
+	}
 
-				case reflect.TypeOf([]float64{}):
-					debugLogger.Infof("Setting %q to []float64", tfschemaTag)
-					if sv.Len() > 0 {
-						output[tfschemaTag] = sv.Interface()
-					} else {
-						output[tfschemaTag] = make([]float64, 0)
-					}
+	return output, nil
+}
 
-				case reflect.TypeOf([]bool{}):
-					debugLogger.Infof("Setting %q to []bool", tfschemaTag)
-					if sv.Len() > 0 {
-						output[tfschemaTag] = sv.Interface()
-					} else {
-						output[tfschemaTag] = make([]bool, 0)
-					}
+func encodeValue(tfschemaTag string, fieldName string, fieldVal reflect.Value, debugLogger Logger) (interface{}, error) {
+	switch fieldVal.Kind() {
+	case reflect.Ptr:
+		// a nil pointer means this field has no value, which unsets it in the state
+		if fieldVal.IsNil() {
+			debugLogger.Infof("Setting %q to nil", tfschemaTag)
+			return nil, nil
+		}
 
-				default:
-					for i := 0; i < sv.Len(); i++ {
-						debugLogger.Infof("[SLICE] Index %d is %q", i, sv.Index(i).Interface())
-						debugLogger.Infof("[SLICE] Type %+v", sv.Type())
-						nestedType := sv.Index(i).Type()
-						nestedValue := sv.Index(i)
-
-						fieldName := field.Name
-						serialized, err := recurse(nestedType, nestedValue, fieldName, debugLogger)
-						if err != nil {
-							return nil, fmt.Errorf("serializing nested object %q: %+v", sv.Type(), exists)
-						}
-						attr[i] = serialized
+		return encodeValue(tfschemaTag, fieldName, fieldVal.Elem(), debugLogger)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		iv := fieldVal.Int()
+		debugLogger.Infof("Setting %q to %d", tfschemaTag, iv)
+		return iv, nil
+
+	case reflect.Float32, reflect.Float64:
+		fv := fieldVal.Float()
+		debugLogger.Infof("Setting %q to %f", tfschemaTag, fv)
+		return fv, nil
+
+	case reflect.String:
+		sv := fieldVal.String()
+		debugLogger.Infof("Setting %q to %q", tfschemaTag, sv)
+		return sv, nil
+
+	case reflect.Bool:
+		bv := fieldVal.Bool()
+		debugLogger.Infof("Setting %q to %t", tfschemaTag, bv)
+		return bv, nil
+
+	case reflect.Map:
+		iter := fieldVal.MapRange()
+		attr := make(map[string]interface{})
+		for iter.Next() {
+			attr[iter.Key().String()] = iter.Value().Interface()
+		}
+		return attr, nil
+
+	case reflect.Slice:
+		sv := fieldVal.Slice(0, fieldVal.Len())
+		switch sv.Type() {
+		case reflect.TypeOf([]string{}):
+			debugLogger.Infof("Setting %q to []string", tfschemaTag)
+			if sv.Len() > 0 {
+				return sv.Interface(), nil
+			}
+			return make([]string, 0), nil
+
+		case reflect.TypeOf([]int{}):
+			debugLogger.Infof("Setting %q to []int", tfschemaTag)
+			if sv.Len() > 0 {
+				return sv.Interface(), nil
+			}
+			return make([]int, 0), nil
+
+		case reflect.TypeOf([]float64{}):
+			debugLogger.Infof("Setting %q to []float64", tfschemaTag)
+			if sv.Len() > 0 {
+				return sv.Interface(), nil
+			}
+			return make([]float64, 0), nil
+
+		case reflect.TypeOf([]bool{}):
+			debugLogger.Infof("Setting %q to []bool", tfschemaTag)
+			if sv.Len() > 0 {
+				return sv.Interface(), nil
+			}
+			return make([]bool, 0), nil
+
+		default:
+			attr := make([]interface{}, 0, sv.Len())
+			for i := 0; i < sv.Len(); i++ {
+				debugLogger.Infof("[SLICE] Index %d is %q", i, sv.Index(i).Interface())
+				debugLogger.Infof("[SLICE] Type %+v", sv.Type())
+				nestedValue := sv.Index(i)
+
+				// nested blocks can be either a slice of structs, or a slice of pointers to structs
+				if nestedValue.Kind() == reflect.Ptr {
+					if nestedValue.IsNil() {
+						continue
 					}
-					debugLogger.Infof("[SLICE] Setting %q to %+v", tfschemaTag, attr)
-					output[tfschemaTag] = attr
+					nestedValue = nestedValue.Elem()
+				}
+				if nestedValue.Kind() != reflect.Struct {
+					return nil, fmt.Errorf("unknown type %+v for key %q", sv.Type(), tfschemaTag)
 				}
-			default:
-				return output, fmt.Errorf("unknown type %+v for key %q", field.Type.Kind(), tfschemaTag)
+
+				serialized, err := recurse(nestedValue.Type(), nestedValue, fieldName, debugLogger)
+				if err != nil {
+					return nil, fmt.Errorf("serializing nested object %q: %+v", sv.Type(), err)
+				}
+				attr = append(attr, serialized)
 			}
+			debugLogger.Infof("[SLICE] Setting %q to %+v", tfschemaTag, attr)
+			return attr, nil
 		}
 	}
 
-	return output, nil
+	return nil, fmt.Errorf("unknown type %+v for key %q", fieldVal.Kind(), tfschemaTag)
 }
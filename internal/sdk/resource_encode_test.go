@@ -647,6 +647,180 @@ func TestResourceEncode_NestedThreeLevelsDeepMultipleItems(t *testing.T) {
 	}.test(t)
 }
 
+func TestResourceEncode_TopLevelPointers(t *testing.T) {
+	type SimpleType struct {
+		String       *string                `tfschema:"string"`
+		Number       *int                   `tfschema:"number"`
+		Price        *float64               `tfschema:"price"`
+		Enabled      *bool                  `tfschema:"enabled"`
+		EmptyString  *string                `tfschema:"empty_string"`
+		ZeroNumber   *int                   `tfschema:"zero_number"`
+		Disabled     *bool                  `tfschema:"disabled"`
+		MapOfAnyType map[string]interface{} `tfschema:"map_of_any_type"`
+	}
+	str := "world"
+	number := 42
+	price := 129.99
+	enabled := true
+	emptyString := ""
+	zeroNumber := 0
+	disabled := false
+	encodeTestData{
+		Input: &SimpleType{
+			String:      &str,
+			Number:      &number,
+			Price:       &price,
+			Enabled:     &enabled,
+			EmptyString: &emptyString,
+			ZeroNumber:  &zeroNumber,
+			Disabled:    &disabled,
+			MapOfAnyType: map[string]interface{}{
+				"hello": "world",
+				"count": 2,
+			},
+		},
+		Expected: map[string]interface{}{
+			"string":       "world",
+			"number":       int64(42),
+			"price":        float64(129.99),
+			"enabled":      true,
+			"empty_string": "",
+			"zero_number":  int64(0),
+			"disabled":     false,
+			"map_of_any_type": map[string]interface{}{
+				"hello": "world",
+				"count": 2,
+			},
+		},
+	}.test(t)
+}
+
+func TestResourceEncode_TopLevelPointersOmitted(t *testing.T) {
+	type SimpleType struct {
+		String  *string  `tfschema:"string"`
+		Number  *int     `tfschema:"number"`
+		Price   *float64 `tfschema:"price"`
+		Enabled *bool    `tfschema:"enabled"`
+	}
+	encodeTestData{
+		Input: &SimpleType{},
+		Expected: map[string]interface{}{
+			"string":  nil,
+			"number":  nil,
+			"price":   nil,
+			"enabled": nil,
+		},
+	}.test(t)
+}
+
+func TestResourceEncode_TopLevelPointerToStruct(t *testing.T) {
+	type Inner struct {
+		Value string `tfschema:"value"`
+	}
+	type Type struct {
+		Inner *Inner `tfschema:"inner"`
+	}
+	encodeTestData{
+		Input: &Type{
+			Inner: &Inner{
+				Value: "hello",
+			},
+		},
+		ExpectError: true,
+	}.test(t)
+}
+
+func TestResourceEncode_NestedPointers(t *testing.T) {
+	type Inner struct {
+		Value    string  `tfschema:"value"`
+		Optional *string `tfschema:"optional"`
+		Number   *int    `tfschema:"number"`
+	}
+	type Type struct {
+		NestedObject []*Inner `tfschema:"inner"`
+	}
+	optional := "bingo"
+	number := 0
+	encodeTestData{
+		Input: &Type{
+			NestedObject: []*Inner{
+				{
+					Value:    "first",
+					Optional: &optional,
+					Number:   &number,
+				},
+				nil,
+				{
+					Value: "second",
+				},
+			},
+		},
+		Expected: map[string]interface{}{
+			"inner": []interface{}{
+				map[string]interface{}{
+					"value":    "first",
+					"optional": "bingo",
+					"number":   int64(0),
+				},
+				map[string]interface{}{
+					"value":    "second",
+					"optional": nil,
+					"number":   nil,
+				},
+			},
+		},
+	}.test(t)
+}
+
+func TestResourceEncode_NestedSetOfObjects(t *testing.T) {
+	type InnerSet struct {
+		Name    string `tfschema:"name"`
+		Enabled bool   `tfschema:"enabled"`
+	}
+	type Inner struct {
+		Value string     `tfschema:"value"`
+		Set   []InnerSet `tfschema:"set"`
+	}
+	type Type struct {
+		NestedObject []Inner `tfschema:"inner"`
+	}
+	encodeTestData{
+		Input: &Type{
+			NestedObject: []Inner{
+				{
+					Value: "first",
+					Set: []InnerSet{
+						{
+							Name:    "bingo",
+							Enabled: true,
+						},
+						{
+							Name: "bango",
+						},
+					},
+				},
+			},
+		},
+		Expected: map[string]interface{}{
+			"inner": []interface{}{
+				map[string]interface{}{
+					"value": "first",
+					"set": []interface{}{
+						map[string]interface{}{
+							"name":    "bingo",
+							"enabled": true,
+						},
+						map[string]interface{}{
+							"name":    "bango",
+							"enabled": false,
+						},
+					},
+				},
+			},
+		},
+	}.test(t)
+}
+
 func (testData encodeTestData) test(t *testing.T) {
 	objType := reflect.TypeOf(testData.Input).Elem()
 	objVal := reflect.ValueOf(testData.Input).Elem()
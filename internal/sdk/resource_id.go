@@ -4,5 +4,11 @@ import "github.com/hashicorp/terraform-provider-azurerm/internal/resourceid"
 
 // SetID uses the specified ID Formatter to set the Resource ID
 func (rmd ResourceMetaData) SetID(formatter resourceid.Formatter) {
+	// the ResourceData isn't available during CustomizeDiff, where the ID can't be set
+	if rmd.ResourceData == nil {
+		rmd.Logger.Warnf("the Resource ID %q can't be set during CustomizeDiff - ignoring", formatter.ID())
+		return
+	}
+
 	rmd.ResourceData.SetId(formatter.ID())
 }
@@ -0,0 +1,267 @@
+package sdk
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type roundTripModel struct {
+	Name     string            `tfschema:"name"`
+	Tags     map[string]string `tfschema:"tags"`
+	Networks []string          `tfschema:"networks"`
+	List     []roundTripList   `tfschema:"list"`
+	Set      []roundTripSet    `tfschema:"set"`
+}
+
+type roundTripList struct {
+	Name    string              `tfschema:"name"`
+	Number  int                 `tfschema:"number"`
+	Enabled bool                `tfschema:"enabled"`
+	Inner   []roundTripInnerSet `tfschema:"inner"`
+}
+
+type roundTripInnerSet struct {
+	Key   string `tfschema:"key"`
+	Value string `tfschema:"value"`
+}
+
+type roundTripSet struct {
+	Name string  `tfschema:"name"`
+	Port int     `tfschema:"port"`
+	Rate float64 `tfschema:"rate"`
+}
+
+func roundTripSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"tags": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"networks": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"list": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"number": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+					"enabled": {
+						Type:     schema.TypeBool,
+						Optional: true,
+					},
+					"inner": {
+						Type:     schema.TypeSet,
+						Optional: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"key": {
+									Type:     schema.TypeString,
+									Optional: true,
+								},
+								"value": {
+									Type:     schema.TypeString,
+									Optional: true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"set": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"port": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+					"rate": {
+						Type:     schema.TypeFloat,
+						Optional: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestResourceEncodeDecode_RoundTrip(t *testing.T) {
+	testData := []struct {
+		name  string
+		input roundTripModel
+	}{
+		{
+			name: "empty",
+			input: roundTripModel{
+				Tags:     map[string]string{},
+				Networks: []string{},
+				List:     []roundTripList{},
+				Set:      []roundTripSet{},
+			},
+		},
+		{
+			name: "single items",
+			input: roundTripModel{
+				Name: "example",
+				Tags: map[string]string{
+					"hello": "world",
+				},
+				Networks: []string{"10.0.0.0/16"},
+				List: []roundTripList{
+					{
+						Name:    "first",
+						Number:  42,
+						Enabled: true,
+						Inner: []roundTripInnerSet{
+							{
+								Key:   "bingo",
+								Value: "bango",
+							},
+						},
+					},
+				},
+				Set: []roundTripSet{
+					{
+						Name: "http",
+						Port: 80,
+						Rate: 1.5,
+					},
+				},
+			},
+		},
+		{
+			name: "multiple items",
+			input: roundTripModel{
+				Name: "example",
+				Tags: map[string]string{
+					"hello": "world",
+					"salut": "tous les monde",
+				},
+				Networks: []string{"10.0.0.0/16", "10.1.0.0/16", "10.2.0.0/16"},
+				List: []roundTripList{
+					{
+						Name:   "first",
+						Number: 1,
+						Inner: []roundTripInnerSet{
+							{
+								Key:   "bingo",
+								Value: "bango",
+							},
+							{
+								Key:   "bongo",
+								Value: "bungo",
+							},
+						},
+					},
+					{
+						Name:    "second",
+						Number:  2,
+						Enabled: true,
+						Inner:   []roundTripInnerSet{},
+					},
+				},
+				Set: []roundTripSet{
+					{
+						Name: "http",
+						Port: 80,
+					},
+					{
+						Name: "https",
+						Port: 443,
+						Rate: 0.25,
+					},
+				},
+			},
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		first := ResourceMetaData{
+			ResourceData:             schema.TestResourceDataRaw(t, roundTripSchema(), map[string]interface{}{}),
+			serializationDebugLogger: NullLogger{},
+		}
+		input := v.input
+		if err := first.Encode(&input); err != nil {
+			t.Fatalf("encoding: %+v", err)
+		}
+
+		var decoded roundTripModel
+		if err := first.Decode(&decoded); err != nil {
+			t.Fatalf("decoding: %+v", err)
+		}
+
+		// Lists and Maps should be returned as-is
+		if decoded.Name != v.input.Name {
+			t.Fatalf("expected `name` to be %q but got %q", v.input.Name, decoded.Name)
+		}
+		if !reflect.DeepEqual(decoded.Tags, v.input.Tags) {
+			t.Fatalf("expected `tags` to be %+v but got %+v", v.input.Tags, decoded.Tags)
+		}
+		if len(decoded.List) != len(v.input.List) {
+			t.Fatalf("expected %d items in `list` but got %d", len(v.input.List), len(decoded.List))
+		}
+		for i, item := range decoded.List {
+			expected := v.input.List[i]
+			if item.Name != expected.Name || item.Number != expected.Number || item.Enabled != expected.Enabled {
+				t.Fatalf("expected `list.%d` to be %+v but got %+v", i, expected, item)
+			}
+		}
+
+		// whereas Sets are ordered by their hash, so should hash identically once re-encoded
+		second := ResourceMetaData{
+			ResourceData:             schema.TestResourceDataRaw(t, roundTripSchema(), map[string]interface{}{}),
+			serializationDebugLogger: NullLogger{},
+		}
+		if err := second.Encode(&decoded); err != nil {
+			t.Fatalf("re-encoding: %+v", err)
+		}
+
+		setKeys := []string{"networks", "set"}
+		for i := range v.input.List {
+			setKeys = append(setKeys, fmt.Sprintf("list.%d.inner", i))
+		}
+		for _, key := range setKeys {
+			expected := first.ResourceData.Get(key).(*schema.Set)
+			actual := second.ResourceData.Get(key).(*schema.Set)
+			if !expected.Equal(actual) {
+				t.Fatalf("expected the hashes for %q to match - expected %+v but got %+v", key, expected.List(), actual.List())
+			}
+		}
+		if len(decoded.Networks) != len(v.input.Networks) {
+			t.Fatalf("expected %d items in `networks` but got %d", len(v.input.Networks), len(decoded.Networks))
+		}
+		if len(decoded.Set) != len(v.input.Set) {
+			t.Fatalf("expected %d items in `set` but got %d", len(v.input.Set), len(decoded.Set))
+		}
+	}
+}
@@ -21,6 +21,17 @@ type TypedServiceRegistration interface {
 	WebsiteCategories() []string
 }
 
+// TypedServiceRegistrationWithListDataSources is an optional interface
+//
+// Service Registrations implementing this interface expose List Data Sources, which
+// look up multiple existing resources (for example, all Key Vaults within a Resource Group)
+type TypedServiceRegistrationWithListDataSources interface {
+	TypedServiceRegistration
+
+	// ListDataSources returns a list of List Data Sources supported by this Service
+	ListDataSources() []ListDataSource
+}
+
 // UntypedServiceRegistration is the interface used for untyped/raw Plugin SDK resources
 // in the future this'll be superseded by the TypedServiceRegistration which allows for
 // stronger Typed resources to be used.
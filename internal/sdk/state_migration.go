@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+// StateUpgradeData defines the Schema Version and State Upgrades for a Resource
+type StateUpgradeData struct {
+	// SchemaVersion is the current version of the Schema for this Resource
+	SchemaVersion int
+
+	// Upgraders is a map of Schema Version to the State Upgrade which migrates
+	// the State from that version to the next one, for example the Upgrader at
+	// index `0` migrates the State from version `0` to version `1`
+	Upgraders map[int]pluginsdk.StateUpgrade
+}
+
+func (d StateUpgradeData) validate() error {
+	if d.SchemaVersion < 0 {
+		return fmt.Errorf("`SchemaVersion` must be a positive number but got %d", d.SchemaVersion)
+	}
+
+	if len(d.Upgraders) != d.SchemaVersion {
+		return fmt.Errorf("expected %d State Upgrades for Schema Version %d but got %d", d.SchemaVersion, d.SchemaVersion, len(d.Upgraders))
+	}
+
+	for version := 0; version < d.SchemaVersion; version++ {
+		upgrade, ok := d.Upgraders[version]
+		if !ok || upgrade == nil {
+			return fmt.Errorf("missing a State Upgrade for version %d", version)
+		}
+
+		if v, ok := upgrade.(pluginsdk.ValidatableStateUpgrade); ok {
+			if err := v.Validate(); err != nil {
+				return fmt.Errorf("validating the State Upgrade for version %d: %+v", version, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// UpgradeState runs the raw state through the chain of State Upgrades defined for this
+// Resource, starting from the specified Schema Version - returning the State at the
+// current Schema Version.
+//
+// This mirrors the behaviour of the Plugin SDK and is intended to be used in unit tests
+// to confirm that the State from a previous version of the Resource is upgraded as expected.
+func UpgradeState(ctx context.Context, resource ResourceWithStateMigration, version int, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	wrapper := NewResourceWrapper(resource)
+	wrapped, err := wrapper.Resource()
+	if err != nil {
+		return nil, fmt.Errorf("building Resource %q: %+v", resource.ResourceType(), err)
+	}
+
+	if version > wrapped.SchemaVersion {
+		return nil, fmt.Errorf("version %d is newer than the current Schema Version %d", version, wrapped.SchemaVersion)
+	}
+
+	for _, upgrader := range wrapped.StateUpgraders {
+		if upgrader.Version != version {
+			continue
+		}
+
+		rawState, err = upgrader.Upgrade(ctx, rawState, meta)
+		if err != nil {
+			return nil, fmt.Errorf("upgrading State from version %d to %d: %+v", version, version+1, err)
+		}
+		version++
+	}
+
+	return rawState, nil
+}
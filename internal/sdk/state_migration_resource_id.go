@@ -0,0 +1,132 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/resourceid"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+// ResourceIDParserFunc parses a Resource ID into a Formatter, which is used to
+// output the Resource ID in its canonical form
+//
+// The generated ID Parsers can be used directly, for example:
+//
+//	func(input string) (resourceid.Formatter, error) {
+//		return parse.HostPoolIDInsensitively(input)
+//	}
+type ResourceIDParserFunc func(input string) (resourceid.Formatter, error)
+
+var _ pluginsdk.StateUpgrade = ResourceIDStateUpgrade{}
+
+// ResourceIDStateUpgrade is a generic State Upgrade which rewrites the Resource ID
+// (and any other fields containing Resource IDs) into their canonical form - for
+// example when the casing of a segment in the Resource ID has changed.
+//
+// This can be used for both Typed and Untyped Resources, for example:
+//
+//	StateUpgraders: pluginsdk.StateUpgrades(map[int]pluginsdk.StateUpgrade{
+//		0: sdk.ResourceIDStateUpgrade{
+//			OldSchema: migration.HostPoolV0Schema(),
+//			IDParser: func(input string) (resourceid.Formatter, error) {
+//				return parse.HostPoolIDInsensitively(input)
+//			},
+//		},
+//	}),
+type ResourceIDStateUpgrade struct {
+	// OldSchema is a point-in-time reference to the Schema at the version being upgraded from
+	OldSchema map[string]*pluginsdk.Schema
+
+	// IDParser is used to parse and rewrite the `id` field
+	IDParser ResourceIDParserFunc
+
+	// Fields is an optional map of the top-level fields containing a Resource ID (or a
+	// List/Set of Resource IDs) to the ID Parser which should be used to rewrite them
+	Fields map[string]ResourceIDParserFunc
+}
+
+// Validate confirms that a parser has been specified for the `id` and each of the Fields
+// this is called when the State Upgrades are built, so that this fails early rather than
+// when the State is upgraded
+func (u ResourceIDStateUpgrade) Validate() error {
+	if u.IDParser == nil {
+		return fmt.Errorf("an `IDParser` must be specified")
+	}
+
+	for field, parser := range u.Fields {
+		if parser == nil {
+			return fmt.Errorf("a parser must be specified for the field %q", field)
+		}
+	}
+
+	return nil
+}
+
+func (u ResourceIDStateUpgrade) Schema() map[string]*pluginsdk.Schema {
+	return u.OldSchema
+}
+
+func (u ResourceIDStateUpgrade) UpgradeFunc() pluginsdk.StateUpgraderFunc {
+	return func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+		if err := u.Validate(); err != nil {
+			return nil, err
+		}
+
+		oldId, ok := rawState["id"].(string)
+		if !ok || oldId == "" {
+			return nil, fmt.Errorf("the `id` field was missing from the State")
+		}
+
+		newId, err := rewriteResourceID(oldId, u.IDParser)
+		if err != nil {
+			return nil, fmt.Errorf("rewriting `id`: %+v", err)
+		}
+		log.Printf("[DEBUG] Updating ID from %q to %q", oldId, newId)
+		rawState["id"] = newId
+
+		for field, parser := range u.Fields {
+			switch v := rawState[field].(type) {
+			case string:
+				// optional fields can be empty
+				if v == "" {
+					continue
+				}
+
+				newValue, err := rewriteResourceID(v, parser)
+				if err != nil {
+					return nil, fmt.Errorf("rewriting %q: %+v", field, err)
+				}
+				log.Printf("[DEBUG] Updating %q from %q to %q", field, v, newValue)
+				rawState[field] = newValue
+
+			case []interface{}:
+				for i, item := range v {
+					value, ok := item.(string)
+					if !ok || value == "" {
+						continue
+					}
+
+					newValue, err := rewriteResourceID(value, parser)
+					if err != nil {
+						return nil, fmt.Errorf("rewriting %q (item %d): %+v", field, i, err)
+					}
+					log.Printf("[DEBUG] Updating %q (item %d) from %q to %q", field, i, value, newValue)
+					v[i] = newValue
+				}
+			}
+		}
+
+		return rawState, nil
+	}
+}
+
+func rewriteResourceID(input string, parser ResourceIDParserFunc) (string, error) {
+	id, err := parser(input)
+	if err != nil {
+		return "", fmt.Errorf("parsing %q: %+v", input, err)
+	}
+
+	return id.ID(), nil
+}
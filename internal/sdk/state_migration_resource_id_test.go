@@ -0,0 +1,203 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/resourceid"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+type stateMigrationTestId struct {
+	SubscriptionId string
+	ResourceGroup  string
+	SegmentKey     string
+	Name           string
+}
+
+func (id stateMigrationTestId) ID() string {
+	fmtString := "/subscriptions/%s/resourceGroups/%s/providers/Microsoft.DesktopVirtualization/%s/%s"
+	return fmt.Sprintf(fmtString, id.SubscriptionId, id.ResourceGroup, id.SegmentKey, id.Name)
+}
+
+// stateMigrationTestParser returns a ResourceIDParserFunc which parses a Desktop Virtualization
+// Resource ID insensitively, mirroring the behaviour of the generated `IDInsensitively` parsers
+func stateMigrationTestParser(segmentKey string) ResourceIDParserFunc {
+	return func(input string) (resourceid.Formatter, error) {
+		segments := strings.Split(strings.TrimPrefix(input, "/"), "/")
+		if len(segments) != 8 {
+			return nil, fmt.Errorf("expected 8 segments but got %d", len(segments))
+		}
+
+		expectedKeys := map[int]string{
+			0: "subscriptions",
+			2: "resourceGroups",
+			4: "providers",
+			6: segmentKey,
+		}
+		for index, key := range expectedKeys {
+			if !strings.EqualFold(segments[index], key) {
+				return nil, fmt.Errorf("expected the segment %q but got %q", key, segments[index])
+			}
+		}
+
+		return stateMigrationTestId{
+			SubscriptionId: segments[1],
+			ResourceGroup:  segments[3],
+			SegmentKey:     segmentKey,
+			Name:           segments[7],
+		}, nil
+	}
+}
+
+func TestResourceIDStateUpgrade(t *testing.T) {
+	hostPoolParser := stateMigrationTestParser("hostPools")
+	upgrade := ResourceIDStateUpgrade{
+		IDParser: stateMigrationTestParser("applicationGroups"),
+		Fields: map[string]ResourceIDParserFunc{
+			"host_pool_id":  hostPoolParser,
+			"host_pool_ids": hostPoolParser,
+		},
+	}
+
+	testData := []struct {
+		name        string
+		input       map[string]interface{}
+		expected    map[string]interface{}
+		expectError bool
+	}{
+		{
+			name: "missing id",
+			input: map[string]interface{}{
+				"id": "",
+			},
+			expectError: true,
+		},
+		{
+			name: "invalid id",
+			input: map[string]interface{}{
+				"id": "/subscriptions/12345678-1234-5678-1234-123456789012/resourceGroups/group1",
+			},
+			expectError: true,
+		},
+		{
+			name: "old id",
+			input: map[string]interface{}{
+				"id":           "/subscriptions/12345678-1234-5678-1234-123456789012/resourcegroups/group1/providers/Microsoft.DesktopVirtualization/applicationgroups/group1",
+				"host_pool_id": "",
+			},
+			expected: map[string]interface{}{
+				"id":           "/subscriptions/12345678-1234-5678-1234-123456789012/resourceGroups/group1/providers/Microsoft.DesktopVirtualization/applicationGroups/group1",
+				"host_pool_id": "",
+			},
+		},
+		{
+			name: "old id and fields",
+			input: map[string]interface{}{
+				"id":           "/subscriptions/12345678-1234-5678-1234-123456789012/resourcegroups/group1/providers/Microsoft.DesktopVirtualization/applicationgroups/group1",
+				"host_pool_id": "/subscriptions/12345678-1234-5678-1234-123456789012/resourcegroups/group1/providers/Microsoft.DesktopVirtualization/hostpools/pool1",
+				"host_pool_ids": []interface{}{
+					"/subscriptions/12345678-1234-5678-1234-123456789012/resourcegroups/group1/providers/Microsoft.DesktopVirtualization/hostpools/pool1",
+					"/subscriptions/12345678-1234-5678-1234-123456789012/resourceGroups/group1/providers/Microsoft.DesktopVirtualization/hostPools/pool2",
+				},
+				"name": "group1",
+			},
+			expected: map[string]interface{}{
+				"id":           "/subscriptions/12345678-1234-5678-1234-123456789012/resourceGroups/group1/providers/Microsoft.DesktopVirtualization/applicationGroups/group1",
+				"host_pool_id": "/subscriptions/12345678-1234-5678-1234-123456789012/resourceGroups/group1/providers/Microsoft.DesktopVirtualization/hostPools/pool1",
+				"host_pool_ids": []interface{}{
+					"/subscriptions/12345678-1234-5678-1234-123456789012/resourceGroups/group1/providers/Microsoft.DesktopVirtualization/hostPools/pool1",
+					"/subscriptions/12345678-1234-5678-1234-123456789012/resourceGroups/group1/providers/Microsoft.DesktopVirtualization/hostPools/pool2",
+				},
+				"name": "group1",
+			},
+		},
+		{
+			name: "invalid field",
+			input: map[string]interface{}{
+				"id":           "/subscriptions/12345678-1234-5678-1234-123456789012/resourceGroups/group1/providers/Microsoft.DesktopVirtualization/applicationGroups/group1",
+				"host_pool_id": "/subscriptions/12345678-1234-5678-1234-123456789012/resourceGroups/group1",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		actual, err := upgrade.UpgradeFunc()(context.TODO(), v.input, nil)
+		if err != nil {
+			if v.expectError {
+				continue
+			}
+
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if v.expectError {
+			t.Fatalf("expected an error but didn't get one")
+		}
+
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Fatalf("Expected:\n%+v\n\nActual:\n%+v", v.expected, actual)
+		}
+	}
+}
+
+func TestResourceIDStateUpgradeValidate(t *testing.T) {
+	testData := []struct {
+		name        string
+		upgrade     ResourceIDStateUpgrade
+		expectError bool
+	}{
+		{
+			name: "valid",
+			upgrade: ResourceIDStateUpgrade{
+				IDParser: stateMigrationTestParser("applicationGroups"),
+				Fields: map[string]ResourceIDParserFunc{
+					"host_pool_id": stateMigrationTestParser("hostPools"),
+				},
+			},
+		},
+		{
+			name:        "missing id parser",
+			upgrade:     ResourceIDStateUpgrade{},
+			expectError: true,
+		},
+		{
+			name: "missing field parser",
+			upgrade: ResourceIDStateUpgrade{
+				IDParser: stateMigrationTestParser("applicationGroups"),
+				Fields: map[string]ResourceIDParserFunc{
+					"host_pool_id": nil,
+				},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		err := v.upgrade.Validate()
+		if v.expectError && err == nil {
+			t.Fatalf("expected an error but didn't get one")
+		}
+		if !v.expectError && err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+
+		// the State Upgrade is also validated as a part of the Schema Version/Upgraders
+		data := StateUpgradeData{
+			SchemaVersion: 1,
+			Upgraders: map[int]pluginsdk.StateUpgrade{
+				0: v.upgrade,
+			},
+		}
+		if err := data.validate(); v.expectError && err == nil {
+			t.Fatalf("expected an error validating the State Upgrade Data but didn't get one")
+		}
+	}
+}
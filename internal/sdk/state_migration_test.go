@@ -0,0 +1,181 @@
+package sdk
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+type stateMigrationTestResource struct {
+	wrapperTestResource
+	upgraders map[int]pluginsdk.StateUpgrade
+}
+
+func (r stateMigrationTestResource) StateUpgraders() StateUpgradeData {
+	return StateUpgradeData{
+		SchemaVersion: 2,
+		Upgraders:     r.upgraders,
+	}
+}
+
+// stateMigrationTestV0ToV1 renames the `sku` field to `tier`
+type stateMigrationTestV0ToV1 struct{}
+
+func (stateMigrationTestV0ToV1) Schema() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"name": {
+			Type:     pluginsdk.TypeString,
+			Required: true,
+		},
+		"sku": {
+			Type:     pluginsdk.TypeString,
+			Optional: true,
+		},
+	}
+}
+
+func (stateMigrationTestV0ToV1) UpgradeFunc() pluginsdk.StateUpgraderFunc {
+	return func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+		rawState["tier"] = rawState["sku"]
+		delete(rawState, "sku")
+		return rawState, nil
+	}
+}
+
+// stateMigrationTestV1ToV2 normalizes the `tier` field to lower-case
+type stateMigrationTestV1ToV2 struct{}
+
+func (stateMigrationTestV1ToV2) Schema() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"name": {
+			Type:     pluginsdk.TypeString,
+			Required: true,
+		},
+		"tier": {
+			Type:     pluginsdk.TypeString,
+			Optional: true,
+		},
+	}
+}
+
+func (stateMigrationTestV1ToV2) UpgradeFunc() pluginsdk.StateUpgraderFunc {
+	return func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+		rawState["tier"] = strings.ToLower(rawState["tier"].(string))
+		return rawState, nil
+	}
+}
+
+func TestResourceWrapper_StateMigration(t *testing.T) {
+	wrapper := NewResourceWrapper(stateMigrationTestResource{
+		upgraders: map[int]pluginsdk.StateUpgrade{
+			0: stateMigrationTestV0ToV1{},
+			1: stateMigrationTestV1ToV2{},
+		},
+	})
+	resource, err := wrapper.Resource()
+	if err != nil {
+		t.Fatalf("building Resource: %+v", err)
+	}
+
+	if resource.SchemaVersion != 2 {
+		t.Fatalf("expected the SchemaVersion to be 2 but got %d", resource.SchemaVersion)
+	}
+	if len(resource.StateUpgraders) != 2 {
+		t.Fatalf("expected 2 State Upgraders but got %d", len(resource.StateUpgraders))
+	}
+}
+
+func TestResourceWrapper_StateMigrationMissingVersion(t *testing.T) {
+	wrapper := NewResourceWrapper(stateMigrationTestResource{
+		upgraders: map[int]pluginsdk.StateUpgrade{
+			1: stateMigrationTestV1ToV2{},
+		},
+	})
+	if _, err := wrapper.Resource(); err == nil {
+		t.Fatalf("expected an error but didn't get one")
+	}
+}
+
+func TestUpgradeState(t *testing.T) {
+	testData := []struct {
+		name        string
+		version     int
+		input       map[string]interface{}
+		expected    map[string]interface{}
+		expectError bool
+	}{
+		{
+			name:    "from version 0",
+			version: 0,
+			input: map[string]interface{}{
+				"name": "example",
+				"sku":  "PREMIUM",
+			},
+			expected: map[string]interface{}{
+				"name": "example",
+				"tier": "premium",
+			},
+		},
+		{
+			name:    "from version 1",
+			version: 1,
+			input: map[string]interface{}{
+				"name": "example",
+				"tier": "STANDARD",
+			},
+			expected: map[string]interface{}{
+				"name": "example",
+				"tier": "standard",
+			},
+		},
+		{
+			name:    "current version",
+			version: 2,
+			input: map[string]interface{}{
+				"name": "example",
+				"tier": "basic",
+			},
+			expected: map[string]interface{}{
+				"name": "example",
+				"tier": "basic",
+			},
+		},
+		{
+			name:    "future version",
+			version: 3,
+			input: map[string]interface{}{
+				"name": "example",
+			},
+			expectError: true,
+		},
+	}
+
+	resource := stateMigrationTestResource{
+		upgraders: map[int]pluginsdk.StateUpgrade{
+			0: stateMigrationTestV0ToV1{},
+			1: stateMigrationTestV1ToV2{},
+		},
+	}
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		actual, err := UpgradeState(context.TODO(), resource, v.version, v.input, nil)
+		if err != nil {
+			if v.expectError {
+				continue
+			}
+
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if v.expectError {
+			t.Fatalf("expected an error but didn't get one")
+		}
+
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Fatalf("Expected:\n%+v\n\nActual:\n%+v", v.expected, actual)
+		}
+	}
+}
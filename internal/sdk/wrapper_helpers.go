@@ -55,3 +55,15 @@ func runArgs(d *schema.ResourceData, meta interface{}, logger Logger) ResourceMe
 
 	return metaData
 }
+
+func runArgsDiff(d *schema.ResourceDiff, meta interface{}, logger Logger) ResourceMetaData {
+	client := meta.(*clients.Client)
+	metaData := ResourceMetaData{
+		Client:                   client,
+		Logger:                   logger,
+		ResourceDiff:             d,
+		serializationDebugLogger: NullLogger{},
+	}
+
+	return metaData
+}
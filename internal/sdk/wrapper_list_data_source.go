@@ -0,0 +1,227 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/location"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/validation"
+)
+
+// ListDataSourceWrapper is a wrapper for converting a ListDataSource implementation
+// into the object used by the Terraform Plugin SDK
+type ListDataSourceWrapper struct {
+	dataSource ListDataSource
+	logger     Logger
+}
+
+// NewListDataSourceWrapper returns a ListDataSourceWrapper for this List Data Source implementation
+func NewListDataSourceWrapper(dataSource ListDataSource) ListDataSourceWrapper {
+	return ListDataSourceWrapper{
+		dataSource: dataSource,
+		logger:     &DiagnosticsLogger{},
+	}
+}
+
+// DataSource returns the Terraform Plugin SDK type for this ListDataSource implementation
+func (lw *ListDataSourceWrapper) DataSource() (*schema.Resource, error) {
+	itemsAttributeName := lw.dataSource.ItemsAttributeName()
+	if itemsAttributeName == "" {
+		return nil, fmt.Errorf("List Data Source %q must return a non-empty ItemsAttributeName", lw.dataSource.ResourceType())
+	}
+
+	arguments := make(map[string]*schema.Schema)
+	for k, v := range lw.dataSource.Arguments() {
+		arguments[k] = v
+	}
+	for k, v := range listDataSourceFilterArguments() {
+		if _, exists := arguments[k]; exists {
+			return nil, fmt.Errorf("%q is a common filter and is added automatically - this should be removed from the Arguments", k)
+		}
+
+		arguments[k] = v
+	}
+
+	itemAttributes, err := combineSchema(nil, lw.dataSource.ItemAttributes())
+	if err != nil {
+		return nil, fmt.Errorf("building Item Schema: %+v", err)
+	}
+
+	resourceSchema, err := combineSchema(arguments, map[string]*schema.Schema{
+		itemsAttributeName: {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: *itemAttributes,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("building Schema: %+v", err)
+	}
+
+	modelObj := lw.dataSource.ModelObject()
+	if modelObj != nil {
+		if err := ValidateModelObject(&modelObj); err != nil {
+			return nil, fmt.Errorf("validating model for %q: %+v", lw.dataSource.ResourceType(), err)
+		}
+
+		if err := ValidateModelObjectMatchesSchema(&modelObj, lw.dataSource.Arguments(), nil); err != nil {
+			return nil, fmt.Errorf("validating model for %q matches the schema: %+v", lw.dataSource.ResourceType(), err)
+		}
+	}
+
+	itemModelObj := lw.dataSource.ItemModelObject()
+	if itemModelObj == nil {
+		return nil, fmt.Errorf("List Data Source %q must return a non-nil ItemModelObject", lw.dataSource.ResourceType())
+	}
+	if err := ValidateModelObject(&itemModelObj); err != nil {
+		return nil, fmt.Errorf("validating item model for %q: %+v", lw.dataSource.ResourceType(), err)
+	}
+	if err := ValidateModelObjectMatchesSchema(&itemModelObj, nil, lw.dataSource.ItemAttributes()); err != nil {
+		return nil, fmt.Errorf("validating item model for %q matches the schema: %+v", lw.dataSource.ResourceType(), err)
+	}
+
+	d := func(duration time.Duration) *time.Duration {
+		return &duration
+	}
+
+	resource := schema.Resource{
+		Schema: *resourceSchema,
+		ReadContext: diagnosticsWrapper(func(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+			metaData := runArgs(d, meta, lw.logger)
+			items, err := lw.dataSource.List().Func(ctx, metaData)
+			if err != nil {
+				return err
+			}
+
+			filtered, err := filterListItems(items, listDataSourceFiltersFromResourceData(d))
+			if err != nil {
+				return err
+			}
+
+			itemModelType := reflect.Indirect(reflect.ValueOf(itemModelObj)).Type()
+			output := make([]interface{}, 0)
+			for _, item := range filtered {
+				if item.Model == nil {
+					return fmt.Errorf("the Model for %q was nil", item.Name)
+				}
+				objVal := reflect.Indirect(reflect.ValueOf(item.Model))
+				if !objVal.IsValid() {
+					return fmt.Errorf("the Model for %q was nil", item.Name)
+				}
+				if objVal.Type() != itemModelType {
+					return fmt.Errorf("the Model for %q was a %s but expected a %s (the ItemModelObject)", item.Name, objVal.Type(), itemModelType)
+				}
+
+				serialized, err := recurse(objVal.Type(), objVal, item.Name, metaData.serializationDebugLogger)
+				if err != nil {
+					return fmt.Errorf("serializing %q: %+v", item.Name, err)
+				}
+
+				output = append(output, serialized)
+			}
+
+			d.SetId(time.Now().UTC().String())
+
+			// lintignore:R001
+			if err := d.Set(itemsAttributeName, output); err != nil {
+				return fmt.Errorf("setting %q: %+v", itemsAttributeName, err)
+			}
+
+			return nil
+		}, lw.logger),
+		Timeouts: &schema.ResourceTimeout{
+			Read: d(lw.dataSource.List().Timeout),
+		},
+	}
+
+	return &resource, nil
+}
+
+// listDataSourceFilterArguments returns the common filters which are available for every List Data Source
+func listDataSourceFilterArguments() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name_regex": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringIsValidRegExp,
+		},
+
+		"location": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			StateFunc:        location.StateFunc,
+			DiffSuppressFunc: location.DiffSuppressFunc,
+		},
+
+		"tags": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+	}
+}
+
+type listDataSourceFilters struct {
+	NameRegex string
+	Location  string
+	Tags      map[string]string
+}
+
+func listDataSourceFiltersFromResourceData(d *schema.ResourceData) listDataSourceFilters {
+	filters := listDataSourceFilters{
+		NameRegex: d.Get("name_regex").(string),
+		Location:  d.Get("location").(string),
+		Tags:      make(map[string]string),
+	}
+
+	for k, v := range d.Get("tags").(map[string]interface{}) {
+		filters.Tags[k] = v.(string)
+	}
+
+	return filters
+}
+
+// filterListItems returns the items which match all of the specified filters
+func filterListItems(input []ListItem, filters listDataSourceFilters) ([]ListItem, error) {
+	var nameRegex *regexp.Regexp
+	if filters.NameRegex != "" {
+		r, err := regexp.Compile(filters.NameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("compiling `name_regex` %q: %+v", filters.NameRegex, err)
+		}
+		nameRegex = r
+	}
+
+	output := make([]ListItem, 0)
+	for _, item := range input {
+		if nameRegex != nil && !nameRegex.MatchString(item.Name) {
+			continue
+		}
+
+		if filters.Location != "" && location.Normalize(filters.Location) != location.Normalize(item.Location) {
+			continue
+		}
+
+		matchesTags := true
+		for k, v := range filters.Tags {
+			if existing, ok := item.Tags[k]; !ok || existing != v {
+				matchesTags = false
+				break
+			}
+		}
+		if !matchesTags {
+			continue
+		}
+
+		output = append(output, item)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,287 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+type listDataSourceTestModel struct {
+	ResourceGroup string `tfschema:"resource_group_name"`
+}
+
+type listDataSourceTestItemModel struct {
+	Name     string            `tfschema:"name"`
+	Location string            `tfschema:"location"`
+	Tags     map[string]string `tfschema:"tags"`
+}
+
+type listDataSourceTest struct {
+	items []listDataSourceTestItemModel
+}
+
+func (listDataSourceTest) Arguments() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"resource_group_name": {
+			Type:     pluginsdk.TypeString,
+			Required: true,
+		},
+	}
+}
+
+func (listDataSourceTest) ModelObject() interface{} {
+	return listDataSourceTestModel{}
+}
+
+func (listDataSourceTest) ItemAttributes() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"name": {
+			Type:     pluginsdk.TypeString,
+			Computed: true,
+		},
+		"location": {
+			Type:     pluginsdk.TypeString,
+			Computed: true,
+		},
+		"tags": {
+			Type:     pluginsdk.TypeMap,
+			Computed: true,
+			Elem: &pluginsdk.Schema{
+				Type: pluginsdk.TypeString,
+			},
+		},
+	}
+}
+
+func (listDataSourceTest) ItemModelObject() interface{} {
+	return listDataSourceTestItemModel{}
+}
+
+func (listDataSourceTest) ItemsAttributeName() string {
+	return "examples"
+}
+
+func (listDataSourceTest) ResourceType() string {
+	return "validator_examples"
+}
+
+func (ds listDataSourceTest) List() ListDataSourceFunc {
+	return ListDataSourceFunc{
+		Func: func(ctx context.Context, metadata ResourceMetaData) ([]ListItem, error) {
+			var model listDataSourceTestModel
+			if err := metadata.Decode(&model); err != nil {
+				return nil, fmt.Errorf("decoding: %+v", err)
+			}
+
+			// each page contains a single item
+			paginator := &TestPaginator{
+				Pages: len(ds.items),
+			}
+			items := make([]ListItem, 0)
+			err := ListAllPages(ctx, paginator, func() error {
+				item := ds.items[paginator.CurrentPage()]
+				items = append(items, ListItem{
+					Name:     item.Name,
+					Location: item.Location,
+					Tags:     item.Tags,
+					Model:    &item,
+				})
+				return nil
+			})
+			return items, err
+		},
+		Timeout: 5 * time.Minute,
+	}
+}
+
+func TestListDataSourceWrapper(t *testing.T) {
+	testData := []struct {
+		name          string
+		config        map[string]interface{}
+		expectedNames []string
+	}{
+		{
+			name: "no filters",
+			config: map[string]interface{}{
+				"resource_group_name": "example",
+			},
+			expectedNames: []string{"first", "second", "third"},
+		},
+		{
+			name: "name regex",
+			config: map[string]interface{}{
+				"resource_group_name": "example",
+				"name_regex":          "^(first|third)$",
+			},
+			expectedNames: []string{"first", "third"},
+		},
+		{
+			name: "location",
+			config: map[string]interface{}{
+				"resource_group_name": "example",
+				"location":            "West Europe",
+			},
+			expectedNames: []string{"first", "second"},
+		},
+		{
+			name: "tags",
+			config: map[string]interface{}{
+				"resource_group_name": "example",
+				"tags": map[string]interface{}{
+					"env": "prod",
+				},
+			},
+			expectedNames: []string{"second"},
+		},
+		{
+			name: "all filters",
+			config: map[string]interface{}{
+				"resource_group_name": "example",
+				"name_regex":          "d$",
+				"location":            "eastus",
+				"tags": map[string]interface{}{
+					"env": "prod",
+				},
+			},
+			expectedNames: []string{},
+		},
+	}
+
+	wrapper := NewListDataSourceWrapper(listDataSourceTest{
+		items: []listDataSourceTestItemModel{
+			{
+				Name:     "first",
+				Location: "westeurope",
+				Tags: map[string]string{
+					"env": "dev",
+				},
+			},
+			{
+				Name:     "second",
+				Location: "westeurope",
+				Tags: map[string]string{
+					"env":  "prod",
+					"team": "platform",
+				},
+			},
+			{
+				Name:     "third",
+				Location: "eastus",
+				Tags:     map[string]string{},
+			},
+		},
+	})
+	dataSource, err := wrapper.DataSource()
+	if err != nil {
+		t.Fatalf("building Data Source: %+v", err)
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		d := schema.TestResourceDataRaw(t, dataSource.Schema, v.config)
+		if diags := dataSource.ReadContext(context.TODO(), d, &clients.Client{}); diags.HasError() {
+			t.Fatalf("reading: %+v", diags)
+		}
+
+		if d.Id() == "" {
+			t.Fatalf("expected an ID to be set but it wasn't")
+		}
+
+		items := d.Get("examples").([]interface{})
+		if len(items) != len(v.expectedNames) {
+			t.Fatalf("expected %d items but got %d: %+v", len(v.expectedNames), len(items), items)
+		}
+		for i, item := range items {
+			name := item.(map[string]interface{})["name"].(string)
+			if name != v.expectedNames[i] {
+				t.Fatalf("expected item %d to be %q but got %q", i, v.expectedNames[i], name)
+			}
+		}
+	}
+}
+
+func TestListDataSourceWrapper_ConflictingArguments(t *testing.T) {
+	wrapper := NewListDataSourceWrapper(listDataSourceTestWithLocationArgument{})
+	if _, err := wrapper.DataSource(); err == nil {
+		t.Fatalf("expected an error since `location` is a common filter but didn't get one")
+	}
+}
+
+type listDataSourceTestWithLocationArgument struct {
+	listDataSourceTest
+}
+
+func (listDataSourceTestWithLocationArgument) Arguments() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"location": {
+			Type:     pluginsdk.TypeString,
+			Required: true,
+		},
+	}
+}
+
+func TestListDataSourceWrapper_InvalidItemModel(t *testing.T) {
+	testData := []struct {
+		name  string
+		model interface{}
+	}{
+		{
+			name:  "nil",
+			model: nil,
+		},
+		{
+			name:  "nil pointer",
+			model: (*listDataSourceTestItemModel)(nil),
+		},
+		{
+			name:  "different type",
+			model: &listDataSourceTestModel{},
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		wrapper := NewListDataSourceWrapper(listDataSourceTestWithItems{
+			items: []ListItem{
+				{
+					Name:     "first",
+					Location: "westeurope",
+					Model:    v.model,
+				},
+			},
+		})
+		dataSource, err := wrapper.DataSource()
+		if err != nil {
+			t.Fatalf("building Data Source: %+v", err)
+		}
+
+		d := schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{
+			"resource_group_name": "example",
+		})
+		if diags := dataSource.ReadContext(context.TODO(), d, &clients.Client{}); !diags.HasError() {
+			t.Fatalf("expected an error but didn't get one")
+		}
+	}
+}
+
+type listDataSourceTestWithItems struct {
+	listDataSourceTest
+
+	items []ListItem
+}
+
+func (ds listDataSourceTestWithItems) List() ListDataSourceFunc {
+	return ListDataSourceFunc{
+		Func: func(ctx context.Context, metadata ResourceMetaData) ([]ListItem, error) {
+			return ds.items, nil
+		},
+		Timeout: 5 * time.Minute,
+	}
+}
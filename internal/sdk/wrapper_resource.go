@@ -37,6 +37,10 @@ func (rw *ResourceWrapper) Resource() (*schema.Resource, error) {
 		if err := ValidateModelObject(&modelObj); err != nil {
 			return nil, fmt.Errorf("validating model for %q: %+v", rw.resource.ResourceType(), err)
 		}
+
+		if err := ValidateModelObjectMatchesSchema(&modelObj, rw.resource.Arguments(), rw.resource.Attributes()); err != nil {
+			return nil, fmt.Errorf("validating model for %q matches the schema: %+v", rw.resource.ResourceType(), err)
+		}
 	}
 
 	d := func(duration time.Duration) *time.Duration {
@@ -133,8 +137,32 @@ func (rw *ResourceWrapper) Resource() (*schema.Resource, error) {
 		resource.DeprecationMessage = message
 	}
 
-	// TODO: CustomizeDiff
-	// TODO: State Migrations
+	if v, ok := rw.resource.(ResourceWithCustomizeDiff); ok {
+		if v.CustomizeDiff().Timeout <= 0 {
+			return nil, fmt.Errorf("Resource %q must specify a Timeout greater than zero for CustomizeDiff", rw.resource.ResourceType())
+		}
+
+		resource.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			customizeDiff := v.CustomizeDiff()
+
+			// unlike the CRUD methods, the Plugin SDK doesn't attach a timeout here
+			ctx, cancel := context.WithTimeout(ctx, customizeDiff.Timeout)
+			defer cancel()
+
+			metaData := runArgsDiff(d, meta, rw.logger)
+			return customizeDiff.Func(ctx, metaData)
+		}
+	}
+
+	if v, ok := rw.resource.(ResourceWithStateMigration); ok {
+		stateUpgradeData := v.StateUpgraders()
+		if err := stateUpgradeData.validate(); err != nil {
+			return nil, fmt.Errorf("validating State Upgrades for %q: %+v", rw.resource.ResourceType(), err)
+		}
+
+		resource.SchemaVersion = stateUpgradeData.SchemaVersion
+		resource.StateUpgraders = pluginsdk.StateUpgrades(stateUpgradeData.Upgraders)
+	}
 
 	return &resource, nil
 }
@@ -0,0 +1,204 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+type wrapperTestModel struct {
+	Name    string `tfschema:"name"`
+	Enabled bool   `tfschema:"enabled"`
+	Tier    string `tfschema:"tier"`
+}
+
+type wrapperTestResource struct{}
+
+func (wrapperTestResource) Arguments() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"name": {
+			Type:     pluginsdk.TypeString,
+			Required: true,
+		},
+		"enabled": {
+			Type:     pluginsdk.TypeBool,
+			Optional: true,
+		},
+		"tier": {
+			Type:     pluginsdk.TypeString,
+			Optional: true,
+		},
+	}
+}
+
+func (wrapperTestResource) Attributes() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{}
+}
+
+func (wrapperTestResource) ModelObject() interface{} {
+	return wrapperTestModel{}
+}
+
+func (wrapperTestResource) ResourceType() string {
+	return "validator_wrapper"
+}
+
+func (wrapperTestResource) Create() ResourceFunc {
+	return wrapperTestNoOpFunc()
+}
+
+func (wrapperTestResource) Read() ResourceFunc {
+	return wrapperTestNoOpFunc()
+}
+
+func (wrapperTestResource) Delete() ResourceFunc {
+	return wrapperTestNoOpFunc()
+}
+
+func (wrapperTestResource) IDValidationFunc() pluginsdk.SchemaValidateFunc {
+	return nil
+}
+
+func wrapperTestNoOpFunc() ResourceFunc {
+	return ResourceFunc{
+		Func: func(_ context.Context, _ ResourceMetaData) error {
+			return nil
+		},
+		Timeout: 5 * time.Minute,
+	}
+}
+
+type wrapperTestResourceWithCustomizeDiff struct {
+	wrapperTestResource
+}
+
+func (wrapperTestResourceWithCustomizeDiff) CustomizeDiff() ResourceFunc {
+	return ResourceFunc{
+		Func: func(ctx context.Context, metadata ResourceMetaData) error {
+			if _, ok := ctx.Deadline(); !ok {
+				return fmt.Errorf("expected the context to have a deadline")
+			}
+			if metadata.ResourceData != nil {
+				return fmt.Errorf("expected ResourceData to be nil during CustomizeDiff")
+			}
+
+			var model wrapperTestModel
+			if err := metadata.Decode(&model); err != nil {
+				return fmt.Errorf("decoding: %+v", err)
+			}
+
+			if model.Enabled && model.Tier == "" {
+				return fmt.Errorf("`tier` must be specified when `enabled` is true for %q", model.Name)
+			}
+
+			return nil
+		},
+		Timeout: 5 * time.Minute,
+	}
+}
+
+func TestResourceWrapper_CustomizeDiffNotImplemented(t *testing.T) {
+	wrapper := NewResourceWrapper(wrapperTestResource{})
+	resource, err := wrapper.Resource()
+	if err != nil {
+		t.Fatalf("building Resource: %+v", err)
+	}
+
+	if resource.CustomizeDiff != nil {
+		t.Fatalf("expected CustomizeDiff to be nil when ResourceWithCustomizeDiff isn't implemented")
+	}
+}
+
+func TestResourceWrapper_CustomizeDiff(t *testing.T) {
+	testData := []struct {
+		config      map[string]interface{}
+		expectError bool
+	}{
+		{
+			config: map[string]interface{}{
+				"name": "disabled",
+			},
+			expectError: false,
+		},
+		{
+			config: map[string]interface{}{
+				"name":    "enabled-with-tier",
+				"enabled": true,
+				"tier":    "Premium",
+			},
+			expectError: false,
+		},
+		{
+			config: map[string]interface{}{
+				"name":    "enabled-without-tier",
+				"enabled": true,
+			},
+			expectError: true,
+		},
+	}
+
+	wrapper := NewResourceWrapper(wrapperTestResourceWithCustomizeDiff{})
+	resource, err := wrapper.Resource()
+	if err != nil {
+		t.Fatalf("building Resource: %+v", err)
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q", v.config["name"])
+
+		config := terraform.NewResourceConfigRaw(v.config)
+		_, err := resource.Diff(context.TODO(), nil, config, &clients.Client{})
+		if v.expectError && err == nil {
+			t.Fatalf("expected an error but didn't get one")
+		}
+		if !v.expectError && err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+	}
+}
+
+type wrapperTestResourceWithoutCustomizeDiffTimeout struct {
+	wrapperTestResource
+}
+
+func (wrapperTestResourceWithoutCustomizeDiffTimeout) CustomizeDiff() ResourceFunc {
+	return ResourceFunc{
+		Func: func(_ context.Context, _ ResourceMetaData) error {
+			return nil
+		},
+	}
+}
+
+func TestResourceWrapper_CustomizeDiffWithoutTimeout(t *testing.T) {
+	wrapper := NewResourceWrapper(wrapperTestResourceWithoutCustomizeDiffTimeout{})
+	if _, err := wrapper.Resource(); err == nil {
+		t.Fatalf("expected an error when CustomizeDiff doesn't specify a Timeout but didn't get one")
+	}
+}
+
+type wrapperTestId struct{}
+
+func (wrapperTestId) ID() string {
+	return "/some/id"
+}
+
+func TestResourceMetaData_WithoutResourceData(t *testing.T) {
+	// during CustomizeDiff only the ResourceDiff is available, so these should error (or be ignored) rather than panic
+	metadata := ResourceMetaData{
+		Logger:                   NullLogger{},
+		serializationDebugLogger: NullLogger{},
+	}
+
+	if err := metadata.Encode(&wrapperTestModel{}); err == nil {
+		t.Fatalf("expected an error when Encoding without ResourceData but didn't get one")
+	}
+	metadata.SetID(wrapperTestId{})
+	if err := metadata.MarkAsGone(wrapperTestId{}); err == nil {
+		t.Fatalf("expected an error when marking as gone without ResourceData but didn't get one")
+	}
+}
@@ -3,21 +3,69 @@ package sdk
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // ValidateModelObject validates that the object contains the specified `tfschema` tags
 // required to be used with the Encode and Decode functions
 func ValidateModelObject(input interface{}) error {
-	if reflect.TypeOf(input).Kind() != reflect.Ptr {
-		return fmt.Errorf("need a pointer")
+	objType, objVal, err := modelObjectTypeAndValue(input)
+	if err != nil {
+		return err
+	}
+
+	return validateModelObjectRecursively("", objType, objVal)
+}
+
+// ValidateModelObjectMatchesSchema validates that each `tfschema` tag within the model object
+// maps to a field within the Schema of a compatible type - and that each field within the Schema
+// is present within the model object - returning an error containing every mismatch
+func ValidateModelObjectMatchesSchema(input interface{}, arguments map[string]*schema.Schema, attributes map[string]*schema.Schema) error {
+	objType, _, err := modelObjectTypeAndValue(input)
+	if err != nil {
+		return err
 	}
 
-	// TODO: could we also validate that each `tfschema` tag exists in the schema?
+	fields := make(map[string]*schema.Schema)
+	for k, v := range arguments {
+		fields[k] = v
+	}
+	for k, v := range attributes {
+		fields[k] = v
+	}
+
+	errs := validateModelObjectMatchesSchemaRecursively("", objType, fields)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	var out *multierror.Error
+	for _, e := range errs {
+		out = multierror.Append(out, e)
+	}
+	return out
+}
+
+// modelObjectTypeAndValue returns the type and value of the struct the input points to
+// NOTE: ModelObject returns an interface{}, so this could also be a pointer to an interface
+func modelObjectTypeAndValue(input interface{}) (reflect.Type, reflect.Value, error) {
+	if reflect.TypeOf(input).Kind() != reflect.Ptr {
+		return nil, reflect.Value{}, fmt.Errorf("need a pointer")
+	}
 
-	objType := reflect.TypeOf(input).Elem()
 	objVal := reflect.ValueOf(input).Elem()
-	return validateModelObjectRecursively("", objType, objVal)
+	if objVal.Kind() == reflect.Interface {
+		objVal = objVal.Elem()
+	}
+	if objVal.Kind() != reflect.Struct {
+		return nil, reflect.Value{}, fmt.Errorf("need a pointer to a struct but got %s", reflect.TypeOf(input))
+	}
+
+	return objVal.Type(), objVal, nil
 }
 
 func validateModelObjectRecursively(prefix string, objType reflect.Type, objVal reflect.Value) (errOut error) {
@@ -39,10 +87,15 @@ func validateModelObjectRecursively(prefix string, objType reflect.Type, objVal
 		if field.Type.Kind() == reflect.Slice {
 			sv := fieldVal.Slice(0, fieldVal.Len())
 			innerType := sv.Type().Elem()
-			innerVal := reflect.Indirect(reflect.New(innerType))
-			fieldName := strings.TrimPrefix(fmt.Sprintf("%s.%s", prefix, field.Name), ".")
-			if err := validateModelObjectRecursively(fieldName, innerType, innerVal); err != nil {
-				return err
+			if innerType.Kind() == reflect.Ptr {
+				innerType = innerType.Elem()
+			}
+			if innerType.Kind() == reflect.Struct {
+				innerVal := reflect.Indirect(reflect.New(innerType))
+				fieldName := strings.TrimPrefix(fmt.Sprintf("%s.%s", prefix, field.Name), ".")
+				if err := validateModelObjectRecursively(fieldName, innerType, innerVal); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -54,3 +107,127 @@ func validateModelObjectRecursively(prefix string, objType reflect.Type, objVal
 
 	return nil
 }
+
+func validateModelObjectMatchesSchemaRecursively(prefix string, objType reflect.Type, fields map[string]*schema.Schema) []error {
+	errs := make([]error, 0)
+	seen := make(map[string]struct{})
+
+	for i := 0; i < objType.NumField(); i++ {
+		field := objType.Field(i)
+		fieldName := strings.TrimPrefix(fmt.Sprintf("%s.%s", prefix, field.Name), ".")
+
+		tfschemaTag, exists := field.Tag.Lookup("tfschema")
+		if !exists {
+			// this is reported by ValidateModelObject
+			continue
+		}
+		key := strings.TrimPrefix(fmt.Sprintf("%s.%s", prefix, tfschemaTag), ".")
+
+		if _, alreadySeen := seen[tfschemaTag]; alreadySeen {
+			errs = append(errs, fmt.Errorf("field %q: the `tfschema` tag %q is used by multiple fields", fieldName, key))
+			continue
+		}
+		seen[tfschemaTag] = struct{}{}
+
+		fieldSchema, ok := fields[tfschemaTag]
+		if !ok {
+			errs = append(errs, fmt.Errorf("field %q: %q was not found in the Schema", fieldName, key))
+			continue
+		}
+
+		errs = append(errs, validateFieldMatchesSchema(fieldName, key, field.Type, fieldSchema)...)
+	}
+
+	keys := make([]string, 0)
+	for k := range fields {
+		if _, ok := seen[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key := strings.TrimPrefix(fmt.Sprintf("%s.%s", prefix, k), ".")
+		errs = append(errs, fmt.Errorf("schema field %q has no corresponding field in the model", key))
+	}
+
+	return errs
+}
+
+func validateFieldMatchesSchema(fieldName, key string, fieldType reflect.Type, fieldSchema *schema.Schema) []error {
+	// pointers are supported for any type, e.g. to be able to distinguish an empty value
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	switch fieldSchema.Type {
+	case schema.TypeList, schema.TypeSet:
+		if fieldType.Kind() != reflect.Slice {
+			return []error{fmt.Errorf("field %q: %q is a %s so must be a slice but got %s", fieldName, key, fieldSchema.Type, fieldType)}
+		}
+
+		elemType := fieldType.Elem()
+		if elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+
+		switch elem := fieldSchema.Elem.(type) {
+		case *schema.Resource:
+			if elemType.Kind() != reflect.Struct {
+				return []error{fmt.Errorf("field %q: %q is a %s of nested blocks so must be a slice of structs but got %s", fieldName, key, fieldSchema.Type, fieldType)}
+			}
+
+			return validateModelObjectMatchesSchemaRecursively(key, elemType, elem.Schema)
+
+		case *schema.Schema:
+			if !primitiveTypeMatchesSchema(elemType, elem.Type) {
+				return []error{fmt.Errorf("field %q: %q is a %s of %s but got %s", fieldName, key, fieldSchema.Type, elem.Type, fieldType)}
+			}
+		}
+
+	case schema.TypeMap:
+		if fieldType.Kind() != reflect.Map || fieldType.Key().Kind() != reflect.String {
+			return []error{fmt.Errorf("field %q: %q is a %s so must be a map with string keys but got %s", fieldName, key, fieldSchema.Type, fieldType)}
+		}
+
+		// the Plugin SDK defaults the Elem for maps to strings
+		elemType := schema.TypeString
+		if elem, ok := fieldSchema.Elem.(*schema.Schema); ok {
+			elemType = elem.Type
+		}
+		if !primitiveTypeMatchesSchema(fieldType.Elem(), elemType) {
+			return []error{fmt.Errorf("field %q: %q is a %s of %s but got %s", fieldName, key, fieldSchema.Type, elemType, fieldType)}
+		}
+
+	default:
+		if !primitiveTypeMatchesSchema(fieldType, fieldSchema.Type) {
+			return []error{fmt.Errorf("field %q: %q is a %s but got %s", fieldName, key, fieldSchema.Type, fieldType)}
+		}
+	}
+
+	return nil
+}
+
+func primitiveTypeMatchesSchema(fieldType reflect.Type, valueType schema.ValueType) bool {
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Interface:
+		return true
+
+	case reflect.String:
+		return valueType == schema.TypeString
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return valueType == schema.TypeInt
+
+	case reflect.Float32, reflect.Float64:
+		return valueType == schema.TypeFloat
+
+	case reflect.Bool:
+		return valueType == schema.TypeBool
+	}
+
+	return false
+}
@@ -1,6 +1,11 @@
 package sdk
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
 
 func TestValidateTopLevelObjectValid(t *testing.T) {
 	type Person struct {
@@ -56,3 +61,171 @@ func TestValidateNestedObjectInvalid(t *testing.T) {
 		t.Fatalf("expected an error but didn't get one")
 	}
 }
+
+func TestValidateModelObjectMatchesSchema(t *testing.T) {
+	type Pet struct {
+		Name string `tfschema:"name"`
+		Age  int    `tfschema:"age"`
+	}
+	type Person struct {
+		Name     string                 `tfschema:"name"`
+		Nickname *string                `tfschema:"nickname"`
+		Enabled  bool                   `tfschema:"enabled"`
+		Height   float64                `tfschema:"height"`
+		Networks []string               `tfschema:"networks"`
+		Pets     []Pet                  `tfschema:"pets"`
+		Tags     map[string]interface{} `tfschema:"tags"`
+	}
+	type PersonWithTypo struct {
+		Name     string                 `tfschema:"nmae"`
+		Nickname *string                `tfschema:"nickname"`
+		Enabled  bool                   `tfschema:"enabled"`
+		Height   float64                `tfschema:"height"`
+		Networks []string               `tfschema:"networks"`
+		Pets     []Pet                  `tfschema:"pets"`
+		Tags     map[string]interface{} `tfschema:"tags"`
+	}
+	type PersonWithWrongTypes struct {
+		Name     int               `tfschema:"name"`
+		Nickname *bool             `tfschema:"nickname"`
+		Enabled  string            `tfschema:"enabled"`
+		Height   float64           `tfschema:"height"`
+		Networks []int             `tfschema:"networks"`
+		Pets     []string          `tfschema:"pets"`
+		Tags     map[string]string `tfschema:"tags"`
+	}
+	type PetWithMissingField struct {
+		Name string `tfschema:"name"`
+	}
+	type PersonWithInvalidNestedObject struct {
+		Name     string                 `tfschema:"name"`
+		Nickname *string                `tfschema:"nickname"`
+		Enabled  bool                   `tfschema:"enabled"`
+		Height   float64                `tfschema:"height"`
+		Networks []string               `tfschema:"networks"`
+		Pets     []*PetWithMissingField `tfschema:"pets"`
+		Tags     map[string]interface{} `tfschema:"tags"`
+	}
+
+	arguments := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"nickname": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"enabled": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+		"networks": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"pets": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"age": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"tags": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+	}
+	attributes := map[string]*schema.Schema{
+		"height": {
+			Type:     schema.TypeFloat,
+			Computed: true,
+		},
+	}
+
+	testData := []struct {
+		name           string
+		input          interface{}
+		expectedErrors int
+	}{
+		{
+			name:           "valid",
+			input:          &Person{},
+			expectedErrors: 0,
+		},
+		{
+			name:           "typo in tag",
+			input:          &PersonWithTypo{},
+			expectedErrors: 2,
+		},
+		{
+			name:           "wrong types",
+			input:          &PersonWithWrongTypes{},
+			expectedErrors: 5,
+		},
+		{
+			name:           "nested object missing a field",
+			input:          &PersonWithInvalidNestedObject{},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		err := ValidateModelObjectMatchesSchema(v.input, arguments, attributes)
+		if v.expectedErrors == 0 {
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Fatalf("expected %d errors but didn't get any", v.expectedErrors)
+		}
+		merr, ok := err.(*multierror.Error)
+		if !ok {
+			t.Fatalf("expected a multierror but got %+v", err)
+		}
+		if len(merr.Errors) != v.expectedErrors {
+			t.Fatalf("expected %d errors but got %d: %+v", v.expectedErrors, len(merr.Errors), err)
+		}
+	}
+}
+
+func TestValidateModelObjectMatchesSchemaFromInterface(t *testing.T) {
+	type Person struct {
+		Name string `tfschema:"name"`
+	}
+	var obj interface{} = Person{}
+	err := ValidateModelObjectMatchesSchema(&obj, map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+	}, map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	})
+	if err == nil {
+		t.Fatalf("expected an error since `id` isn't present in the model but didn't get one")
+	}
+}
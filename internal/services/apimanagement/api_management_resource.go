@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-provider-azurerm/helpers/tf"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/location"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/lro"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/services/apimanagement/parse"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/services/apimanagement/schemaz"
 	apimValidate "github.com/hashicorp/terraform-provider-azurerm/internal/services/apimanagement/validate"
@@ -621,7 +622,20 @@ func resourceApiManagementServiceCreateUpdate(d *pluginsdk.ResourceData, meta in
 		}
 
 		if existing.ID != nil && *existing.ID != "" {
-			return tf.ImportAsExistsError("azurerm_api_management", *existing.ID)
+			// when the Provider was killed part-way through the creation the API Management Service is still being provisioned,
+			// as such it's adopted once provisioned (and then updated below) rather than requiring that it's imported
+			provisioningState := ""
+			if props := existing.ServiceProperties; props != nil && props.ProvisioningState != nil {
+				provisioningState = *props.ProvisioningState
+			}
+			if !lro.IsProvisioning(provisioningState) {
+				return tf.ImportAsExistsError("azurerm_api_management", *existing.ID)
+			}
+
+			log.Printf("[DEBUG] API Management Service %q (Resource Group %q) is still being provisioned - waiting for this to complete to adopt it..", name, resourceGroup)
+			if err := lro.WaitForProvisioning(ctx, d.Timeout(pluginsdk.TimeoutCreate), apiManagementServiceProvisioningState(client, resourceGroup, name)); err != nil {
+				return fmt.Errorf("waiting for the existing API Management Service %q (Resource Group %q) to be provisioned: %+v", name, resourceGroup, err)
+			}
 		}
 	}
 
@@ -720,6 +734,16 @@ func resourceApiManagementServiceCreateUpdate(d *pluginsdk.ResourceData, meta in
 	}
 
 	if err = future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		if lro.WasInterrupted(ctx) {
+			// the API Management Service continues to be provisioned, so this is resumed when it's next read
+			operation := lro.OperationUpdate
+			if d.IsNewResource() {
+				operation = lro.OperationCreate
+			}
+			id := parse.NewApiManagementID(meta.(*clients.Client).Account.SubscriptionId, resourceGroup, name)
+			d.SetId(id.ID())
+			return lro.Interrupted(id.ID(), operation, future.FutureAPI)
+		}
 		return fmt.Errorf("waiting for creation/update of API Management Service %q (Resource Group %q): %+v", name, resourceGroup, err)
 	}
 
@@ -811,6 +835,14 @@ func resourceApiManagementServiceRead(d *pluginsdk.ResourceData, meta interface{
 		return err
 	}
 
+	// when the creation/update of the API Management Service was interrupted, we need to check whether it's completed
+	if err := lro.Resume(ctx, d, client.Client); err != nil {
+		return fmt.Errorf("checking the creation/update of %s: %+v", *id, err)
+	}
+	if d.Id() == "" {
+		return nil
+	}
+
 	resourceGroup := id.ResourceGroup
 	name := id.ServiceName
 
@@ -1750,3 +1782,18 @@ func flattenApiManagementTenantAccessSettings(input apimanagement.AccessInformat
 
 	return []interface{}{result}
 }
+
+func apiManagementServiceProvisioningState(client *apimanagement.ServiceClient, resourceGroup, name string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		resp, err := client.Get(ctx, resourceGroup, name)
+		if err != nil {
+			return "", fmt.Errorf("retrieving API Management Service %q (Resource Group %q): %+v", name, resourceGroup, err)
+		}
+
+		if resp.ServiceProperties == nil || resp.ServiceProperties.ProvisioningState == nil {
+			return "", fmt.Errorf("`properties.provisioningState` was nil for API Management Service %q (Resource Group %q)", name, resourceGroup)
+		}
+
+		return *resp.ServiceProperties.ProvisioningState, nil
+	}
+}
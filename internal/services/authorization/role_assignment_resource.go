@@ -130,15 +130,21 @@ func resourceArmRoleAssignment() *pluginsdk.Resource {
 					"2.0",
 				}, false),
 			},
+
+			"subscription_id": azure.SchemaSubscriptionIdOverride(),
 		},
 	}
 }
 
 func resourceArmRoleAssignmentCreate(d *pluginsdk.ResourceData, meta interface{}) error {
-	roleAssignmentsClient := meta.(*clients.Client).Authorization.RoleAssignmentsClient
-	roleDefinitionsClient := meta.(*clients.Client).Authorization.RoleDefinitionsClient
-	subscriptionClient := meta.(*clients.Client).Subscription.Client
-	subscriptionId := meta.(*clients.Client).Account.SubscriptionId
+	armClient, err := meta.(*clients.Client).ForSubscription(d.Get("subscription_id").(string))
+	if err != nil {
+		return err
+	}
+	roleAssignmentsClient := armClient.Authorization.RoleAssignmentsClient
+	roleDefinitionsClient := armClient.Authorization.RoleDefinitionsClient
+	subscriptionClient := armClient.Subscription.Client
+	subscriptionId := armClient.Account.SubscriptionId
 	ctx, cancel := timeouts.ForCreate(meta.(*clients.Client).StopContext, d)
 	defer cancel()
 
@@ -222,7 +228,7 @@ func resourceArmRoleAssignmentCreate(d *pluginsdk.ResourceData, meta interface{}
 		properties.RoleAssignmentProperties.PrincipalType = authorization.ServicePrincipal
 	}
 
-	if err := pluginsdk.Retry(d.Timeout(pluginsdk.TimeoutCreate), retryRoleAssignmentsClient(d, scope, name, properties, meta, tenantId)); err != nil {
+	if err := pluginsdk.Retry(d.Timeout(pluginsdk.TimeoutCreate), retryRoleAssignmentsClient(d, roleAssignmentsClient, scope, name, properties, meta, tenantId)); err != nil {
 		return err
 	}
 
@@ -239,15 +245,27 @@ func resourceArmRoleAssignmentCreate(d *pluginsdk.ResourceData, meta interface{}
 }
 
 func resourceArmRoleAssignmentRead(d *pluginsdk.ResourceData, meta interface{}) error {
-	client := meta.(*clients.Client).Authorization.RoleAssignmentsClient
-	roleDefinitionsClient := meta.(*clients.Client).Authorization.RoleDefinitionsClient
-	ctx, cancel := timeouts.ForRead(meta.(*clients.Client).StopContext, d)
-	defer cancel()
-
 	id, err := parse.RoleAssignmentID(d.Id())
 	if err != nil {
 		return err
 	}
+
+	// the Subscription can only be determined from the ID when the Role Assignment is scoped to (or within)
+	// a Subscription, otherwise (e.g. for a Management Group) this falls back to the configured Subscription
+	subscriptionId := d.Get("subscription_id").(string)
+	if id.SubscriptionID != "" {
+		subscriptionId = id.SubscriptionID
+	}
+
+	armClient, err := meta.(*clients.Client).ForSubscription(subscriptionId)
+	if err != nil {
+		return err
+	}
+	client := armClient.Authorization.RoleAssignmentsClient
+	roleDefinitionsClient := armClient.Authorization.RoleDefinitionsClient
+	ctx, cancel := timeouts.ForRead(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
 	resp, err := client.GetByID(ctx, id.AzureResourceID(), id.TenantId)
 	if err != nil {
 		if utils.ResponseWasNotFound(resp.Response) {
@@ -261,6 +279,12 @@ func resourceArmRoleAssignmentRead(d *pluginsdk.ResourceData, meta interface{})
 
 	d.Set("name", resp.Name)
 
+	// the Subscription of the Scope (which is used to read the Role Assignment) can differ to the `subscription_id`
+	// specified, so this is only set when it wasn't specified - since changing it would otherwise force a new resource
+	if d.Get("subscription_id").(string) == "" {
+		d.Set("subscription_id", armClient.Account.SubscriptionId)
+	}
+
 	if props := resp.RoleAssignmentPropertiesWithScope; props != nil {
 		d.Set("scope", props.Scope)
 		d.Set("role_definition_id", props.RoleDefinitionID)
@@ -288,7 +312,11 @@ func resourceArmRoleAssignmentRead(d *pluginsdk.ResourceData, meta interface{})
 }
 
 func resourceArmRoleAssignmentDelete(d *pluginsdk.ResourceData, meta interface{}) error {
-	client := meta.(*clients.Client).Authorization.RoleAssignmentsClient
+	armClient, err := meta.(*clients.Client).ForSubscription(d.Get("subscription_id").(string))
+	if err != nil {
+		return err
+	}
+	client := armClient.Authorization.RoleAssignmentsClient
 	ctx, cancel := timeouts.ForDelete(meta.(*clients.Client).StopContext, d)
 	defer cancel()
 
@@ -308,9 +336,8 @@ func resourceArmRoleAssignmentDelete(d *pluginsdk.ResourceData, meta interface{}
 }
 
 //lintignore:R006
-func retryRoleAssignmentsClient(d *pluginsdk.ResourceData, scope string, name string, properties authorization.RoleAssignmentCreateParameters, meta interface{}, tenantId string) func() *pluginsdk.RetryError {
+func retryRoleAssignmentsClient(d *pluginsdk.ResourceData, roleAssignmentsClient *authorization.RoleAssignmentsClient, scope string, name string, properties authorization.RoleAssignmentCreateParameters, meta interface{}, tenantId string) func() *pluginsdk.RetryError {
 	return func() *pluginsdk.RetryError {
-		roleAssignmentsClient := meta.(*clients.Client).Authorization.RoleAssignmentsClient
 		ctx, cancel := timeouts.ForCreate(meta.(*clients.Client).StopContext, d)
 		defer cancel()
 
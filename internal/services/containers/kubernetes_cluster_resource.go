@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-provider-azurerm/helpers/tf"
 	"github.com/hashicorp/terraform-provider-azurerm/helpers/validate"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/lro"
 	computeValidate "github.com/hashicorp/terraform-provider-azurerm/internal/services/compute/validate"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/services/containers/kubernetes"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/services/containers/parse"
@@ -888,7 +889,20 @@ func resourceKubernetesClusterCreate(d *pluginsdk.ResourceData, meta interface{}
 	}
 
 	if existing.ID != nil && *existing.ID != "" {
-		return tf.ImportAsExistsError("azurerm_kubernetes_cluster", *existing.ID)
+		// when the Provider was killed part-way through the creation the Managed Kubernetes Cluster is still being provisioned,
+		// as such it's adopted once provisioned (and then updated below) rather than requiring that it's imported
+		provisioningState := ""
+		if props := existing.ManagedClusterProperties; props != nil && props.ProvisioningState != nil {
+			provisioningState = *props.ProvisioningState
+		}
+		if !lro.IsProvisioning(provisioningState) {
+			return tf.ImportAsExistsError("azurerm_kubernetes_cluster", *existing.ID)
+		}
+
+		log.Printf("[DEBUG] Managed Kubernetes Cluster %q (Resource Group %q) is still being provisioned - waiting for this to complete to adopt it..", name, resGroup)
+		if err := lro.WaitForProvisioning(ctx, d.Timeout(pluginsdk.TimeoutCreate), kubernetesClusterProvisioningState(client, resGroup, name)); err != nil {
+			return fmt.Errorf("waiting for the existing Managed Kubernetes Cluster %q (Resource Group %q) to be provisioned: %+v", name, resGroup, err)
+		}
 	}
 
 	if err := validateKubernetesCluster(d, nil, resGroup, name); err != nil {
@@ -1044,6 +1058,12 @@ func resourceKubernetesClusterCreate(d *pluginsdk.ResourceData, meta interface{}
 	}
 
 	if err = future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		if lro.WasInterrupted(ctx) {
+			// the Managed Kubernetes Cluster continues to be provisioned, so this is resumed when it's next read
+			id := parse.NewClusterID(meta.(*clients.Client).Account.SubscriptionId, resGroup, name)
+			d.SetId(id.ID())
+			return lro.Interrupted(id.ID(), lro.OperationCreate, future.FutureAPI)
+		}
 		return fmt.Errorf("waiting for creation of Managed Kubernetes Cluster %q (Resource Group %q): %+v", name, resGroup, err)
 	}
 
@@ -1435,6 +1455,14 @@ func resourceKubernetesClusterRead(d *pluginsdk.ResourceData, meta interface{})
 		return err
 	}
 
+	// when the creation of the Managed Kubernetes Cluster was interrupted, we need to check whether it's been provisioned
+	if err := lro.Resume(ctx, d, client.Client); err != nil {
+		return fmt.Errorf("checking the creation of %s: %+v", *id, err)
+	}
+	if d.Id() == "" {
+		return nil
+	}
+
 	resp, err := client.Get(ctx, id.ResourceGroup, id.ManagedClusterName)
 	if err != nil {
 		if utils.ResponseWasNotFound(resp.Response) {
@@ -2539,3 +2567,18 @@ func flattenKubernetesClusterMaintenanceConfigurationTimeInWeeks(input *[]contai
 	}
 	return results
 }
+
+func kubernetesClusterProvisioningState(client *containerservice.ManagedClustersClient, resourceGroup, name string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		resp, err := client.Get(ctx, resourceGroup, name)
+		if err != nil {
+			return "", fmt.Errorf("retrieving Managed Kubernetes Cluster %q (Resource Group %q): %+v", name, resourceGroup, err)
+		}
+
+		if resp.ManagedClusterProperties == nil || resp.ManagedClusterProperties.ProvisioningState == nil {
+			return "", fmt.Errorf("`properties.provisioningState` was nil for Managed Kubernetes Cluster %q (Resource Group %q)", name, resourceGroup)
+		}
+
+		return *resp.ManagedClusterProperties.ProvisioningState, nil
+	}
+}
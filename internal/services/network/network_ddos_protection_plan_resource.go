@@ -90,10 +90,15 @@ func resourceNetworkDDoSProtectionPlanCreateUpdate(d *pluginsdk.ResourceData, me
 		return fmt.Errorf("Error extracting names of Virtual Network: %+v", err)
 	}
 
-	locks.ByName(name, azureNetworkDDoSProtectionPlanResourceName)
+	ctx = locks.WithNewOwner(ctx, "azurerm_network_ddos_protection_plan")
+	if err := locks.LockByNameCtx(ctx, name, azureNetworkDDoSProtectionPlanResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(name, azureNetworkDDoSProtectionPlanResourceName)
 
-	locks.MultipleByName(vnetsToLock, VirtualNetworkResourceName)
+	if err := locks.LockMultipleByNameCtx(ctx, vnetsToLock, VirtualNetworkResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockMultipleByName(vnetsToLock, VirtualNetworkResourceName)
 
 	parameters := network.DdosProtectionPlan{
@@ -191,10 +196,15 @@ func resourceNetworkDDoSProtectionPlanDelete(d *pluginsdk.ResourceData, meta int
 		return fmt.Errorf("Error extracting names of Virtual Network: %+v", err)
 	}
 
-	locks.ByName(name, azureNetworkDDoSProtectionPlanResourceName)
+	ctx = locks.WithNewOwner(ctx, "azurerm_network_ddos_protection_plan")
+	if err := locks.LockByNameCtx(ctx, name, azureNetworkDDoSProtectionPlanResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(name, azureNetworkDDoSProtectionPlanResourceName)
 
-	locks.MultipleByName(vnetsToLock, VirtualNetworkResourceName)
+	if err := locks.LockMultipleByNameCtx(ctx, vnetsToLock, VirtualNetworkResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockMultipleByName(vnetsToLock, VirtualNetworkResourceName)
 
 	future, err := client.Delete(ctx, resourceGroup, name)
@@ -1,6 +1,9 @@
 package network
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-11-01/network"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/locks"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/services/network/parse"
@@ -12,9 +15,20 @@ type networkInterfaceIPConfigurationLockingDetails struct {
 	virtualNetworkNamesToLock []string
 }
 
-func (details networkInterfaceIPConfigurationLockingDetails) lock() {
-	locks.MultipleByName(&details.subnetNamesToLock, SubnetResourceName)
-	locks.MultipleByName(&details.virtualNetworkNamesToLock, VirtualNetworkResourceName)
+// lock locks the Virtual Networks and then the Subnets - which is the same order used by the
+// other resources which lock both (e.g. the Subnet and the Subnet Associations), so that these
+// can't deadlock one another. The Context should specify the Owner for this operation.
+func (details networkInterfaceIPConfigurationLockingDetails) lock(ctx context.Context) error {
+	if err := locks.LockMultipleByNameCtx(ctx, &details.virtualNetworkNamesToLock, VirtualNetworkResourceName); err != nil {
+		return fmt.Errorf("locking Virtual Networks: %+v", err)
+	}
+
+	if err := locks.LockMultipleByNameCtx(ctx, &details.subnetNamesToLock, SubnetResourceName); err != nil {
+		locks.UnlockMultipleByName(&details.virtualNetworkNamesToLock, VirtualNetworkResourceName)
+		return fmt.Errorf("locking Subnets: %+v", err)
+	}
+
+	return nil
 }
 
 func (details networkInterfaceIPConfigurationLockingDetails) unlock() {
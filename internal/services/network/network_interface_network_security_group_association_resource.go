@@ -68,7 +68,10 @@ func resourceNetworkInterfaceSecurityGroupAssociationCreate(d *pluginsdk.Resourc
 	networkInterfaceName := nicId.Path["networkInterfaces"]
 	resourceGroup := nicId.ResourceGroup
 
-	locks.ByName(networkInterfaceName, networkInterfaceResourceName)
+	ctx = locks.WithNewOwner(ctx, "azurerm_network_interface_security_group_association")
+	if err := locks.LockByNameCtx(ctx, networkInterfaceName, networkInterfaceResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(networkInterfaceName, networkInterfaceResourceName)
 
 	nsgId, err := azure.ParseAzureResourceID(networkSecurityGroupId)
@@ -77,7 +80,9 @@ func resourceNetworkInterfaceSecurityGroupAssociationCreate(d *pluginsdk.Resourc
 	}
 	nsgName := nsgId.Path["networkSecurityGroups"]
 
-	locks.ByName(nsgName, networkSecurityGroupResourceName)
+	if err := locks.LockByNameCtx(ctx, nsgName, networkSecurityGroupResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(nsgName, networkSecurityGroupResourceName)
 
 	read, err := client.Get(ctx, resourceGroup, networkInterfaceName, "")
@@ -124,13 +124,20 @@ func resourceNetworkProfileCreateUpdate(d *pluginsdk.ResourceData, meta interfac
 		return fmt.Errorf("Error extracting names of Subnet and Virtual Network: %+v", err)
 	}
 
-	locks.ByName(name, azureNetworkProfileResourceName)
+	ctx = locks.WithNewOwner(ctx, "azurerm_network_profile")
+	if err := locks.LockByNameCtx(ctx, name, azureNetworkProfileResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(name, azureNetworkProfileResourceName)
 
-	locks.MultipleByName(vnetsToLock, VirtualNetworkResourceName)
+	if err := locks.LockMultipleByNameCtx(ctx, vnetsToLock, VirtualNetworkResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockMultipleByName(vnetsToLock, VirtualNetworkResourceName)
 
-	locks.MultipleByName(subnetsToLock, SubnetResourceName)
+	if err := locks.LockMultipleByNameCtx(ctx, subnetsToLock, SubnetResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockMultipleByName(subnetsToLock, SubnetResourceName)
 
 	parameters := network.Profile{
@@ -231,13 +238,20 @@ func resourceNetworkProfileDelete(d *pluginsdk.ResourceData, meta interface{}) e
 		return fmt.Errorf("Error extracting names of Subnet and Virtual Network: %+v", err)
 	}
 
-	locks.ByName(name, azureNetworkProfileResourceName)
+	ctx = locks.WithNewOwner(ctx, "azurerm_network_profile")
+	if err := locks.LockByNameCtx(ctx, name, azureNetworkProfileResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(name, azureNetworkProfileResourceName)
 
-	locks.MultipleByName(vnetsToLock, VirtualNetworkResourceName)
+	if err := locks.LockMultipleByNameCtx(ctx, vnetsToLock, VirtualNetworkResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockMultipleByName(vnetsToLock, VirtualNetworkResourceName)
 
-	locks.MultipleByName(subnetsToLock, SubnetResourceName)
+	if err := locks.LockMultipleByNameCtx(ctx, subnetsToLock, SubnetResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockMultipleByName(subnetsToLock, SubnetResourceName)
 
 	if _, err = client.Delete(ctx, resourceGroup, name); err != nil {
@@ -72,11 +72,18 @@ func resourceSubnetNatGatewayAssociationCreate(d *pluginsdk.ResourceData, meta i
 
 	gatewayName := parsedGatewayId.Name
 
-	locks.ByName(gatewayName, natGatewayResourceName)
+	ctx = locks.WithNewOwner(ctx, "azurerm_subnet_nat_gateway_association")
+	if err := locks.LockByNameCtx(ctx, gatewayName, natGatewayResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(gatewayName, natGatewayResourceName)
-	locks.ByName(virtualNetworkName, VirtualNetworkResourceName)
+	if err := locks.LockByNameCtx(ctx, virtualNetworkName, VirtualNetworkResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(virtualNetworkName, VirtualNetworkResourceName)
-	locks.ByName(subnetName, SubnetResourceName)
+	if err := locks.LockByNameCtx(ctx, subnetName, SubnetResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(subnetName, SubnetResourceName)
 
 	subnet, err := client.Get(ctx, resourceGroup, virtualNetworkName, subnetName, "")
@@ -195,9 +202,14 @@ func resourceSubnetNatGatewayAssociationDelete(d *pluginsdk.ResourceData, meta i
 	}
 
 	gatewayName := parsedGatewayId.Path["natGateways"]
-	locks.ByName(gatewayName, natGatewayResourceName)
+	ctx = locks.WithNewOwner(ctx, "azurerm_subnet_nat_gateway_association")
+	if err := locks.LockByNameCtx(ctx, gatewayName, natGatewayResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(gatewayName, natGatewayResourceName)
-	locks.ByName(virtualNetworkName, VirtualNetworkResourceName)
+	if err := locks.LockByNameCtx(ctx, virtualNetworkName, VirtualNetworkResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(virtualNetworkName, VirtualNetworkResourceName)
 
 	// ensure we get the latest state
@@ -69,17 +69,24 @@ func resourceSubnetNetworkSecurityGroupAssociationCreate(d *pluginsdk.ResourceDa
 		return err
 	}
 
-	locks.ByName(parsedNetworkSecurityGroupId.Name, networkSecurityGroupResourceName)
+	ctx = locks.WithNewOwner(ctx, "azurerm_subnet_network_security_group_association")
+	if err := locks.LockByNameCtx(ctx, parsedNetworkSecurityGroupId.Name, networkSecurityGroupResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(parsedNetworkSecurityGroupId.Name, networkSecurityGroupResourceName)
 
 	subnetName := parsedSubnetId.Path["subnets"]
 	virtualNetworkName := parsedSubnetId.Path["virtualNetworks"]
 	resourceGroup := parsedSubnetId.ResourceGroup
 
-	locks.ByName(virtualNetworkName, VirtualNetworkResourceName)
+	if err := locks.LockByNameCtx(ctx, virtualNetworkName, VirtualNetworkResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(virtualNetworkName, VirtualNetworkResourceName)
 
-	locks.ByName(subnetName, SubnetResourceName)
+	if err := locks.LockByNameCtx(ctx, subnetName, SubnetResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(subnetName, SubnetResourceName)
 
 	subnet, err := client.Get(ctx, resourceGroup, virtualNetworkName, subnetName, "")
@@ -204,13 +211,20 @@ func resourceSubnetNetworkSecurityGroupAssociationDelete(d *pluginsdk.ResourceDa
 		return err
 	}
 
-	locks.ByName(parsedNetworkSecurityGroupId.Name, networkSecurityGroupResourceName)
+	ctx = locks.WithNewOwner(ctx, "azurerm_subnet_network_security_group_association")
+	if err := locks.LockByNameCtx(ctx, parsedNetworkSecurityGroupId.Name, networkSecurityGroupResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(parsedNetworkSecurityGroupId.Name, networkSecurityGroupResourceName)
 
-	locks.ByName(virtualNetworkName, VirtualNetworkResourceName)
+	if err := locks.LockByNameCtx(ctx, virtualNetworkName, VirtualNetworkResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(virtualNetworkName, VirtualNetworkResourceName)
 
-	locks.ByName(subnetName, SubnetResourceName)
+	if err := locks.LockByNameCtx(ctx, subnetName, SubnetResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(subnetName, SubnetResourceName)
 
 	// then re-retrieve it to ensure we've got the latest state
@@ -404,10 +404,15 @@ func resourceSubnetDelete(d *pluginsdk.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	locks.ByName(id.VirtualNetworkName, VirtualNetworkResourceName)
+	ctx = locks.WithNewOwner(ctx, "azurerm_subnet")
+	if err := locks.LockByNameCtx(ctx, id.VirtualNetworkName, VirtualNetworkResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(id.VirtualNetworkName, VirtualNetworkResourceName)
 
-	locks.ByName(id.Name, SubnetResourceName)
+	if err := locks.LockByNameCtx(ctx, id.Name, SubnetResourceName); err != nil {
+		return err
+	}
 	defer locks.UnlockByName(id.Name, SubnetResourceName)
 
 	future, err := client.Delete(ctx, id.ResourceGroup, id.VirtualNetworkName, id.Name)
@@ -70,14 +70,20 @@ func resourcePrivateDnsZoneVirtualNetworkLink() *pluginsdk.Resource {
 			// TODO: make this case sensitive once the API's fixed https://github.com/Azure/azure-rest-api-specs/issues/10933
 			"resource_group_name": azure.SchemaResourceGroupNameDiffSuppress(),
 
+			"subscription_id": azure.SchemaSubscriptionIdOverride(),
+
 			"tags": tags.Schema(),
 		},
 	}
 }
 
 func resourcePrivateDnsZoneVirtualNetworkLinkCreateUpdate(d *pluginsdk.ResourceData, meta interface{}) error {
-	client := meta.(*clients.Client).PrivateDns.VirtualNetworkLinksClient
-	subscriptionId := meta.(*clients.Client).Account.SubscriptionId
+	subscriptionClient, err := meta.(*clients.Client).ForSubscription(d.Get("subscription_id").(string))
+	if err != nil {
+		return err
+	}
+	client := subscriptionClient.PrivateDns.VirtualNetworkLinksClient
+	subscriptionId := subscriptionClient.Account.SubscriptionId
 	ctx, cancel := timeouts.ForCreateUpdate(meta.(*clients.Client).StopContext, d)
 	defer cancel()
 
@@ -129,7 +135,6 @@ func resourcePrivateDnsZoneVirtualNetworkLinkCreateUpdate(d *pluginsdk.ResourceD
 }
 
 func resourcePrivateDnsZoneVirtualNetworkLinkRead(d *pluginsdk.ResourceData, meta interface{}) error {
-	client := meta.(*clients.Client).PrivateDns.VirtualNetworkLinksClient
 	ctx, cancel := timeouts.ForRead(meta.(*clients.Client).StopContext, d)
 	defer cancel()
 
@@ -138,6 +143,12 @@ func resourcePrivateDnsZoneVirtualNetworkLinkRead(d *pluginsdk.ResourceData, met
 		return err
 	}
 
+	subscriptionClient, err := meta.(*clients.Client).ForSubscription(id.SubscriptionId)
+	if err != nil {
+		return err
+	}
+	client := subscriptionClient.PrivateDns.VirtualNetworkLinksClient
+
 	resp, err := client.Get(ctx, id.ResourceGroup, id.PrivateDnsZoneName, id.Name)
 	if err != nil {
 		if utils.ResponseWasNotFound(resp.Response) {
@@ -150,6 +161,7 @@ func resourcePrivateDnsZoneVirtualNetworkLinkRead(d *pluginsdk.ResourceData, met
 	d.Set("name", id.Name)
 	d.Set("private_dns_zone_name", id.PrivateDnsZoneName)
 	d.Set("resource_group_name", id.ResourceGroup)
+	d.Set("subscription_id", id.SubscriptionId)
 
 	if props := resp.VirtualNetworkLinkProperties; props != nil {
 		d.Set("registration_enabled", props.RegistrationEnabled)
@@ -163,7 +175,6 @@ func resourcePrivateDnsZoneVirtualNetworkLinkRead(d *pluginsdk.ResourceData, met
 }
 
 func resourcePrivateDnsZoneVirtualNetworkLinkDelete(d *pluginsdk.ResourceData, meta interface{}) error {
-	client := meta.(*clients.Client).PrivateDns.VirtualNetworkLinksClient
 	ctx, cancel := timeouts.ForDelete(meta.(*clients.Client).StopContext, d)
 	defer cancel()
 
@@ -172,6 +183,12 @@ func resourcePrivateDnsZoneVirtualNetworkLinkDelete(d *pluginsdk.ResourceData, m
 		return err
 	}
 
+	subscriptionClient, err := meta.(*clients.Client).ForSubscription(id.SubscriptionId)
+	if err != nil {
+		return err
+	}
+	client := subscriptionClient.PrivateDns.VirtualNetworkLinksClient
+
 	etag := ""
 	if future, err := client.Delete(ctx, id.ResourceGroup, id.PrivateDnsZoneName, id.Name, etag); err != nil {
 		if response.WasNotFound(future.Response()) {
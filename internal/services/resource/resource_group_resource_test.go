@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2020-06-01/resources"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance/check"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
@@ -55,6 +56,22 @@ func TestAccResourceGroup_disappears(t *testing.T) {
 	})
 }
 
+func TestAccResourceGroup_drift(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_resource_group", "test")
+
+	testResource := ResourceGroupResource{}
+	data.ResourceTest(t, testResource, []acceptance.TestStep{
+		data.DriftStep(acceptance.DriftStepData{
+			Config:       testResource.withTagsConfig,
+			TestResource: testResource,
+			ExpectedChanges: map[string]string{
+				"tags.environment":     "Production",
+				"tags_all.environment": "Production",
+			},
+		}),
+	})
+}
+
 func TestAccResourceGroup_withTags(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_resource_group", "test")
 
@@ -100,6 +117,22 @@ func (t ResourceGroupResource) Destroy(ctx context.Context, client *clients.Clie
 	return utils.Bool(true), nil
 }
 
+func (t ResourceGroupResource) Drift(ctx context.Context, client *clients.Client, state *pluginsdk.InstanceState) (*bool, error) {
+	resourceGroup := state.Attributes["name"]
+
+	parameters := resources.GroupPatchable{
+		Tags: map[string]*string{
+			"cost_center": utils.String("MSFT"),
+			"environment": utils.String("Drifted"),
+		},
+	}
+	if _, err := client.Resource.GroupsClient.Update(ctx, resourceGroup, parameters); err != nil {
+		return nil, fmt.Errorf("updating Resource Group %q: %+v", resourceGroup, err)
+	}
+
+	return utils.Bool(true), nil
+}
+
 func (t ResourceGroupResource) Exists(ctx context.Context, client *clients.Client, state *pluginsdk.InstanceState) (*bool, error) {
 	name := state.Attributes["name"]
 
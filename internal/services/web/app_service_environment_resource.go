@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/terraform-provider-azurerm/helpers/tf"
 	helpersValidate "github.com/hashicorp/terraform-provider-azurerm/helpers/validate"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/lro"
 	networkParse "github.com/hashicorp/terraform-provider-azurerm/internal/services/network/parse"
 	networkValidate "github.com/hashicorp/terraform-provider-azurerm/internal/services/network/validate"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/services/web/parse"
@@ -225,7 +226,20 @@ func resourceAppServiceEnvironmentCreate(d *pluginsdk.ResourceData, meta interfa
 	}
 
 	if existing.ID != nil && *existing.ID != "" {
-		return tf.ImportAsExistsError("azurerm_app_service_environment", *existing.ID)
+		// when the Provider was killed part-way through the creation the App Service Environment is still being provisioned,
+		// as such it's adopted once provisioned (and then updated below) rather than requiring that it's imported
+		provisioningState := ""
+		if props := existing.AppServiceEnvironment; props != nil {
+			provisioningState = string(props.ProvisioningState)
+		}
+		if !lro.IsProvisioning(provisioningState) {
+			return tf.ImportAsExistsError("azurerm_app_service_environment", *existing.ID)
+		}
+
+		log.Printf("[DEBUG] App Service Environment %q (Resource Group %q) is still being provisioned - waiting for this to complete to adopt it..", name, resourceGroup)
+		if err := waitForAppServiceEnvironmentProvisioning(ctx, client, resourceGroup, name, d.Timeout(pluginsdk.TimeoutCreate)); err != nil {
+			return fmt.Errorf("waiting for the existing App Service Environment %q (Resource Group %q) to be provisioned: %+v", name, resourceGroup, err)
+		}
 	}
 
 	frontEndScaleFactor := d.Get("front_end_scale_factor").(int)
@@ -256,20 +270,14 @@ func resourceAppServiceEnvironmentCreate(d *pluginsdk.ResourceData, meta interfa
 		return fmt.Errorf("creating App Service Environment %q (Resource Group %q): %+v", name, resourceGroup, err)
 	}
 
-	createWait := pluginsdk.StateChangeConf{
-		Pending: []string{
-			string(web.ProvisioningStateInProgress),
-		},
-		Target: []string{
-			string(web.ProvisioningStateSucceeded),
-		},
-		MinTimeout: 1 * time.Minute,
-		Timeout:    d.Timeout(pluginsdk.TimeoutCreate),
-		Refresh:    appServiceEnvironmentRefresh(ctx, client, resourceGroup, name),
-	}
-
 	// as such we'll ignore it and use a custom poller instead
-	if _, err := createWait.WaitForStateContext(ctx); err != nil {
+	if err := waitForAppServiceEnvironmentProvisioning(ctx, client, resourceGroup, name, d.Timeout(pluginsdk.TimeoutCreate)); err != nil {
+		if lro.WasInterrupted(ctx) {
+			// the App Service Environment continues to be provisioned, so this is resumed when it's next read
+			id := parse.NewAppServiceEnvironmentID(meta.(*clients.Client).Account.SubscriptionId, resourceGroup, name)
+			d.SetId(id.ID())
+			return lro.Interrupted(id.ID(), lro.OperationCreate, nil)
+		}
 		return fmt.Errorf("waiting for the creation of App Service Environment %q (Resource Group %q): %+v", name, resourceGroup, err)
 	}
 
@@ -358,6 +366,28 @@ func resourceAppServiceEnvironmentRead(d *pluginsdk.ResourceData, meta interface
 		return err
 	}
 
+	// when the creation of the App Service Environment was interrupted, we need to check whether it's been provisioned
+	err = lro.ResumeFunc(ctx, d, func(ctx context.Context, pending lro.PendingOperation) (bool, error) {
+		_, state, err := appServiceEnvironmentRefresh(ctx, client, id.ResourceGroup, id.HostingEnvironmentName)()
+		if err != nil {
+			return false, err
+		}
+
+		switch web.ProvisioningState(state) {
+		case web.ProvisioningStateInProgress:
+			return false, nil
+		case web.ProvisioningStateSucceeded:
+			return true, nil
+		}
+		return true, fmt.Errorf("the provisioning state was %q", state)
+	})
+	if err != nil {
+		return fmt.Errorf("checking the provisioning of %s: %+v", *id, err)
+	}
+	if d.Id() == "" {
+		return nil
+	}
+
 	existing, err := client.Get(ctx, id.ResourceGroup, id.HostingEnvironmentName)
 	if err != nil {
 		if utils.ResponseWasNotFound(existing.Response) {
@@ -451,6 +481,23 @@ func resourceAppServiceEnvironmentDelete(d *pluginsdk.ResourceData, meta interfa
 	return nil
 }
 
+func waitForAppServiceEnvironmentProvisioning(ctx context.Context, client *web.AppServiceEnvironmentsClient, resourceGroup string, name string, timeout time.Duration) error {
+	stateConf := &pluginsdk.StateChangeConf{
+		Pending: []string{
+			string(web.ProvisioningStateInProgress),
+		},
+		Target: []string{
+			string(web.ProvisioningStateSucceeded),
+		},
+		MinTimeout: 1 * time.Minute,
+		Timeout:    timeout,
+		Refresh:    appServiceEnvironmentRefresh(ctx, client, resourceGroup, name),
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
+
 func appServiceEnvironmentRefresh(ctx context.Context, client *web.AppServiceEnvironmentsClient, resourceGroup string, name string) pluginsdk.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 		read, err := client.Get(ctx, resourceGroup, name)
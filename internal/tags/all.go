@@ -0,0 +1,271 @@
+package tags
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+// allFieldName is the name of the Computed field containing all of the Tags on a Resource,
+// including the Default Tags configured in the Provider
+const allFieldName = "tags_all"
+
+// SchemaAll returns the Schema used for the `tags_all` field, containing all of the Tags on a
+// Resource - including the Default Tags configured in the Provider
+func SchemaAll() *pluginsdk.Schema {
+	return &pluginsdk.Schema{
+		Type:     pluginsdk.TypeMap,
+		Computed: true,
+		Elem: &pluginsdk.Schema{
+			Type: pluginsdk.TypeString,
+		},
+	}
+}
+
+// SupportsProviderTags returns whether the specified Resource has configurable Tags (e.g. using Schema
+// or ForceNewSchema) and as such should have the `default_tags` and `ignore_tags` from the Provider applied
+func SupportsProviderTags(resource *pluginsdk.Resource) bool {
+	if resource == nil || resource.Schema == nil {
+		return false
+	}
+
+	if _, exists := resource.Schema[allFieldName]; exists {
+		return false
+	}
+
+	v, ok := resource.Schema["tags"]
+	if !ok || v == nil {
+		return false
+	}
+
+	return v.Type == pluginsdk.TypeMap && v.Optional && !v.Computed
+}
+
+// AddDefaultsSupport adds the `tags_all` field to the specified Resource, alongside a CustomizeDiff
+// which plans the Tags which will be applied to the Resource (including the Default Tags).
+//
+// The Default Tags are only merged into the top-level `tags` field of the Resource, when it's created
+// or updated - and are separated back out into the `tags_all` field when the Resource is read. Any
+// Tags ignored in the Provider are removed from both the `tags` and `tags_all` fields.
+func AddDefaultsSupport(resource *pluginsdk.Resource, defaults DefaultsFunc, ignored IgnoredFunc) {
+	resource.Schema[allFieldName] = SchemaAll()
+
+	if resource.CustomizeDiff == nil {
+		resource.CustomizeDiff = customizeDiffAll(defaults, ignored)
+	} else {
+		resource.CustomizeDiff = pluginsdk.CustomDiffInSequence(resource.CustomizeDiff, customizeDiffAll(defaults, ignored))
+	}
+
+	if create := resource.Create; create != nil { //nolint:SA1019
+		resource.Create = func(d *pluginsdk.ResourceData, meta interface{}) error { //nolint:SA1019
+			return writeWithDefaults(d, defaults(meta), ignored(meta), func() error {
+				return create(d, meta)
+			})
+		}
+	}
+	if create := resource.CreateContext; create != nil {
+		resource.CreateContext = func(ctx context.Context, d *pluginsdk.ResourceData, meta interface{}) diag.Diagnostics {
+			return diagnosticsWithDefaults(d, defaults(meta), ignored(meta), writeWithDefaults, func() diag.Diagnostics {
+				return create(ctx, d, meta)
+			})
+		}
+	}
+
+	if read := resource.Read; read != nil { //nolint:SA1019
+		resource.Read = func(d *pluginsdk.ResourceData, meta interface{}) error { //nolint:SA1019
+			return readWithDefaults(d, defaults(meta), ignored(meta), func() error {
+				return read(d, meta)
+			})
+		}
+	}
+	if read := resource.ReadContext; read != nil {
+		resource.ReadContext = func(ctx context.Context, d *pluginsdk.ResourceData, meta interface{}) diag.Diagnostics {
+			return diagnosticsWithDefaults(d, defaults(meta), ignored(meta), readWithDefaults, func() diag.Diagnostics {
+				return read(ctx, d, meta)
+			})
+		}
+	}
+
+	if update := resource.Update; update != nil { //nolint:SA1019
+		resource.Update = func(d *pluginsdk.ResourceData, meta interface{}) error { //nolint:SA1019
+			return writeWithDefaults(d, defaults(meta), ignored(meta), func() error {
+				return update(d, meta)
+			})
+		}
+	}
+	if update := resource.UpdateContext; update != nil {
+		resource.UpdateContext = func(ctx context.Context, d *pluginsdk.ResourceData, meta interface{}) diag.Diagnostics {
+			return diagnosticsWithDefaults(d, defaults(meta), ignored(meta), writeWithDefaults, func() diag.Diagnostics {
+				return update(ctx, d, meta)
+			})
+		}
+	}
+}
+
+// writeWithDefaults merges the Default Tags into the `tags` field prior to the Resource being created/updated,
+// such that these are sent to the API by the Resource (e.g. using Expand) - and then populates the `tags_all`
+// field from the `tags` field set when the Resource is read at the end of the create/update
+func writeWithDefaults(d *pluginsdk.ResourceData, defaults map[string]string, ignored IgnoreTags, next func() error) error {
+	configured := tagsFromResourceData(d)
+	if len(defaults) > 0 {
+		merged := make(map[string]interface{})
+		for k, v := range mergeDefaults(defaults, tagsToStrings(configured)) {
+			merged[k] = v
+		}
+		if err := d.Set("tags", merged); err != nil {
+			return fmt.Errorf("setting `tags`: %+v", err)
+		}
+	}
+
+	err := next()
+	if d.Id() == "" {
+		return err
+	}
+
+	if allErr := setAllFromTags(d, defaults, ignored, configured); allErr != nil && err == nil {
+		return allErr
+	}
+	return err
+}
+
+// readWithDefaults populates the `tags_all` field from the `tags` field set when the Resource is read,
+// removing any Default Tags which aren't specified on the Resource from the `tags` field
+func readWithDefaults(d *pluginsdk.ResourceData, defaults map[string]string, ignored IgnoreTags, next func() error) error {
+	existing := tagsFromResourceData(d)
+	if err := next(); err != nil {
+		return err
+	}
+
+	// the Resource has been removed
+	if d.Id() == "" {
+		return nil
+	}
+
+	return setAllFromTags(d, defaults, ignored, existing)
+}
+
+func diagnosticsWithDefaults(d *pluginsdk.ResourceData, defaults map[string]string, ignored IgnoreTags, wrapper func(*pluginsdk.ResourceData, map[string]string, IgnoreTags, func() error) error, next func() diag.Diagnostics) diag.Diagnostics {
+	var diags diag.Diagnostics
+	err := wrapper(d, defaults, ignored, func() error {
+		diags = next()
+		if diags.HasError() {
+			return fmt.Errorf("the Resource returned an error")
+		}
+		return nil
+	})
+	if err != nil && !diags.HasError() {
+		diags = append(diags, diag.FromErr(err)...)
+	}
+	return diags
+}
+
+// setAllFromTags sets the `tags_all` field to all of the Tags on the Resource (as set into the `tags` field
+// by the Resource) other than the ignored Tags - and then removes any Default Tags which aren't specified
+// (with the same value) in the specified Tags from the `tags` field
+func setAllFromTags(d *pluginsdk.ResourceData, defaults map[string]string, ignored IgnoreTags, specified map[string]interface{}) error {
+	all := ignored.Filter(tagsFromResourceData(d))
+	specifiedTags := tagsToStrings(specified)
+
+	output := make(map[string]interface{})
+	for k, v := range all {
+		// Validate should have ignored this error already
+		value, _ := TagValueToString(v)
+		if isDefault(defaults, k, value) {
+			if existing, ok := specifiedTags[k]; !ok || existing != value {
+				continue
+			}
+		}
+
+		output[k] = v
+	}
+
+	if err := d.Set("tags", output); err != nil {
+		return fmt.Errorf("setting `tags`: %+v", err)
+	}
+	if err := d.Set(allFieldName, all); err != nil {
+		return fmt.Errorf("setting `%s`: %+v", allFieldName, err)
+	}
+
+	return nil
+}
+
+func tagsFromResourceData(d *pluginsdk.ResourceData) map[string]interface{} {
+	output := make(map[string]interface{})
+	if raw, ok := d.Get("tags").(map[string]interface{}); ok {
+		for k, v := range raw {
+			output[k] = v
+		}
+	}
+	return output
+}
+
+func tagsToStrings(input map[string]interface{}) map[string]string {
+	output := make(map[string]string, len(input))
+	for k, v := range input {
+		// Validate should have ignored this error already
+		value, _ := TagValueToString(v)
+		output[k] = value
+	}
+	return output
+}
+
+func customizeDiffAll(defaults DefaultsFunc, ignored IgnoredFunc) pluginsdk.CustomizeDiffFunc {
+	return func(_ context.Context, diff *pluginsdk.ResourceDiff, meta interface{}) error {
+		// the Tags aren't known until apply, so neither are all of the Tags
+		if !diff.NewValueKnown("tags") {
+			return diff.SetNewComputed(allFieldName)
+		}
+
+		configured := make(map[string]string)
+		if raw, ok := diff.Get("tags").(map[string]interface{}); ok {
+			configured = tagsToStrings(raw)
+		}
+
+		ignoredTags := ignored(meta)
+		expected := make(map[string]interface{})
+		for k, v := range mergeDefaults(defaults(meta), configured) {
+			if !ignoredTags.IsIgnored(k) {
+				expected[k] = v
+			}
+		}
+
+		existing, _ := diff.Get(allFieldName).(map[string]interface{})
+		if reflect.DeepEqual(existing, expected) {
+			return nil
+		}
+
+		return diff.SetNew(allFieldName, expected)
+	}
+}
+
+// AddIgnoredSupport suppresses the diff for any Tags ignored in the Provider on the specified Resource, where
+// the ignored Tags are retrieved from the meta for the Provider which the Resource belongs to - since the meta
+// isn't available when suppressing a diff
+func AddIgnoredSupport(resource *pluginsdk.Resource, ignored IgnoredFunc, meta func() interface{}) {
+	existing := resource.Schema["tags"]
+	if existing == nil || existing.DiffSuppressFunc != nil {
+		return
+	}
+
+	// the Schema can be shared between Resources, so this needs to be copied
+	updated := *existing
+	updated.DiffSuppressFunc = func(k, _, _ string, d *pluginsdk.ResourceData) bool {
+		return suppressIgnoredDiff(ignored(meta()), k, d)
+	}
+	resource.Schema["tags"] = &updated
+}
+
+func suppressIgnoredDiff(ignored IgnoreTags, k string, d *pluginsdk.ResourceData) bool {
+	if k == "tags.%" {
+		o, n := d.GetChange("tags")
+		oldTags, _ := o.(map[string]interface{})
+		newTags, _ := n.(map[string]interface{})
+		return len(ignored.Filter(oldTags)) == len(ignored.Filter(newTags))
+	}
+
+	return ignored.IsIgnored(strings.TrimPrefix(k, "tags."))
+}
@@ -0,0 +1,38 @@
+package tags
+
+import (
+	"strings"
+)
+
+// DefaultsFunc returns the Default Tags configured in the `default_tags` block of the Provider
+// from the Provider's meta - since these are configured per Provider (e.g. per alias)
+type DefaultsFunc func(meta interface{}) map[string]string
+
+// mergeDefaults returns the Default Tags merged with the specified Tags, where the specified Tags
+// take precedence - since Tag keys are case-insensitive in Azure a Default Tag is omitted when a
+// Tag with the same key (in any casing) is specified
+func mergeDefaults(defaults map[string]string, tagsMap map[string]string) map[string]string {
+	output := make(map[string]string)
+	for k, v := range defaults {
+		output[k] = v
+	}
+
+	for k := range tagsMap {
+		for existing := range output {
+			if strings.EqualFold(k, existing) {
+				delete(output, existing)
+			}
+		}
+	}
+	for k, v := range tagsMap {
+		output[k] = v
+	}
+
+	return output
+}
+
+// isDefault returns whether the specified Tag has been applied from the Default Tags
+func isDefault(defaults map[string]string, key, value string) bool {
+	v, ok := defaults[key]
+	return ok && v == value
+}
@@ -0,0 +1,587 @@
+package tags
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+	"github.com/hashicorp/terraform-provider-azurerm/utils"
+)
+
+// testProviderMeta is the meta for a Provider which has both Default Tags and ignored Tags configured
+type testProviderMeta struct {
+	defaults map[string]string
+	ignored  IgnoreTags
+}
+
+// testDefaults returns the Default Tags from the meta, which is either a map of the Default Tags or
+// a testProviderMeta in these tests
+func testDefaults(meta interface{}) map[string]string {
+	switch v := meta.(type) {
+	case map[string]string:
+		return v
+	case testProviderMeta:
+		return v.defaults
+	}
+	return nil
+}
+
+// testIgnored returns the ignored Tags from the meta, when it's a testProviderMeta
+func testIgnored(meta interface{}) IgnoreTags {
+	if v, ok := meta.(testProviderMeta); ok {
+		return v.ignored
+	}
+	return IgnoreTags{}
+}
+
+// testTaggedResource is a fake Resource whose remote Tags are stored in-memory - which contains a nested
+// `tags` field (e.g. as in `soa_record` within the `azurerm_dns_zone`) which isn't the Resource's Tags
+type testTaggedResource struct {
+	remote       map[string]*string
+	remoteNested map[string]*string
+}
+
+func (r *testTaggedResource) resource() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Create: r.write,
+		Read:   r.read,
+		Update: r.write,
+		Delete: func(d *pluginsdk.ResourceData, meta interface{}) error {
+			return nil
+		},
+		Schema: map[string]*pluginsdk.Schema{
+			"nested": {
+				Type:     pluginsdk.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &pluginsdk.Resource{
+					Schema: map[string]*pluginsdk.Schema{
+						"tags": Schema(),
+					},
+				},
+			},
+			"tags": Schema(),
+		},
+	}
+}
+
+func (r *testTaggedResource) write(d *pluginsdk.ResourceData, meta interface{}) error {
+	r.remote = Expand(d.Get("tags").(map[string]interface{}))
+	r.remoteNested = Expand(d.Get("nested.0.tags").(map[string]interface{}))
+	d.SetId("example")
+	return r.read(d, meta)
+}
+
+func (r *testTaggedResource) read(d *pluginsdk.ResourceData, _ interface{}) error {
+	return FlattenAndSet(d, r.remote)
+}
+
+func TestMergeDefaults(t *testing.T) {
+	defaults := map[string]string{
+		"cost-center": "1234",
+		"env":         "prod",
+		"owner":       "platform",
+	}
+
+	testData := []struct {
+		Name     string
+		Input    map[string]string
+		Expected map[string]string
+	}{
+		{
+			Name:     "No Resource Tags",
+			Input:    map[string]string{},
+			Expected: defaults,
+		},
+		{
+			Name: "Additional Resource Tags",
+			Input: map[string]string{
+				"hello": "world",
+			},
+			Expected: map[string]string{
+				"cost-center": "1234",
+				"env":         "prod",
+				"hello":       "world",
+				"owner":       "platform",
+			},
+		},
+		{
+			Name: "Resource Tags Win",
+			Input: map[string]string{
+				"env": "dev",
+			},
+			Expected: map[string]string{
+				"cost-center": "1234",
+				"env":         "dev",
+				"owner":       "platform",
+			},
+		},
+		{
+			Name: "Resource Tags Win Case Insensitively",
+			Input: map[string]string{
+				"Owner": "someone-else",
+			},
+			Expected: map[string]string{
+				"cost-center": "1234",
+				"env":         "prod",
+				"Owner":       "someone-else",
+			},
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		actual := mergeDefaults(defaults, v.Input)
+		if !reflect.DeepEqual(actual, v.Expected) {
+			t.Fatalf("Expected %+v but got %+v", v.Expected, actual)
+		}
+	}
+}
+
+func TestExpandWithoutDefaults(t *testing.T) {
+	resource := &testTaggedResource{}
+	wrapped := resource.resource()
+	if !SupportsProviderTags(wrapped) {
+		t.Fatalf("expected a Resource using `tags.Schema()` to support Default Tags")
+	}
+	AddDefaultsSupport(wrapped, testDefaults, testIgnored)
+	if SupportsProviderTags(wrapped) {
+		t.Fatalf("expected a Resource which already has `tags_all` to not need Default Tags support adding")
+	}
+
+	// Expand is only used for the Tags, so the Default Tags shouldn't be merged in outside of a Resource
+	actual := ToTypedObject(Expand(map[string]interface{}{
+		"hello": "world",
+	}))
+	expected := map[string]string{
+		"hello": "world",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %+v but got %+v", expected, actual)
+	}
+}
+
+func TestAddDefaultsSupportCreate(t *testing.T) {
+	defaults := map[string]string{
+		"env":   "prod",
+		"owner": "platform",
+	}
+
+	testData := []struct {
+		Name           string
+		Config         map[string]interface{}
+		ExpectedRemote map[string]string
+		ExpectedTags   map[string]string
+		ExpectedAll    map[string]string
+	}{
+		{
+			Name: "Default Tags",
+			Config: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"hello": "world",
+				},
+			},
+			ExpectedRemote: map[string]string{
+				"env":   "prod",
+				"hello": "world",
+				"owner": "platform",
+			},
+			ExpectedTags: map[string]string{
+				"tags.%":     "1",
+				"tags.hello": "world",
+			},
+			ExpectedAll: map[string]string{
+				"tags_all.%":     "3",
+				"tags_all.env":   "prod",
+				"tags_all.hello": "world",
+				"tags_all.owner": "platform",
+			},
+		},
+		{
+			Name: "Resource specifying a Default Tag",
+			Config: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"env":   "prod",
+					"hello": "world",
+				},
+			},
+			ExpectedRemote: map[string]string{
+				"env":   "prod",
+				"hello": "world",
+				"owner": "platform",
+			},
+			ExpectedTags: map[string]string{
+				"tags.%":     "2",
+				"tags.env":   "prod",
+				"tags.hello": "world",
+			},
+			ExpectedAll: map[string]string{
+				"tags_all.%":     "3",
+				"tags_all.env":   "prod",
+				"tags_all.hello": "world",
+				"tags_all.owner": "platform",
+			},
+		},
+		{
+			Name: "Resource overriding a Default Tag",
+			Config: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"env": "dev",
+				},
+			},
+			ExpectedRemote: map[string]string{
+				"env":   "dev",
+				"owner": "platform",
+			},
+			ExpectedTags: map[string]string{
+				"tags.%":   "1",
+				"tags.env": "dev",
+			},
+			ExpectedAll: map[string]string{
+				"tags_all.%":     "2",
+				"tags_all.env":   "dev",
+				"tags_all.owner": "platform",
+			},
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		fake := &testTaggedResource{}
+		resource := fake.resource()
+		AddDefaultsSupport(resource, testDefaults, testIgnored)
+
+		config := terraform.NewResourceConfigRaw(v.Config)
+		diff, err := resource.Diff(context.TODO(), nil, config, defaults)
+		if err != nil {
+			t.Fatalf("diffing: %+v", err)
+		}
+		for k, expected := range v.ExpectedAll {
+			if attr, ok := diff.Attributes[k]; !ok || attr.New != expected {
+				t.Fatalf("expected %q to be planned as %q but got %+v", k, expected, diff.Attributes[k])
+			}
+		}
+
+		state, diags := resource.Apply(context.TODO(), nil, diff, defaults)
+		if diags.HasError() {
+			t.Fatalf("applying: %+v", diags)
+		}
+
+		if actual := ToTypedObject(fake.remote); !reflect.DeepEqual(actual, v.ExpectedRemote) {
+			t.Fatalf("Expected the Tags sent to the API to be %+v but got %+v", v.ExpectedRemote, actual)
+		}
+		assertStateContains(t, state, v.ExpectedTags)
+		assertStateContains(t, state, v.ExpectedAll)
+	}
+}
+
+func TestAddDefaultsSupportNestedTags(t *testing.T) {
+	defaults := map[string]string{
+		"env": "prod",
+	}
+
+	fake := &testTaggedResource{}
+	resource := fake.resource()
+	AddDefaultsSupport(resource, testDefaults, testIgnored)
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"nested": []interface{}{
+			map[string]interface{}{
+				"tags": map[string]interface{}{
+					"hello": "world",
+				},
+			},
+		},
+	})
+	diff, err := resource.Diff(context.TODO(), nil, config, defaults)
+	if err != nil {
+		t.Fatalf("diffing: %+v", err)
+	}
+	if _, diags := resource.Apply(context.TODO(), nil, diff, defaults); diags.HasError() {
+		t.Fatalf("applying: %+v", diags)
+	}
+
+	expected := map[string]string{
+		"hello": "world",
+	}
+	if actual := ToTypedObject(fake.remoteNested); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected the nested Tags sent to the API to be %+v but got %+v", expected, actual)
+	}
+}
+
+func TestAddDefaultsSupportPerProvider(t *testing.T) {
+	// each Provider (e.g. an alias) builds its own Resources, with the Default Tags coming from its meta
+	first := map[string]string{
+		"env": "prod",
+	}
+	second := map[string]string{
+		"env": "dev",
+	}
+
+	for _, defaults := range []map[string]string{first, second, first} {
+		fake := &testTaggedResource{}
+		resource := fake.resource()
+		AddDefaultsSupport(resource, testDefaults, testIgnored)
+
+		diff, err := resource.Diff(context.TODO(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{}), defaults)
+		if err != nil {
+			t.Fatalf("diffing: %+v", err)
+		}
+		if _, diags := resource.Apply(context.TODO(), nil, diff, defaults); diags.HasError() {
+			t.Fatalf("applying: %+v", diags)
+		}
+
+		if actual := ToTypedObject(fake.remote); !reflect.DeepEqual(actual, defaults) {
+			t.Fatalf("Expected the Tags sent to the API to be %+v but got %+v", defaults, actual)
+		}
+	}
+}
+
+func TestAddDefaultsSupportRead(t *testing.T) {
+	defaults := map[string]string{
+		"env":   "prod",
+		"owner": "platform",
+	}
+
+	fake := &testTaggedResource{
+		remote: map[string]*string{
+			"env":   utils.String("prod"),
+			"hello": utils.String("world"),
+			"owner": utils.String("someone-else"),
+		},
+	}
+	resource := fake.resource()
+	AddDefaultsSupport(resource, testDefaults, testIgnored)
+
+	state := &terraform.InstanceState{
+		ID: "example",
+		Attributes: map[string]string{
+			"id":         "example",
+			"tags.%":     "1",
+			"tags.hello": "world",
+		},
+	}
+	refreshed, diags := resource.RefreshWithoutUpgrade(context.TODO(), state, defaults)
+	if diags.HasError() {
+		t.Fatalf("refreshing: %+v", diags)
+	}
+
+	// the Default Tag `owner` has been changed outside of Terraform, so is exposed in `tags`
+	assertStateContains(t, refreshed, map[string]string{
+		"tags.%":         "2",
+		"tags.hello":     "world",
+		"tags.owner":     "someone-else",
+		"tags_all.%":     "3",
+		"tags_all.env":   "prod",
+		"tags_all.hello": "world",
+		"tags_all.owner": "someone-else",
+	})
+}
+
+func TestAddDefaultsSupportReadContext(t *testing.T) {
+	defaults := map[string]string{
+		"env": "prod",
+	}
+
+	// Typed Resources are exposed using ReadContext and set the `tags` field from `tags.Flatten`
+	resource := &pluginsdk.Resource{
+		ReadContext: func(_ context.Context, d *pluginsdk.ResourceData, _ interface{}) diag.Diagnostics {
+			remote := map[string]*string{
+				"env":   utils.String("prod"),
+				"hello": utils.String("world"),
+			}
+			if err := d.Set("tags", Flatten(remote)); err != nil {
+				return diag.FromErr(err)
+			}
+			return nil
+		},
+		Schema: map[string]*pluginsdk.Schema{
+			"tags": Schema(),
+		},
+	}
+	AddDefaultsSupport(resource, testDefaults, testIgnored)
+
+	state := &terraform.InstanceState{
+		ID: "example",
+		Attributes: map[string]string{
+			"id": "example",
+		},
+	}
+	refreshed, diags := resource.RefreshWithoutUpgrade(context.TODO(), state, defaults)
+	if diags.HasError() {
+		t.Fatalf("refreshing: %+v", diags)
+	}
+
+	assertStateContains(t, refreshed, map[string]string{
+		"tags.%":         "1",
+		"tags.hello":     "world",
+		"tags_all.%":     "2",
+		"tags_all.env":   "prod",
+		"tags_all.hello": "world",
+	})
+}
+
+func TestFlattenAndSetDataSource(t *testing.T) {
+	dataSource := &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"tags": SchemaDataSource(),
+		},
+	}
+	d := dataSource.TestResourceData()
+	apiTags := map[string]*string{
+		"env":               utils.String("prod"),
+		"ms-resource-usage": utils.String("azure-cloud-shell"),
+	}
+	if err := FlattenAndSet(d, apiTags); err != nil {
+		t.Fatalf("flattening: %+v", err)
+	}
+
+	// Data Sources expose all of the Tags on the Resource
+	expected := map[string]interface{}{
+		"env":               "prod",
+		"ms-resource-usage": "azure-cloud-shell",
+	}
+	if actual := d.Get("tags").(map[string]interface{}); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected `tags` to be %+v but got %+v", expected, actual)
+	}
+}
+
+func TestAddDefaultsSupportReadWithIgnored(t *testing.T) {
+	meta := testProviderMeta{
+		ignored: IgnoreTags{
+			Keys:        []string{"MS-Resource-Usage"},
+			KeyPrefixes: []string{"hidden-link:"},
+		},
+	}
+
+	fake := &testTaggedResource{
+		remote: map[string]*string{
+			"env":                        utils.String("prod"),
+			"hidden-link:/some/resource": utils.String("Resource"),
+			"ms-resource-usage":          utils.String("azure-cloud-shell"),
+		},
+	}
+	resource := fake.resource()
+	AddDefaultsSupport(resource, testDefaults, testIgnored)
+	AddIgnoredSupport(resource, testIgnored, func() interface{} {
+		return meta
+	})
+
+	state := &terraform.InstanceState{
+		ID: "example",
+		Attributes: map[string]string{
+			"id":       "example",
+			"tags.%":   "1",
+			"tags.env": "prod",
+		},
+	}
+	refreshed, diags := resource.RefreshWithoutUpgrade(context.TODO(), state, meta)
+	if diags.HasError() {
+		t.Fatalf("refreshing: %+v", diags)
+	}
+
+	expected := map[string]string{
+		"tags.%":       "1",
+		"tags.env":     "prod",
+		"tags_all.%":   "1",
+		"tags_all.env": "prod",
+	}
+	assertStateContains(t, refreshed, expected)
+}
+
+func TestAddDefaultsSupportIgnoredPerProvider(t *testing.T) {
+	// each Provider (e.g. an alias) builds its own Resources, with the ignored Tags coming from its meta
+	first := testProviderMeta{
+		ignored: IgnoreTags{
+			Keys: []string{"ms-resource-usage"},
+		},
+	}
+	second := testProviderMeta{}
+
+	for _, meta := range []testProviderMeta{first, second, first} {
+		fake := &testTaggedResource{
+			remote: map[string]*string{
+				"env":               utils.String("prod"),
+				"ms-resource-usage": utils.String("azure-cloud-shell"),
+			},
+		}
+		resource := fake.resource()
+		AddDefaultsSupport(resource, testDefaults, testIgnored)
+
+		state := &terraform.InstanceState{
+			ID: "example",
+			Attributes: map[string]string{
+				"id": "example",
+			},
+		}
+		refreshed, diags := resource.RefreshWithoutUpgrade(context.TODO(), state, meta)
+		if diags.HasError() {
+			t.Fatalf("refreshing: %+v", diags)
+		}
+
+		_, exists := refreshed.Attributes["tags.ms-resource-usage"]
+		if ignored := len(meta.ignored.Keys) > 0; exists == ignored {
+			t.Fatalf("expected the Tag `ms-resource-usage` to be ignored (%t) but got the State %+v", ignored, refreshed.Attributes)
+		}
+	}
+}
+
+func TestDiffWithIgnored(t *testing.T) {
+	meta := testProviderMeta{
+		ignored: IgnoreTags{
+			KeyPrefixes: []string{"hidden-link:"},
+		},
+	}
+
+	resource := &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"tags": Schema(),
+		},
+	}
+	AddDefaultsSupport(resource, testDefaults, testIgnored)
+	AddIgnoredSupport(resource, testIgnored, func() interface{} {
+		return meta
+	})
+
+	state := &terraform.InstanceState{
+		ID: "example",
+		Attributes: map[string]string{
+			"id":           "example",
+			"tags.%":       "1",
+			"tags.env":     "prod",
+			"tags_all.%":   "1",
+			"tags_all.env": "prod",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"tags": map[string]interface{}{
+			"env":                        "prod",
+			"hidden-link:/some/resource": "Resource",
+		},
+	})
+
+	diff, err := resource.Diff(context.TODO(), state, config, meta)
+	if err != nil {
+		t.Fatalf("diffing: %+v", err)
+	}
+	if diff != nil && len(diff.Attributes) > 0 {
+		t.Fatalf("expected no diff for an ignored Tag but got %+v", diff.Attributes)
+	}
+}
+
+func assertStateContains(t *testing.T, state *terraform.InstanceState, expected map[string]string) {
+	if state == nil {
+		t.Fatalf("expected a State but got nil")
+	}
+
+	for k, v := range expected {
+		if actual, ok := state.Attributes[k]; !ok || actual != v {
+			t.Fatalf("expected %q to be %q but got %q (State: %+v)", k, v, actual, state.Attributes)
+		}
+	}
+}
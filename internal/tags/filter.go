@@ -1,6 +1,8 @@
 package tags
 
-import "strings"
+import (
+	"strings"
+)
 
 func Filter(tagsMap map[string]*string, tagNames ...string) map[string]*string {
 	if len(tagNames) == 0 {
@@ -25,3 +27,44 @@ func Filter(tagsMap map[string]*string, tagNames ...string) map[string]*string {
 
 	return tagsRet
 }
+
+// IgnoreTags are the Tags which should be ignored on every Resource, configured in the
+// `ignore_tags` block of the Provider - for example Tags which are managed by Azure Policy
+type IgnoreTags struct {
+	// Keys are the Tag keys which should be ignored (case-insensitively)
+	Keys []string
+
+	// KeyPrefixes are the prefixes of Tag keys which should be ignored (case-insensitively)
+	KeyPrefixes []string
+}
+
+// IgnoredFunc returns the Tags which should be ignored for the Provider which built the specified meta
+type IgnoredFunc func(meta interface{}) IgnoreTags
+
+// IsIgnored returns whether the specified Tag key should be ignored
+func (i IgnoreTags) IsIgnored(key string) bool {
+	key = strings.ToLower(key)
+	for _, v := range i.Keys {
+		if v != "" && key == strings.ToLower(v) {
+			return true
+		}
+	}
+	for _, v := range i.KeyPrefixes {
+		if v != "" && strings.HasPrefix(key, strings.ToLower(v)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter returns the specified Tags without those which should be ignored
+func (i IgnoreTags) Filter(tagsMap map[string]interface{}) map[string]interface{} {
+	output := make(map[string]interface{}, len(tagsMap))
+	for k, v := range tagsMap {
+		if !i.IsIgnored(k) {
+			output[k] = v
+		}
+	}
+	return output
+}
@@ -22,3 +22,52 @@ func TestFilter(t *testing.T) {
 		t.Fatalf("Expected %v in filtered tag map, got %v", valueData[1], *filtered["key2"])
 	}
 }
+
+func TestIsIgnored(t *testing.T) {
+	ignored := IgnoreTags{
+		Keys:        []string{"ms-resource-usage", ""},
+		KeyPrefixes: []string{"Hidden-Link:", ""},
+	}
+
+	testData := []struct {
+		Key      string
+		Expected bool
+	}{
+		{
+			Key:      "ms-resource-usage",
+			Expected: true,
+		},
+		{
+			Key:      "MS-Resource-Usage",
+			Expected: true,
+		},
+		{
+			Key:      "ms-resource-usage-2",
+			Expected: false,
+		},
+		{
+			Key:      "hidden-link:/subscriptions/00000000-0000-0000-0000-000000000000",
+			Expected: true,
+		},
+		{
+			Key:      "Hidden-Link:/app-insights-resource-id",
+			Expected: true,
+		},
+		{
+			Key:      "hidden",
+			Expected: false,
+		},
+		{
+			Key:      "env",
+			Expected: false,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Key)
+
+		if actual := ignored.IsIgnored(v.Key); actual != v.Expected {
+			t.Fatalf("Expected %t but got %t", v.Expected, actual)
+		}
+	}
+}
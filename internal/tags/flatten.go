@@ -21,6 +21,8 @@ func Flatten(tagMap map[string]*string) map[string]interface{} {
 	return output
 }
 
+// FlattenAndSet sets the `tags` field - where the Default Tags and the Tags ignored in the Provider
+// are separated out of this for Resources when they're read (see AddDefaultsSupport)
 func FlattenAndSet(d *pluginsdk.ResourceData, tagMap map[string]*string) error {
 	flattened := Flatten(tagMap)
 	if err := d.Set("tags", flattened); err != nil {
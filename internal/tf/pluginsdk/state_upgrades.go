@@ -23,6 +23,14 @@ type StateUpgrade interface {
 	UpgradeFunc() StateUpgraderFunc
 }
 
+// ValidatableStateUpgrade is an optional interface which allows a StateUpgrade
+// to be validated when the State Upgrades are built, rather than when they're run
+type ValidatableStateUpgrade interface {
+	StateUpgrade
+
+	Validate() error
+}
+
 // StateUpgrades is a wrapper around the Plugin SDK's State Upgraders
 // which allows us to upgrade the Plugin SDK without breaking all open
 // PR's and attempts to make this interface a little less verbose.
@@ -43,6 +51,12 @@ func StateUpgrades(upgrades map[int]StateUpgrade) []StateUpgrader {
 		expectedVersion++
 
 		upgrade := upgrades[version]
+		if v, ok := upgrade.(ValidatableStateUpgrade); ok {
+			if err := v.Validate(); err != nil {
+				panic(fmt.Sprintf("validating the state upgrade for version %d: %+v", version, err))
+			}
+		}
+
 		resource := Resource{
 			Schema: upgrade.Schema(),
 		}
@@ -0,0 +1,162 @@
+package timeouts
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+// DefaultTimeouts are the timeouts configured in the `default_timeouts` block of the Provider, which are
+// used (instead of the timeouts defined by the Resource) for each Resource matching the ResourceType
+type DefaultTimeouts struct {
+	// ResourceType is the Resource Type (e.g. `azurerm_kubernetes_cluster`) or a glob matching
+	// multiple Resource Types (e.g. `azurerm_kubernetes_*`) which these timeouts apply to
+	ResourceType string
+
+	Create *time.Duration
+	Read   *time.Duration
+	Update *time.Duration
+	Delete *time.Duration
+}
+
+// registeredResource is a Resource supporting the Default Timeouts, alongside the timeouts defined by the Resource
+type registeredResource struct {
+	resource *pluginsdk.Resource
+	timeouts pluginsdk.ResourceTimeout
+}
+
+// Defaults applies the Default Timeouts configured in the `default_timeouts` block of a Provider to the Resources
+// of that Provider - since each Provider (e.g. an alias) has its own Resources, these are configured per Provider
+type Defaults struct {
+	lock      sync.Mutex
+	resources map[string]registeredResource
+}
+
+// ValidateResourceTypeGlob validates that the specified value is a valid Resource Type glob (e.g. `azurerm_kubernetes_*`)
+func ValidateResourceTypeGlob(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected %q to be a string", k))
+		return
+	}
+
+	if v == "" {
+		errors = append(errors, fmt.Errorf("%q must not be empty", k))
+		return
+	}
+
+	if _, err := path.Match(v, ""); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a valid glob: %+v", k, err))
+	}
+
+	return
+}
+
+// NewDefaults returns the Defaults for the specified Resources (keyed by Resource Type), capturing the timeouts
+// defined by each Resource - which the Default Timeouts take precedence over, but not a `timeouts` block on the Resource
+func NewDefaults(resources map[string]*pluginsdk.Resource) *Defaults {
+	registered := make(map[string]registeredResource)
+	for resourceType, resource := range resources {
+		if resource == nil || resource.Timeouts == nil {
+			continue
+		}
+
+		registered[resourceType] = registeredResource{
+			resource: resource,
+			timeouts: *resource.Timeouts,
+		}
+	}
+
+	return &Defaults{
+		resources: registered,
+	}
+}
+
+// Configure configures the Default Timeouts, updating the timeouts for each of the Resources
+func (d *Defaults) Configure(input []DefaultTimeouts) error {
+	for _, v := range input {
+		if _, err := path.Match(v.ResourceType, ""); err != nil {
+			return fmt.Errorf("parsing the Resource Type %q: %+v", v.ResourceType, err)
+		}
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for resourceType, registered := range d.resources {
+		*registered.resource.Timeouts = resolve(resourceType, registered.timeouts, input)
+	}
+
+	return nil
+}
+
+func resolve(resourceType string, input pluginsdk.ResourceTimeout, defaults []DefaultTimeouts) pluginsdk.ResourceTimeout {
+	matches := matchingDefaults(resourceType, defaults)
+	if len(matches) == 0 {
+		return input
+	}
+
+	output := input
+	output.Create = resolveOperation(input.Create, matches, func(v DefaultTimeouts) *time.Duration { return v.Create })
+	output.Read = resolveOperation(input.Read, matches, func(v DefaultTimeouts) *time.Duration { return v.Read })
+	output.Update = resolveOperation(input.Update, matches, func(v DefaultTimeouts) *time.Duration { return v.Update })
+	output.Delete = resolveOperation(input.Delete, matches, func(v DefaultTimeouts) *time.Duration { return v.Delete })
+	return output
+}
+
+// resolveOperation returns the timeout from the most specific Default Timeouts which define one for this operation
+func resolveOperation(input *time.Duration, matches []DefaultTimeouts, get func(DefaultTimeouts) *time.Duration) *time.Duration {
+	if input == nil {
+		return nil
+	}
+
+	for _, match := range matches {
+		if v := get(match); v != nil {
+			timeout := *v
+			return &timeout
+		}
+	}
+
+	return input
+}
+
+// matchingDefaults returns the Default Timeouts matching the Resource Type, ordered from the most specific
+// to the least specific - an exact match first, followed by globs ordered by the number of literal characters
+func matchingDefaults(resourceType string, defaults []DefaultTimeouts) []DefaultTimeouts {
+	matches := make([]DefaultTimeouts, 0)
+	for _, v := range defaults {
+		if matched, _ := path.Match(v.ResourceType, resourceType); matched {
+			matches = append(matches, v)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		// an exact match always takes precedence over a glob
+		exactI := matches[i].ResourceType == resourceType
+		exactJ := matches[j].ResourceType == resourceType
+		if exactI != exactJ {
+			return exactI
+		}
+
+		return literals(matches[i].ResourceType) > literals(matches[j].ResourceType)
+	})
+
+	return matches
+}
+
+// literals returns the number of literal (non-wildcard) characters in the glob
+func literals(glob string) int {
+	count := 0
+	for _, c := range glob {
+		switch c {
+		case '*', '?', '[', ']', '\\':
+			continue
+		}
+		count++
+	}
+	return count
+}
@@ -0,0 +1,221 @@
+package timeouts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+func TestDefaultsConfigure(t *testing.T) {
+	duration := func(input time.Duration) *time.Duration {
+		return &input
+	}
+
+	testData := []struct {
+		Name         string
+		ResourceType string
+		Defaults     []DefaultTimeouts
+		Expected     pluginsdk.ResourceTimeout
+	}{
+		{
+			Name:         "No Defaults",
+			ResourceType: "azurerm_kubernetes_cluster",
+			Expected: pluginsdk.ResourceTimeout{
+				Create: duration(90 * time.Minute),
+				Read:   duration(5 * time.Minute),
+				Delete: duration(90 * time.Minute),
+			},
+		},
+		{
+			Name:         "Not Matching",
+			ResourceType: "azurerm_kubernetes_cluster",
+			Defaults: []DefaultTimeouts{
+				{
+					ResourceType: "azurerm_storage_*",
+					Create:       duration(3 * time.Hour),
+				},
+			},
+			Expected: pluginsdk.ResourceTimeout{
+				Create: duration(90 * time.Minute),
+				Read:   duration(5 * time.Minute),
+				Delete: duration(90 * time.Minute),
+			},
+		},
+		{
+			Name:         "Glob",
+			ResourceType: "azurerm_kubernetes_cluster",
+			Defaults: []DefaultTimeouts{
+				{
+					ResourceType: "azurerm_kubernetes_*",
+					Create:       duration(3 * time.Hour),
+					Delete:       duration(2 * time.Hour),
+				},
+			},
+			Expected: pluginsdk.ResourceTimeout{
+				Create: duration(3 * time.Hour),
+				Read:   duration(5 * time.Minute),
+				Delete: duration(2 * time.Hour),
+			},
+		},
+		{
+			Name:         "Operation Not Supported By Resource",
+			ResourceType: "azurerm_kubernetes_cluster",
+			Defaults: []DefaultTimeouts{
+				{
+					ResourceType: "*",
+					Update:       duration(3 * time.Hour),
+				},
+			},
+			Expected: pluginsdk.ResourceTimeout{
+				Create: duration(90 * time.Minute),
+				Read:   duration(5 * time.Minute),
+				Delete: duration(90 * time.Minute),
+			},
+		},
+		{
+			Name:         "Most Specific Takes Precedence",
+			ResourceType: "azurerm_kubernetes_cluster",
+			Defaults: []DefaultTimeouts{
+				{
+					ResourceType: "azurerm_kubernetes_cluster",
+					Create:       duration(4 * time.Hour),
+				},
+				{
+					ResourceType: "*",
+					Create:       duration(time.Hour),
+					Read:         duration(time.Hour),
+					Delete:       duration(time.Hour),
+				},
+				{
+					ResourceType: "azurerm_kubernetes_*",
+					Create:       duration(3 * time.Hour),
+					Delete:       duration(2 * time.Hour),
+				},
+			},
+			Expected: pluginsdk.ResourceTimeout{
+				Create: duration(4 * time.Hour),
+				Read:   duration(time.Hour),
+				Delete: duration(2 * time.Hour),
+			},
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		resource := &pluginsdk.Resource{
+			Timeouts: &pluginsdk.ResourceTimeout{
+				Create: pluginsdk.DefaultTimeout(90 * time.Minute),
+				Read:   pluginsdk.DefaultTimeout(5 * time.Minute),
+				Delete: pluginsdk.DefaultTimeout(90 * time.Minute),
+			},
+		}
+		defaults := NewDefaults(map[string]*pluginsdk.Resource{
+			v.ResourceType: resource,
+		})
+
+		if err := defaults.Configure(v.Defaults); err != nil {
+			t.Fatalf("configuring defaults: %+v", err)
+		}
+
+		assertTimeout(t, "Create", v.Expected.Create, resource.Timeouts.Create)
+		assertTimeout(t, "Read", v.Expected.Read, resource.Timeouts.Read)
+		assertTimeout(t, "Update", v.Expected.Update, resource.Timeouts.Update)
+		assertTimeout(t, "Delete", v.Expected.Delete, resource.Timeouts.Delete)
+	}
+
+}
+
+func TestDefaultsConfigureReverts(t *testing.T) {
+	resource := &pluginsdk.Resource{
+		Timeouts: &pluginsdk.ResourceTimeout{
+			Create: pluginsdk.DefaultTimeout(30 * time.Minute),
+		},
+	}
+	defaults := NewDefaults(map[string]*pluginsdk.Resource{
+		"azurerm_resource_group": resource,
+	})
+
+	if err := defaults.Configure([]DefaultTimeouts{{ResourceType: "azurerm_*", Create: pluginsdk.DefaultTimeout(time.Hour)}}); err != nil {
+		t.Fatalf("configuring defaults: %+v", err)
+	}
+	assertTimeout(t, "Create", pluginsdk.DefaultTimeout(time.Hour), resource.Timeouts.Create)
+
+	// removing the defaults should revert to the timeouts defined by the Resource
+	if err := defaults.Configure(nil); err != nil {
+		t.Fatalf("configuring defaults: %+v", err)
+	}
+	assertTimeout(t, "Create", pluginsdk.DefaultTimeout(30*time.Minute), resource.Timeouts.Create)
+}
+
+func TestDefaultsConfigurePerProvider(t *testing.T) {
+	newResource := func() *pluginsdk.Resource {
+		return &pluginsdk.Resource{
+			Timeouts: &pluginsdk.ResourceTimeout{
+				Create: pluginsdk.DefaultTimeout(30 * time.Minute),
+				Delete: pluginsdk.DefaultTimeout(30 * time.Minute),
+			},
+		}
+	}
+
+	// each Provider (e.g. an alias) has its own Resources, so configuring one mustn't affect the other
+	first := newResource()
+	firstDefaults := NewDefaults(map[string]*pluginsdk.Resource{
+		"azurerm_resource_group": first,
+	})
+	second := newResource()
+	secondDefaults := NewDefaults(map[string]*pluginsdk.Resource{
+		"azurerm_resource_group": second,
+	})
+
+	if err := firstDefaults.Configure([]DefaultTimeouts{{ResourceType: "azurerm_*", Create: pluginsdk.DefaultTimeout(time.Hour)}}); err != nil {
+		t.Fatalf("configuring defaults: %+v", err)
+	}
+	if err := secondDefaults.Configure([]DefaultTimeouts{{ResourceType: "azurerm_resource_group", Delete: pluginsdk.DefaultTimeout(2 * time.Hour)}}); err != nil {
+		t.Fatalf("configuring defaults: %+v", err)
+	}
+
+	assertTimeout(t, "Create", pluginsdk.DefaultTimeout(time.Hour), first.Timeouts.Create)
+	assertTimeout(t, "Delete", pluginsdk.DefaultTimeout(30*time.Minute), first.Timeouts.Delete)
+	assertTimeout(t, "Create", pluginsdk.DefaultTimeout(30*time.Minute), second.Timeouts.Create)
+	assertTimeout(t, "Delete", pluginsdk.DefaultTimeout(2*time.Hour), second.Timeouts.Delete)
+}
+
+func TestDefaultsConfigureWithoutTimeouts(t *testing.T) {
+	resource := &pluginsdk.Resource{}
+	defaults := NewDefaults(map[string]*pluginsdk.Resource{
+		"azurerm_example": resource,
+	})
+
+	if err := defaults.Configure([]DefaultTimeouts{{ResourceType: "*", Create: pluginsdk.DefaultTimeout(time.Hour)}}); err != nil {
+		t.Fatalf("configuring defaults: %+v", err)
+	}
+	if resource.Timeouts != nil {
+		t.Fatalf("expected a Resource without timeouts to be left unchanged but got %+v", resource.Timeouts)
+	}
+}
+
+func TestDefaultsConfigureInvalidGlob(t *testing.T) {
+	err := NewDefaults(nil).Configure([]DefaultTimeouts{
+		{
+			ResourceType: "azurerm_[",
+		},
+	})
+	if err == nil {
+		t.Fatalf("expected an error but didn't get one")
+	}
+}
+
+func assertTimeout(t *testing.T, operation string, expected, actual *time.Duration) {
+	if expected == nil || actual == nil {
+		if expected != actual {
+			t.Fatalf("expected the %s timeout to be %v but got %v", operation, expected, actual)
+		}
+		return
+	}
+
+	if *expected != *actual {
+		t.Fatalf("expected the %s timeout to be %s but got %s", operation, expected.String(), actual.String())
+	}
+}
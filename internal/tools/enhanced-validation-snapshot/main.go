@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/2017-03-09/resources/mgmt/resources"
+	"github.com/hashicorp/go-azure-helpers/authentication"
+	"github.com/hashicorp/go-azure-helpers/sender"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/features"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/location"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/resourceproviders"
+)
+
+func main() {
+	outputPath := flag.String("output", "", "The path to the file which the Snapshot should be written to")
+	environment := flag.String("environment", "", "The name of the Azure Environment, defaults to the `ARM_ENVIRONMENT` Environment Variable (or `public`)")
+	metadataHost := flag.String("metadata-host", "", "The Hostname of the Azure Metadata Service, defaults to the `ARM_METADATA_HOST` Environment Variable")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	if *environment == "" {
+		*environment = os.Getenv("ARM_ENVIRONMENT")
+	}
+	if *environment == "" {
+		*environment = "public"
+	}
+	if *metadataHost == "" {
+		*metadataHost = os.Getenv("ARM_METADATA_HOST")
+	}
+
+	if err := run(*outputPath, *environment, *metadataHost); err != nil {
+		log.Printf("[ERROR] %+v", err)
+		os.Exit(1)
+	}
+}
+
+func run(outputPath, environment, metadataHost string) error {
+	if outputPath == "" {
+		return fmt.Errorf("the `-output` argument must be specified")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	// this uses the same credentials as the Provider (either the Environment Variables or the Azure CLI)
+	builder := &authentication.Builder{
+		SubscriptionID: os.Getenv("ARM_SUBSCRIPTION_ID"),
+		ClientID:       os.Getenv("ARM_CLIENT_ID"),
+		ClientSecret:   os.Getenv("ARM_CLIENT_SECRET"),
+		TenantID:       os.Getenv("ARM_TENANT_ID"),
+		Environment:    environment,
+		MetadataHost:   metadataHost,
+
+		SupportsClientSecretAuth: true,
+		SupportsAzureCliToken:    true,
+	}
+	config, err := builder.Build()
+	if err != nil {
+		return fmt.Errorf("building authentication config: %+v", err)
+	}
+
+	env, err := authentication.AzureEnvironmentByNameFromEndpoint(ctx, metadataHost, environment)
+	if err != nil {
+		return fmt.Errorf("finding environment %q from endpoint %q: %+v", environment, metadataHost, err)
+	}
+
+	oauthConfig, err := config.BuildOAuthConfig(env.ActiveDirectoryEndpoint)
+	if err != nil {
+		return fmt.Errorf("building OAuth Config: %+v", err)
+	}
+
+	s := sender.BuildSender("AzureRM")
+	auth, err := config.GetAuthorizationToken(s, oauthConfig, env.TokenAudience)
+	if err != nil {
+		return fmt.Errorf("obtaining authorization token for resource manager: %+v", err)
+	}
+
+	log.Printf("[DEBUG] Retrieving Locations for %q..", env.Name)
+	locations, err := location.AvailableLocations(ctx, env)
+	if err != nil {
+		return fmt.Errorf("retrieving locations: %+v", err)
+	}
+
+	log.Printf("[DEBUG] Retrieving Resource Providers for Subscription %q..", config.SubscriptionID)
+	providersClient := resources.NewProvidersClientWithBaseURI(env.ResourceManagerEndpoint, config.SubscriptionID)
+	providersClient.Authorizer = auth
+	providersClient.Sender = s
+	providers, err := resourceproviders.AvailableProviders(ctx, &providersClient)
+	if err != nil {
+		return fmt.Errorf("retrieving resource providers: %+v", err)
+	}
+
+	snapshot := features.EnhancedValidationSnapshot{
+		Environment:       environment,
+		Locations:         locations,
+		ResourceProviders: providers,
+	}
+	if err := snapshot.Save(outputPath); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Written %d Locations and %d Resource Providers to %q", len(locations), len(providers), outputPath)
+	return nil
+}
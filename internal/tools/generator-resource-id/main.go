@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"unicode"
@@ -23,6 +24,12 @@ func main() {
 	name := flag.String("name", "", "The name of this Resource Type")
 	id := flag.String("id", "", "An example of this Resource ID")
 	rewrite := flag.Bool("rewrite", false, "Should this Resource ID be parsed insensitively, to workaround an API bug?")
+	parents := stringsFlag{}
+	flag.Var(&parents, "parent", "The name of a Parent Resource Type (defined in the same Service Package) to generate an accessor for, can be specified multiple times")
+	constants := stringsFlag{}
+	flag.Var(&constants, "constant", "The possible values for a Segment in the format `key=Value1|Value2`, can be specified multiple times")
+	validations := stringsFlag{}
+	flag.Var(&validations, "validate", "A regular expression the value for a Segment must match in the format `key=regex`, can be specified multiple times")
 	showHelp := flag.Bool("help", false, "Display this message")
 
 	flag.Parse()
@@ -32,12 +39,41 @@ func main() {
 		return
 	}
 
-	if err := run(*servicePackagePath, *name, *id, *rewrite); err != nil {
+	options := ResourceIdOptions{
+		Parents:     parents,
+		Constants:   constants,
+		Validations: validations,
+	}
+	if err := run(*servicePackagePath, *name, *id, *rewrite, options); err != nil {
 		panic(err)
 	}
 }
 
-func run(servicePackagePath, name, id string, shouldRewrite bool) error {
+// stringsFlag allows a flag to be specified multiple times
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// ResourceIdOptions are the optional behaviours which can be enabled for a Resource ID
+type ResourceIdOptions struct {
+	// Parents is a list of the names of Resource ID's (within this Service Package) which this Resource ID is nested within
+	Parents []string
+
+	// Constants is a list of Segments which can only contain a fixed set of values, in the format `key=Value1|Value2`
+	Constants []string
+
+	// Validations is a list of Segments which must match a regular expression, in the format `key=regex`
+	Validations []string
+}
+
+func run(servicePackagePath, name, id string, shouldRewrite bool, options ResourceIdOptions) error {
 	servicePackage, err := parseServicePackageName(servicePackagePath)
 	if err != nil {
 		return fmt.Errorf("determining Service Package Name for %q: %+v", servicePackagePath, err)
@@ -64,6 +100,43 @@ func run(servicePackagePath, name, id string, shouldRewrite bool) error {
 		return err
 	}
 
+	for _, v := range options.Constants {
+		key, values := splitOption(v)
+		if err := resourceId.SetConstantValues(key, strings.Split(values, "|")); err != nil {
+			return fmt.Errorf("setting the constant values %q: %+v", v, err)
+		}
+	}
+
+	for _, v := range options.Validations {
+		key, expression := splitOption(v)
+		if err := resourceId.SetValidationRegex(key, expression); err != nil {
+			return fmt.Errorf("setting the validation %q: %+v", v, err)
+		}
+	}
+
+	if len(options.Parents) > 0 {
+		definitions, err := findResourceIdDefinitions(servicePackagePath)
+		if err != nil {
+			return fmt.Errorf("finding the Resource ID's defined in %q: %+v", servicePackagePath, err)
+		}
+
+		for _, parentName := range options.Parents {
+			parentId, ok := definitions[parentName]
+			if !ok {
+				return fmt.Errorf("the Parent Resource ID %q was not defined in %q", parentName, servicePackagePath)
+			}
+
+			parent, err := NewResourceID(parentName, *servicePackage, parentId)
+			if err != nil {
+				return fmt.Errorf("parsing the Parent Resource ID %q: %+v", parentName, err)
+			}
+
+			if err := resourceId.AddParent(*parent); err != nil {
+				return err
+			}
+		}
+	}
+
 	generator := ResourceIdGenerator{
 		ResourceId:    *resourceId,
 		ShouldRewrite: shouldRewrite,
@@ -126,6 +199,52 @@ func parseServicePackageName(relativePath string) (*string, error) {
 	return &servicePackageName, nil
 }
 
+var goGenerateNameRegex = regexp.MustCompile(`\s-name=(\S+)`)
+var goGenerateIdRegex = regexp.MustCompile(`\s-id=(\S+)`)
+
+// findResourceIdDefinitions returns a map of the Resource ID names to the example Resource ID
+// for each Resource ID generated within the Service Package
+func findResourceIdDefinitions(servicePackagePath string) (map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(servicePackagePath, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make(map[string]string)
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %q: %+v", file, err)
+		}
+
+		for _, line := range strings.Split(string(contents), "\n") {
+			if !strings.HasPrefix(line, "//go:generate") || !strings.Contains(line, "generator-resource-id") {
+				continue
+			}
+
+			name := goGenerateNameRegex.FindStringSubmatch(line)
+			id := goGenerateIdRegex.FindStringSubmatch(line)
+			if name == nil || id == nil {
+				continue
+			}
+
+			definitions[name[1]] = id[1]
+		}
+	}
+
+	return definitions, nil
+}
+
+// splitOption splits an option in the format `key=value` into the key and the value
+func splitOption(input string) (string, string) {
+	split := strings.SplitN(input, "=", 2)
+	if len(split) == 1 {
+		return split[0], ""
+	}
+
+	return split[0], split[1]
+}
+
 func convertToSnakeCase(input string) string {
 	splitIdxMap := map[int]struct{}{}
 	var lastChar rune
@@ -171,6 +290,20 @@ type ResourceIdSegment struct {
 
 	// SegmentValue is the value for this segment used in the Resource ID
 	SegmentValue string
+
+	// ConstantValues is the list of possible values for this segment, when it can only contain a fixed set of values
+	ConstantValues []string
+
+	// ValidationRegex is a regular expression which the value for this segment must match
+	ValidationRegex string
+}
+
+// ParentResourceId is a Resource ID which this Resource ID is nested within
+type ParentResourceId struct {
+	ResourceId
+
+	// FieldNames are the names of the fields in this Resource ID used as the arguments for the Parent Resource ID
+	FieldNames []string
 }
 
 type ResourceId struct {
@@ -184,16 +317,72 @@ type ResourceId struct {
 	HasResourceGroup  bool
 	HasSubscriptionId bool
 	Segments          []ResourceIdSegment // this has to be a slice not a map since we care about the order
+
+	// IsScoped specifies whether this is an Extension Resource ID in the format `{scope}/providers/...`
+	// where the Scope can be any Resource ID
+	IsScoped bool
+	Parents  []ParentResourceId
+}
+
+// scopePlaceholder is the prefix used for a Resource ID which can be nested within any Resource ID
+const scopePlaceholder = "{scope}"
+
+// exampleScope is the Scope used in the generated tests for a Scoped Resource ID
+const exampleScope = "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/resGroup1"
+
+// exampleScopes are additional Scopes used to confirm that a Scoped Resource ID can be parsed at any Scope
+var exampleScopes = []struct {
+	Name  string
+	Scope string
+}{
+	{
+		Name:  "management group",
+		Scope: "/providers/Microsoft.Management/managementGroups/group1",
+	},
+	{
+		Name:  "subscription",
+		Scope: "/subscriptions/12345678-1234-9876-4563-123456789012",
+	},
+	{
+		Name:  "resource",
+		Scope: "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/resGroup1/providers/Microsoft.Network/virtualNetworks/network1",
+	},
+}
+
+// invalidSegmentValues are values which are used to confirm a segment is validated in the generated tests
+var invalidSegmentValues = []string{
+	"invalid!value",
+	"-",
+	"a",
+	strings.Repeat("a", 261),
 }
 
 func NewResourceID(typeName, servicePackageName, resourceId string) (*ResourceId, error) {
+	segments := make([]ResourceIdSegment, 0)
+
+	// Scoped Resource ID's only contain the Segments after the Scope
+	segmentsPath := resourceId
+	isScoped := strings.HasPrefix(resourceId, scopePlaceholder)
+	if isScoped {
+		if !strings.HasPrefix(resourceId, fmt.Sprintf("%s/providers/", scopePlaceholder)) {
+			return nil, fmt.Errorf("a Scoped Resource ID must be in the format `%s/providers/...`: %q", scopePlaceholder, resourceId)
+		}
+
+		segments = append(segments, ResourceIdSegment{
+			FieldName:    "Scope",
+			ArgumentName: "scope",
+			SegmentValue: exampleScope,
+		})
+		segmentsPath = strings.TrimPrefix(resourceId, scopePlaceholder)
+		resourceId = exampleScope + segmentsPath
+	}
+
 	// split the string, but remove the prefix of `/` since it's an empty segment
-	split := strings.Split(strings.TrimPrefix(resourceId, "/"), "/")
+	split := strings.Split(strings.TrimPrefix(segmentsPath, "/"), "/")
 	if len(split)%2 != 0 {
 		return nil, fmt.Errorf("segments weren't divisible by 2: %q", resourceId)
 	}
 
-	segments := make([]ResourceIdSegment, 0)
 	for i := 0; i < len(split); i += 2 {
 		key := split[i]
 		value := split[i+1]
@@ -284,9 +473,16 @@ func NewResourceID(typeName, servicePackageName, resourceId string) (*ResourceId
 
 	// finally build up the format string based on this information
 	fmtString := resourceId
+	if isScoped {
+		fmtString = strings.Replace(fmtString, exampleScope, "%s", 1)
+	}
 	hasResourceGroup := false
 	hasSubscriptionId := false
 	for _, segment := range segments {
+		if segment.FieldName == "Scope" && isScoped {
+			continue
+		}
+
 		if strings.EqualFold(segment.SegmentKey, "subscriptions") {
 			hasSubscriptionId = true
 		}
@@ -312,9 +508,142 @@ func NewResourceID(typeName, servicePackageName, resourceId string) (*ResourceId
 		ServicePackageName: servicePackageName,
 		TypeName:           typeName,
 		TestPackageSuffix:  packageSuffix,
+		IsScoped:           isScoped,
 	}, nil
 }
 
+// SetConstantValues limits the segment with the specified key to a fixed set of values
+func (id *ResourceId) SetConstantValues(key string, values []string) error {
+	found := false
+	for i, segment := range id.Segments {
+		if segment.SegmentKey != key || segment.FieldName == "Scope" {
+			continue
+		}
+
+		if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+			return fmt.Errorf("at least one value must be specified for the segment %q", key)
+		}
+
+		exampleIsValid := false
+		for _, v := range values {
+			if v == segment.SegmentValue {
+				exampleIsValid = true
+				break
+			}
+		}
+		if !exampleIsValid {
+			return fmt.Errorf("the example value %q for the segment %q isn't one of the possible values %q", segment.SegmentValue, key, values)
+		}
+
+		id.Segments[i].ConstantValues = values
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("the segment %q was not found in %q", key, id.IDRaw)
+	}
+
+	return nil
+}
+
+// SetValidationRegex requires the value for the segment with the specified key to match a regular expression
+func (id *ResourceId) SetValidationRegex(key, expression string) error {
+	regex, err := regexp.Compile(expression)
+	if err != nil {
+		return fmt.Errorf("compiling the regular expression %q: %+v", expression, err)
+	}
+
+	found := false
+	for i, segment := range id.Segments {
+		if segment.SegmentKey != key || segment.FieldName == "Scope" {
+			continue
+		}
+
+		if !regex.MatchString(segment.SegmentValue) {
+			return fmt.Errorf("the example value %q for the segment %q doesn't match the regular expression %q", segment.SegmentValue, key, expression)
+		}
+
+		id.Segments[i].ValidationRegex = expression
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("the segment %q was not found in %q", key, id.IDRaw)
+	}
+
+	return nil
+}
+
+// AddParent adds an accessor for a Parent Resource ID, the segments of which must be a prefix of this Resource ID
+func (id *ResourceId) AddParent(parent ResourceId) error {
+	if len(parent.Segments) >= len(id.Segments) || parent.IsScoped != id.IsScoped {
+		return fmt.Errorf("%q is not a Parent Resource ID of %q", parent.IDRaw, id.IDRaw)
+	}
+
+	fieldNames := make([]string, 0)
+	for i, segment := range parent.Segments {
+		if segment.SegmentKey != id.Segments[i].SegmentKey {
+			return fmt.Errorf("%q is not a Parent Resource ID of %q: expected the segment %q but got %q", parent.IDRaw, id.IDRaw, id.Segments[i].SegmentKey, segment.SegmentKey)
+		}
+
+		fieldNames = append(fieldNames, id.Segments[i].FieldName)
+	}
+
+	id.Parents = append(id.Parents, ParentResourceId{
+		ResourceId: parent,
+		FieldNames: fieldNames,
+	})
+	return nil
+}
+
+// idWithSegmentValue returns the example Resource ID with the value for the specified segment replaced
+func (id ResourceId) idWithSegmentValue(fieldName, value string) string {
+	values := make([]interface{}, 0)
+	for _, segment := range id.Segments {
+		if segment.FieldName == fieldName {
+			values = append(values, value)
+			continue
+		}
+
+		values = append(values, segment.SegmentValue)
+	}
+
+	return fmt.Sprintf(id.IDFmt, values...)
+}
+
+// idUpToSegment returns the example Resource ID up until the key (or the value, if includeKey is set) for the specified segment
+func (id ResourceId) idUpToSegment(segment ResourceIdSegment, includeKey bool) string {
+	// for a Scoped Resource ID the segments are searched for after the Scope, since the same keys could be used within it
+	offset := 0
+	if id.IsScoped {
+		if segment.FieldName == "Scope" {
+			return ""
+		}
+		offset = len(exampleScope)
+	}
+
+	searchFor := segment.SegmentKey
+	if includeKey {
+		searchFor = segment.SegmentValue
+	}
+
+	index := strings.Index(id.IDRaw[offset:], searchFor)
+	return id.IDRaw[0 : offset+index]
+}
+
+// invalidValue returns a value which doesn't match the validation regex for this segment, if one can be found
+func (segment ResourceIdSegment) invalidValue() *string {
+	regex := regexp.MustCompile(segment.ValidationRegex)
+	for _, v := range invalidSegmentValues {
+		if !regex.MatchString(v) {
+			value := v
+			return &value
+		}
+	}
+
+	return nil
+}
+
 type ResourceIdGenerator struct {
 	ResourceId
 
@@ -329,7 +658,7 @@ package parse
 
 import (
 	"fmt"
-	"strings"
+	%s"strings"
 
 	"github.com/hashicorp/terraform-provider-azurerm/helpers/azure"
 )
@@ -340,7 +669,18 @@ import (
 %s
 %s
 %s
-`, id.codeForType(), id.codeForConstructor(), id.codeForDescription(), id.codeForFormatter(), id.codeForParser(), id.codeForParserInsensitive())
+%s
+`, id.codeForImports(), id.codeForType(), id.codeForConstructor(), id.codeForDescription(), id.codeForFormatter(), id.codeForParents(), id.codeForParser(), id.codeForParserInsensitive())
+}
+
+func (id ResourceIdGenerator) codeForImports() string {
+	for _, segment := range id.Segments {
+		if segment.ValidationRegex != "" {
+			return "\"regexp\"\n\t"
+		}
+	}
+
+	return ""
 }
 
 func (id ResourceIdGenerator) codeForType() string {
@@ -431,7 +771,70 @@ func (id %[1]sId) ID() string {
 `, id.TypeName, id.IDFmt, formatKeysString)
 }
 
+func (id ResourceIdGenerator) codeForParents() string {
+	accessors := make([]string, 0)
+	for _, parent := range id.Parents {
+		arguments := make([]string, 0)
+		for _, fieldName := range parent.FieldNames {
+			arguments = append(arguments, fmt.Sprintf("id.%s", fieldName))
+		}
+
+		accessors = append(accessors, fmt.Sprintf(`
+// Parent%[2]sID returns the ID of the %[2]s this %[1]s is nested within
+func (id %[1]sId) Parent%[2]sID() %[2]sId {
+	return New%[2]sID(%[3]s)
+}
+`, id.TypeName, parent.TypeName, strings.Join(arguments, ", ")))
+	}
+
+	return strings.Join(accessors, "")
+}
+
+// codeForSegmentValidation returns the statements validating the value for a segment, once it's been parsed
+func (id ResourceIdGenerator) codeForSegmentValidation(segment ResourceIdSegment, insensitively bool) string {
+	statements := make([]string, 0)
+
+	if len(segment.ConstantValues) > 0 {
+		values := make([]string, 0)
+		for _, v := range segment.ConstantValues {
+			values = append(values, fmt.Sprintf("%q", v))
+		}
+		valuesStr := strings.Join(values, ", ")
+
+		if insensitively {
+			statements = append(statements, fmt.Sprintf(`
+	// normalize the casing of the value for the '%[2]s' segment
+	for _, v := range []string{%[3]s} {
+		if strings.EqualFold(v, resourceId.%[1]s) {
+			resourceId.%[1]s = v
+			break
+		}
+	}`, segment.FieldName, segment.SegmentKey, valuesStr))
+		}
+
+		statements = append(statements, fmt.Sprintf(`
+	switch resourceId.%[1]s {
+	case %[3]s:
+	default:
+		return nil, fmt.Errorf("ID contained an unsupported value %%q for the '%[2]s' element, expected one of %%q", resourceId.%[1]s, []string{%[3]s})
+	}`, segment.FieldName, segment.SegmentKey, valuesStr))
+	}
+
+	if segment.ValidationRegex != "" {
+		statements = append(statements, fmt.Sprintf(`
+	if !regexp.MustCompile(%[3]q).MatchString(resourceId.%[1]s) {
+		return nil, fmt.Errorf("ID contained an invalid value %%q for the '%[2]s' element, expected it to match %%q", resourceId.%[1]s, %[3]q)
+	}`, segment.FieldName, segment.SegmentKey, segment.ValidationRegex))
+	}
+
+	return strings.Join(statements, "\n")
+}
+
 func (id ResourceIdGenerator) codeForParser() string {
+	if id.IsScoped {
+		return id.codeForScopedParser(false)
+	}
+
 	directAssignments := make([]string, 0)
 	if id.HasSubscriptionId {
 		directAssignments = append(directAssignments, "\t\tSubscriptionId: id.SubscriptionID,")
@@ -451,11 +854,17 @@ func (id ResourceIdGenerator) codeForParser() string {
 		return nil, fmt.Errorf("ID was missing the '%[2]s' element")
 	}
 `, segment.FieldName, segment.SegmentKey))
+			if validation := id.codeForSegmentValidation(segment, false); validation != "" {
+				parserStatements = append(parserStatements, validation)
+			}
 			continue
 		}
 
 		fmtString := "\tif resourceId.%[1]s, err = id.PopSegment(\"%[2]s\"); err != nil {\n\t\treturn nil, err\n\t}"
 		parserStatements = append(parserStatements, fmt.Sprintf(fmtString, segment.FieldName, segment.SegmentKey))
+		if validation := id.codeForSegmentValidation(segment, false); validation != "" {
+			parserStatements = append(parserStatements, validation)
+		}
 	}
 	parserStatementsStr := strings.Join(parserStatements, "\n")
 	return fmt.Sprintf(`
@@ -487,6 +896,10 @@ func (id ResourceIdGenerator) codeForParserInsensitive() string {
 		return ""
 	}
 
+	if id.IsScoped {
+		return id.codeForScopedParser(true)
+	}
+
 	directAssignments := make([]string, 0)
 	if id.HasSubscriptionId {
 		directAssignments = append(directAssignments, "\t\tSubscriptionId: id.SubscriptionID,")
@@ -506,6 +919,9 @@ func (id ResourceIdGenerator) codeForParserInsensitive() string {
 		return nil, fmt.Errorf("ID was missing the '%[2]s' element")
 	}
 `, segment.FieldName, segment.SegmentKey))
+			if validation := id.codeForSegmentValidation(segment, true); validation != "" {
+				parserStatements = append(parserStatements, validation)
+			}
 			continue
 		}
 
@@ -525,6 +941,9 @@ func (id ResourceIdGenerator) codeForParserInsensitive() string {
   }
 `
 		parserStatements = append(parserStatements, fmt.Sprintf(fmtString, segment.FieldName, segment.SegmentKey))
+		if validation := id.codeForSegmentValidation(segment, true); validation != "" {
+			parserStatements = append(parserStatements, validation)
+		}
 	}
 	parserStatementsStr := strings.Join(parserStatements, "\n")
 	return fmt.Sprintf(`
@@ -555,6 +974,121 @@ func %[1]sIDInsensitively(input string) (*%[1]sId, error) {
 `, id.TypeName, directAssignmentsStr, parserStatementsStr)
 }
 
+// scopedProvider returns the Resource Provider segment which follows the Scope in a Scoped Resource ID, e.g. `/providers/Microsoft.Authorization/`
+func (id ResourceIdGenerator) scopedProvider() string {
+	split := strings.Split(strings.TrimPrefix(id.IDFmt, "%s/"), "/")
+	return fmt.Sprintf("/%s/%s/", split[0], split[1])
+}
+
+func (id ResourceIdGenerator) codeForScopedParser(insensitively bool) string {
+	parserStatements := make([]string, 0)
+	for _, segment := range id.Segments {
+		if segment.FieldName == "Scope" {
+			continue
+		}
+
+		if insensitively {
+			parserStatements = append(parserStatements, fmt.Sprintf(`
+	// find the correct casing for the '%[2]s' segment
+	%[2]sKey := "%[2]s"
+	for key := range id.Path {
+		if strings.EqualFold(key, %[2]sKey) {
+			%[2]sKey = key
+			break
+		}
+	}
+	if resourceId.%[1]s, err = id.PopSegment(%[2]sKey); err != nil {
+		return nil, err
+	}`, segment.FieldName, segment.SegmentKey))
+		} else {
+			fmtString := "\tif resourceId.%[1]s, err = id.PopSegment(\"%[2]s\"); err != nil {\n\t\treturn nil, err\n\t}"
+			parserStatements = append(parserStatements, fmt.Sprintf(fmtString, segment.FieldName, segment.SegmentKey))
+		}
+
+		if validation := id.codeForSegmentValidation(segment, insensitively); validation != "" {
+			parserStatements = append(parserStatements, validation)
+		}
+	}
+	parserStatementsStr := strings.Join(parserStatements, "\n")
+
+	if insensitively {
+		return fmt.Sprintf(`
+// %[1]sIDInsensitively parses an %[1]s ID into an %[1]sId struct, insensitively
+// This should only be used to parse an ID for rewriting, the %[1]sID
+// method should be used instead for validation etc.
+//
+// Whilst this may seem strange, this enables Terraform have consistent casing
+// which works around issues in Core, whilst handling broken API responses.
+func %[1]sIDInsensitively(input string) (*%[1]sId, error) {
+	// the Scope can be any Resource ID, so this is split on the last occurrence of the Resource Provider
+	index := strings.LastIndex(strings.ToLower(input), strings.ToLower(%[2]q))
+	if index == -1 {
+		return nil, fmt.Errorf("ID was missing the '%[3]s' element")
+	}
+
+	resourceId := %[1]sId{
+		Scope: input[0:index],
+	}
+
+	if resourceId.Scope == "" {
+		return nil, fmt.Errorf("ID was missing the 'scope' element")
+	}
+	if _, err := azure.ParseAzureResourceIDWithoutSubscription(resourceId.Scope); err != nil {
+		return nil, fmt.Errorf("parsing the 'scope' element: %%+v", err)
+	}
+
+	id, err := azure.ParseAzureResourceIDWithoutSubscription(input[index:])
+	if err != nil {
+		return nil, err
+	}
+
+%[4]s
+
+	if err := id.ValidateNoEmptySegments(input); err != nil {
+		return nil, err
+	}
+
+	return &resourceId, nil
+}
+`, id.TypeName, id.scopedProvider(), strings.Trim(id.scopedProvider(), "/"), parserStatementsStr)
+	}
+
+	return fmt.Sprintf(`
+// %[1]sID parses a %[1]s ID into an %[1]sId struct
+func %[1]sID(input string) (*%[1]sId, error) {
+	// the Scope can be any Resource ID, so this is split on the last occurrence of the Resource Provider
+	index := strings.LastIndex(input, %[2]q)
+	if index == -1 {
+		return nil, fmt.Errorf("ID was missing the '%[3]s' element")
+	}
+
+	resourceId := %[1]sId{
+		Scope: input[0:index],
+	}
+
+	if resourceId.Scope == "" {
+		return nil, fmt.Errorf("ID was missing the 'scope' element")
+	}
+	if _, err := azure.ParseAzureResourceIDWithoutSubscription(resourceId.Scope); err != nil {
+		return nil, fmt.Errorf("parsing the 'scope' element: %%+v", err)
+	}
+
+	id, err := azure.ParseAzureResourceIDWithoutSubscription(input[index:])
+	if err != nil {
+		return nil, err
+	}
+
+%[4]s
+
+	if err := id.ValidateNoEmptySegments(input); err != nil {
+		return nil, err
+	}
+
+	return &resourceId, nil
+}
+`, id.TypeName, id.scopedProvider(), strings.Trim(id.scopedProvider(), "/"), parserStatementsStr)
+}
+
 func (id ResourceIdGenerator) TestCode() string {
 	importLine := ""
 	if id.TestPackageSuffix != "" {
@@ -576,7 +1110,42 @@ import (
 %s
 %s
 %s
-`, id.TestPackageSuffix, importLine, id.testCodeForFormatter(), id.testCodeForParser(), id.testCodeForParserInsensitive())
+%s
+`, id.TestPackageSuffix, importLine, id.testCodeForFormatter(), id.testCodeForParents(), id.testCodeForParser(), id.testCodeForParserInsensitive())
+}
+
+func (id ResourceIdGenerator) testCodeForParents() string {
+	arguments := make([]string, 0)
+	for _, segment := range id.Segments {
+		arguments = append(arguments, fmt.Sprintf("%q", segment.SegmentValue))
+	}
+	argumentsStr := strings.Join(arguments, ", ")
+
+	packagePrefix := ""
+	if id.TestPackageSuffix != "" {
+		packagePrefix = "parse."
+	}
+
+	tests := make([]string, 0)
+	for _, parent := range id.Parents {
+		values := make([]interface{}, 0)
+		for i := range parent.Segments {
+			values = append(values, id.Segments[i].SegmentValue)
+		}
+		expected := fmt.Sprintf(parent.IDFmt, values...)
+
+		tests = append(tests, fmt.Sprintf(`
+func Test%[1]sIDParent%[2]sID(t *testing.T) {
+	actual := %[3]sNew%[1]sID(%[4]s).Parent%[2]sID().ID()
+	expected := %[5]q
+	if actual != expected {
+		t.Fatalf("Expected %%q but got %%q", expected, actual)
+	}
+}
+`, id.TypeName, parent.TypeName, packagePrefix, argumentsStr, expected))
+	}
+
+	return strings.Join(tests, "")
 }
 
 func (id ResourceIdGenerator) testCodeForFormatter() string {
@@ -629,15 +1198,16 @@ func (id ResourceIdGenerator) testCodeForParser() string {
 			Input: %q,
 			Error: true,
 		},`
-		// missing the key
-		resourceIdToThisPointIndex := strings.Index(id.IDRaw, segment.SegmentKey)
-		resourceIdToThisPoint := id.IDRaw[0:resourceIdToThisPointIndex]
-		testCases = append(testCases, fmt.Sprintf(testCaseFmt, segment.FieldName, resourceIdToThisPoint))
-
-		// missing the value
-		resourceIdToThisPointIndex = strings.Index(id.IDRaw, segment.SegmentValue)
-		resourceIdToThisPoint = id.IDRaw[0:resourceIdToThisPointIndex]
-		testCases = append(testCases, fmt.Sprintf(testCaseFmt, fmt.Sprintf("value for %s", segment.FieldName), resourceIdToThisPoint))
+		if id.IsScoped && segment.FieldName == "Scope" {
+			// the ID without the Scope
+			testCases = append(testCases, fmt.Sprintf(testCaseFmt, segment.FieldName, strings.TrimPrefix(id.IDRaw, segment.SegmentValue)))
+		} else {
+			// missing the key
+			testCases = append(testCases, fmt.Sprintf(testCaseFmt, segment.FieldName, id.idUpToSegment(segment, false)))
+
+			// missing the value
+			testCases = append(testCases, fmt.Sprintf(testCaseFmt, fmt.Sprintf("value for %s", segment.FieldName), id.idUpToSegment(segment, true)))
+		}
 
 		assignmentsFmt := "\t\tif actual.%[1]s != v.Expected.%[1]s {\n\t\t\tt.Fatalf(\"Expected %%q but got %%q for %[1]s\", v.Expected.%[1]s, actual.%[1]s)\n\t\t}"
 		assignmentChecks = append(assignmentChecks, fmt.Sprintf(assignmentsFmt, segment.FieldName))
@@ -662,6 +1232,9 @@ func (id ResourceIdGenerator) testCodeForParser() string {
 		},
 `, id.IDRaw, typeName, strings.Join(expectAssignments, "\n")))
 
+	// add the test cases for any Scopes, Constants and Validations
+	testCases = append(testCases, id.testCodeForAdditionalTestCases()...)
+
 	// add an intentionally failing upper-cased test case
 	testCases = append(testCases, fmt.Sprintf(`
 		{
@@ -758,15 +1331,16 @@ func (id ResourceIdGenerator) testCodeForParserInsensitive() string {
 			Input: %q,
 			Error: true,
 		},`
-		// missing the key
-		resourceIdToThisPointIndex := strings.Index(id.IDRaw, segment.SegmentKey)
-		resourceIdToThisPoint := id.IDRaw[0:resourceIdToThisPointIndex]
-		testCases = append(testCases, fmt.Sprintf(testCaseFmt, segment.FieldName, resourceIdToThisPoint))
-
-		// missing the value
-		resourceIdToThisPointIndex = strings.Index(id.IDRaw, segment.SegmentValue)
-		resourceIdToThisPoint = id.IDRaw[0:resourceIdToThisPointIndex]
-		testCases = append(testCases, fmt.Sprintf(testCaseFmt, fmt.Sprintf("value for %s", segment.FieldName), resourceIdToThisPoint))
+		if id.IsScoped && segment.FieldName == "Scope" {
+			// the ID without the Scope
+			testCases = append(testCases, fmt.Sprintf(testCaseFmt, segment.FieldName, strings.TrimPrefix(id.IDRaw, segment.SegmentValue)))
+		} else {
+			// missing the key
+			testCases = append(testCases, fmt.Sprintf(testCaseFmt, segment.FieldName, id.idUpToSegment(segment, false)))
+
+			// missing the value
+			testCases = append(testCases, fmt.Sprintf(testCaseFmt, fmt.Sprintf("value for %s", segment.FieldName), id.idUpToSegment(segment, true)))
+		}
 
 		assignmentsFmt := "\t\tif actual.%[1]s != v.Expected.%[1]s {\n\t\t\tt.Fatalf(\"Expected %%q but got %%q for %[1]s\", v.Expected.%[1]s, actual.%[1]s)\n\t\t}"
 		assignmentChecks = append(assignmentChecks, fmt.Sprintf(assignmentsFmt, segment.FieldName))
@@ -790,8 +1364,8 @@ func (id ResourceIdGenerator) testCodeForParserInsensitive() string {
 	testCaseWithTransformation := func(testCaseName string, transform func(in string) string) string {
 		resourceIdWithTransform := id.IDRaw
 		for _, segment := range id.Segments {
-			// we're not as concerned with these two for now
-			if segment.FieldName == "SubscriptionId" || segment.FieldName == "ResourceGroup" {
+			// we're not as concerned with these two for now, and the Scope has no key
+			if segment.FieldName == "SubscriptionId" || segment.FieldName == "ResourceGroup" || segment.FieldName == "Scope" {
 				continue
 			}
 
@@ -813,6 +1387,9 @@ func (id ResourceIdGenerator) testCodeForParserInsensitive() string {
 		},`, resourceIdWithTransform, typeName, strings.Join(expectAssignments, "\n"), testCaseName)
 	}
 
+	// add the test cases for any Scopes, Constants and Validations
+	testCases = append(testCases, id.testCodeForAdditionalTestCases()...)
+
 	testCases = append(testCases, testCaseWithTransformation("lower-cased segment names", strings.ToLower))
 	testCases = append(testCases, testCaseWithTransformation("upper-cased segment names", strings.ToUpper))
 	testCases = append(testCases, testCaseWithTransformation("mixed-cased segment names", func(in string) string {
@@ -893,6 +1470,118 @@ func Test%[1]sIDInsensitively(t *testing.T) {
 `, id.TypeName, testCasesStr, assignmentCheckStr)
 }
 
+// additionalTestCase is a test case for the optional behaviours of a Resource ID, such as Scopes, Constants and Validations
+type additionalTestCase struct {
+	Description string
+	Input       string
+	Valid       bool
+
+	// Values are the expected values for each field, when this test case is Valid
+	Values map[string]string
+}
+
+func (id ResourceIdGenerator) additionalTestCases() []additionalTestCase {
+	testCase := func(description, fieldName, value string, valid bool) additionalTestCase {
+		values := make(map[string]string)
+		for _, segment := range id.Segments {
+			values[segment.FieldName] = segment.SegmentValue
+		}
+		values[fieldName] = value
+
+		return additionalTestCase{
+			Description: description,
+			Input:       id.idWithSegmentValue(fieldName, value),
+			Valid:       valid,
+			Values:      values,
+		}
+	}
+
+	testCases := make([]additionalTestCase, 0)
+	for _, segment := range id.Segments {
+		if id.IsScoped && segment.FieldName == "Scope" {
+			for _, v := range exampleScopes {
+				testCases = append(testCases, testCase(fmt.Sprintf("valid at a %s scope", v.Name), segment.FieldName, v.Scope, true))
+			}
+			continue
+		}
+
+		if len(segment.ConstantValues) > 0 {
+			for _, v := range segment.ConstantValues {
+				if v == segment.SegmentValue {
+					continue
+				}
+
+				testCases = append(testCases, testCase(fmt.Sprintf("valid with %q for %s", v, segment.FieldName), segment.FieldName, v, true))
+			}
+
+			unsupportedValue := "unsupported"
+			for _, v := range segment.ConstantValues {
+				if v == unsupportedValue {
+					unsupportedValue = "unsupported-value"
+				}
+			}
+			testCases = append(testCases, testCase(fmt.Sprintf("unsupported value for %s", segment.FieldName), segment.FieldName, unsupportedValue, false))
+		}
+
+		if segment.ValidationRegex != "" {
+			if invalidValue := segment.invalidValue(); invalidValue != nil {
+				testCases = append(testCases, testCase(fmt.Sprintf("invalid value for %s", segment.FieldName), segment.FieldName, *invalidValue, false))
+			}
+		}
+	}
+
+	return testCases
+}
+
+func (id ResourceIdGenerator) testCodeForAdditionalTestCases() []string {
+	typeName := fmt.Sprintf("%sId", id.TypeName)
+	if id.TestPackageSuffix != "" {
+		typeName = fmt.Sprintf("parse.%s", typeName)
+	}
+
+	output := make([]string, 0)
+	for _, testCase := range id.additionalTestCases() {
+		if !testCase.Valid {
+			output = append(output, fmt.Sprintf(`
+		{
+			// %s
+			Input: %q,
+			Error: true,
+		},`, testCase.Description, testCase.Input))
+			continue
+		}
+
+		expectAssignments := make([]string, 0)
+		for _, segment := range id.Segments {
+			expectAssignments = append(expectAssignments, fmt.Sprintf("\t\t\t\t%s:\t%q,", segment.FieldName, testCase.Values[segment.FieldName]))
+		}
+		output = append(output, fmt.Sprintf(`
+		{
+			// %[1]s
+			Input: %[2]q,
+			Expected: &%[3]s{
+%[4]s
+			},
+		},`, testCase.Description, testCase.Input, typeName, strings.Join(expectAssignments, "\n")))
+	}
+
+	return output
+}
+
+func (id ResourceIdGenerator) validatorTestCodeForAdditionalTestCases() []string {
+	output := make([]string, 0)
+	for _, testCase := range id.additionalTestCases() {
+		output = append(output, fmt.Sprintf(`
+		{
+			// %s
+			Input: %q,
+			Valid: %t,
+		},`, testCase.Description, testCase.Input, testCase.Valid))
+	}
+
+	return output
+}
+
 func (id ResourceIdGenerator) ValidatorCode() string {
 	return fmt.Sprintf(`package validate
 
@@ -936,15 +1625,16 @@ func (id ResourceIdGenerator) ValidatorTestCode() string {
 			Input: %q,
 			Valid: false,
 		},`
-		// missing the key
-		resourceIdToThisPointIndex := strings.Index(id.IDRaw, segment.SegmentKey)
-		resourceIdToThisPoint := id.IDRaw[0:resourceIdToThisPointIndex]
-		testCases = append(testCases, fmt.Sprintf(testCaseFmt, segment.FieldName, resourceIdToThisPoint))
-
-		// missing the value
-		resourceIdToThisPointIndex = strings.Index(id.IDRaw, segment.SegmentValue)
-		resourceIdToThisPoint = id.IDRaw[0:resourceIdToThisPointIndex]
-		testCases = append(testCases, fmt.Sprintf(testCaseFmt, fmt.Sprintf("value for %s", segment.FieldName), resourceIdToThisPoint))
+		if id.IsScoped && segment.FieldName == "Scope" {
+			// the ID without the Scope
+			testCases = append(testCases, fmt.Sprintf(testCaseFmt, segment.FieldName, strings.TrimPrefix(id.IDRaw, segment.SegmentValue)))
+		} else {
+			// missing the key
+			testCases = append(testCases, fmt.Sprintf(testCaseFmt, segment.FieldName, id.idUpToSegment(segment, false)))
+
+			// missing the value
+			testCases = append(testCases, fmt.Sprintf(testCaseFmt, fmt.Sprintf("value for %s", segment.FieldName), id.idUpToSegment(segment, true)))
+		}
 	}
 
 	// add a successful test case
@@ -956,6 +1646,9 @@ func (id ResourceIdGenerator) ValidatorTestCode() string {
 		},
 `, id.IDRaw))
 
+	// add the test cases for any Scopes, Constants and Validations
+	testCases = append(testCases, id.validatorTestCodeForAdditionalTestCases()...)
+
 	// add an intentionally failing upper-cased test case
 	testCases = append(testCases, fmt.Sprintf(`
 		{
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"go/format"
+	"reflect"
 	"testing"
 )
 
@@ -54,3 +56,250 @@ func Test(t *testing.T) {
 		}
 	}
 }
+
+func TestNewResourceIDScoped(t *testing.T) {
+	actual, err := NewResourceID("Lock", "resource", "{scope}/providers/Microsoft.Authorization/locks/lock1")
+	if err != nil {
+		t.Fatalf("parsing: %+v", err)
+	}
+
+	if !actual.IsScoped {
+		t.Fatalf("expected the Resource ID to be Scoped")
+	}
+	if actual.HasSubscriptionId || actual.HasResourceGroup {
+		t.Fatalf("expected the Scope not to be parsed for a Subscription ID or Resource Group")
+	}
+
+	expectedFmt := "%s/providers/Microsoft.Authorization/locks/%s"
+	if actual.IDFmt != expectedFmt {
+		t.Fatalf("expected the format %q but got %q", expectedFmt, actual.IDFmt)
+	}
+
+	fields := make([]string, 0)
+	for _, segment := range actual.Segments {
+		fields = append(fields, segment.FieldName)
+	}
+	expectedFields := []string{"Scope", "Name"}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("expected the fields %+v but got %+v", expectedFields, fields)
+	}
+
+	generator := ResourceIdGenerator{
+		ResourceId:    *actual,
+		ShouldRewrite: true,
+	}
+	assertValidGoCode(t, generator)
+
+	if _, err := NewResourceID("Lock", "resource", "{scope}/locks/lock1"); err == nil {
+		t.Fatalf("expected an error for a Scoped Resource ID without a Resource Provider")
+	}
+}
+
+func TestSetConstantValues(t *testing.T) {
+	testData := []struct {
+		Name   string
+		Key    string
+		Values []string
+		Error  bool
+	}{
+		{
+			Name:   "Valid",
+			Key:    "pricings",
+			Values: []string{"VirtualMachines", "AppServices"},
+		},
+		{
+			Name:   "Example Value Not Possible",
+			Key:    "pricings",
+			Values: []string{"AppServices"},
+			Error:  true,
+		},
+		{
+			Name:   "No Values",
+			Key:    "pricings",
+			Values: []string{""},
+			Error:  true,
+		},
+		{
+			Name:   "Unknown Segment",
+			Key:    "tiers",
+			Values: []string{"VirtualMachines"},
+			Error:  true,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		id, err := NewResourceID("Pricing", "security", "/subscriptions/12345678-1234-9876-4563-123456789012/providers/Microsoft.Security/pricings/VirtualMachines")
+		if err != nil {
+			t.Fatalf("parsing: %+v", err)
+		}
+
+		err = id.SetConstantValues(v.Key, v.Values)
+		if err != nil {
+			if v.Error {
+				continue
+			}
+
+			t.Fatalf("expected no error but got: %+v", err)
+		}
+		if v.Error {
+			t.Fatalf("expected an error but didn't get one")
+		}
+
+		if !reflect.DeepEqual(id.Segments[1].ConstantValues, v.Values) {
+			t.Fatalf("expected the values %+v but got %+v", v.Values, id.Segments[1].ConstantValues)
+		}
+
+		generator := ResourceIdGenerator{
+			ResourceId:    *id,
+			ShouldRewrite: true,
+		}
+		assertValidGoCode(t, generator)
+	}
+}
+
+func TestSetValidationRegex(t *testing.T) {
+	testData := []struct {
+		Name       string
+		Key        string
+		Expression string
+		Error      bool
+	}{
+		{
+			Name:       "Valid",
+			Key:        "servers",
+			Expression: `^[a-z0-9-]{3,63}$`,
+		},
+		{
+			Name:       "Example Value Doesn't Match",
+			Key:        "servers",
+			Expression: `^[A-Z]+$`,
+			Error:      true,
+		},
+		{
+			Name:       "Invalid Expression",
+			Key:        "servers",
+			Expression: `^[a-z$`,
+			Error:      true,
+		},
+		{
+			Name:       "Unknown Segment",
+			Key:        "databases",
+			Expression: `^[a-z0-9-]{3,63}$`,
+			Error:      true,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		id, err := NewResourceID("Server", "mssql", "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/group1/providers/Microsoft.Sql/servers/server1")
+		if err != nil {
+			t.Fatalf("parsing: %+v", err)
+		}
+
+		err = id.SetValidationRegex(v.Key, v.Expression)
+		if err != nil {
+			if v.Error {
+				continue
+			}
+
+			t.Fatalf("expected no error but got: %+v", err)
+		}
+		if v.Error {
+			t.Fatalf("expected an error but didn't get one")
+		}
+
+		generator := ResourceIdGenerator{
+			ResourceId: *id,
+		}
+		if generator.additionalTestCases()[0].Valid {
+			t.Fatalf("expected a test case for an invalid value to be generated")
+		}
+		assertValidGoCode(t, generator)
+	}
+}
+
+func TestAddParent(t *testing.T) {
+	testData := []struct {
+		Name               string
+		ParentName         string
+		ParentId           string
+		Error              bool
+		ExpectedFieldNames []string
+	}{
+		{
+			Name:               "Parent",
+			ParentName:         "Server",
+			ParentId:           "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/group1/providers/Microsoft.Sql/servers/server1",
+			ExpectedFieldNames: []string{"SubscriptionId", "ResourceGroup", "ServerName"},
+		},
+		{
+			Name:       "Sibling",
+			ParentName: "ElasticPool",
+			ParentId:   "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/group1/providers/Microsoft.Sql/servers/server1/elasticPools/pool1",
+			Error:      true,
+		},
+		{
+			Name:       "Itself",
+			ParentName: "Database",
+			ParentId:   "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/group1/providers/Microsoft.Sql/servers/server1/databases/database1",
+			Error:      true,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		id, err := NewResourceID("Database", "mssql", "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/group1/providers/Microsoft.Sql/servers/server1/databases/database1")
+		if err != nil {
+			t.Fatalf("parsing: %+v", err)
+		}
+		parent, err := NewResourceID(v.ParentName, "mssql", v.ParentId)
+		if err != nil {
+			t.Fatalf("parsing the parent: %+v", err)
+		}
+
+		err = id.AddParent(*parent)
+		if err != nil {
+			if v.Error {
+				continue
+			}
+
+			t.Fatalf("expected no error but got: %+v", err)
+		}
+		if v.Error {
+			t.Fatalf("expected an error but didn't get one")
+		}
+
+		if !reflect.DeepEqual(id.Parents[0].FieldNames, v.ExpectedFieldNames) {
+			t.Fatalf("expected the field names %+v but got %+v", v.ExpectedFieldNames, id.Parents[0].FieldNames)
+		}
+
+		generator := ResourceIdGenerator{
+			ResourceId: *id,
+		}
+		assertValidGoCode(t, generator)
+	}
+}
+
+func TestSplitOption(t *testing.T) {
+	key, value := splitOption("servers=^[a-z]{1,3}=$")
+	if key != "servers" || value != "^[a-z]{1,3}=$" {
+		t.Fatalf("expected `servers` and `^[a-z]{1,3}=$` but got %q and %q", key, value)
+	}
+}
+
+func assertValidGoCode(t *testing.T, generator ResourceIdGenerator) {
+	for name, code := range map[string]string{
+		"Parser":          generator.Code(),
+		"Parser Tests":    generator.TestCode(),
+		"Validator":       generator.ValidatorCode(),
+		"Validator Tests": generator.ValidatorTestCode(),
+	} {
+		if _, err := format.Source([]byte(code)); err != nil {
+			t.Fatalf("the generated %s wasn't valid Go: %+v", name, err)
+		}
+	}
+}
@@ -0,0 +1,477 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/provider"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/resourceproviders"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+)
+
+// NOTE: since we're using `go run` for these tools all of the code needs to live within the main.go
+
+func main() {
+	filePath := flag.String("path", "", "The relative path to the root directory")
+	outputPath := flag.String("output", "", "The path to the file which the Schema should be written to, defaults to stdout")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	if err := run(*filePath, *outputPath); err != nil {
+		log.Printf("[ERROR] %+v", err)
+		os.Exit(1)
+	}
+}
+
+func run(rootDirectory, outputPath string) error {
+	if rootDirectory == "" {
+		return fmt.Errorf("the `-path` argument must be specified")
+	}
+
+	services, err := loadServices(rootDirectory)
+	if err != nil {
+		return fmt.Errorf("loading the Services: %+v", err)
+	}
+
+	azureProvider := provider.AzureProvider()
+	document := schemaDocument{
+		DataSources: map[string]resourceDefinition{},
+		Resources:   map[string]resourceDefinition{},
+	}
+
+	for _, service := range services {
+		for _, name := range service.dataSources {
+			resource, ok := azureProvider.DataSourcesMap[name]
+			if !ok {
+				return fmt.Errorf("the Data Source %q is registered in the Service %q but isn't exposed by the Provider", name, service.name)
+			}
+
+			document.DataSources[name] = newResourceDefinition(name, resource, service)
+		}
+
+		for _, name := range service.resources {
+			resource, ok := azureProvider.ResourcesMap[name]
+			if !ok {
+				return fmt.Errorf("the Resource %q is registered in the Service %q but isn't exposed by the Provider", name, service.name)
+			}
+
+			document.Resources[name] = newResourceDefinition(name, resource, service)
+		}
+	}
+
+	output, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serializing the Schema: %+v", err)
+	}
+
+	if outputPath == "" {
+		fmt.Println(string(output))
+		return nil
+	}
+
+	if err := ioutil.WriteFile(outputPath, output, 0644); err != nil {
+		return fmt.Errorf("writing the Schema to %q: %+v", outputPath, err)
+	}
+
+	return nil
+}
+
+type schemaDocument struct {
+	DataSources map[string]resourceDefinition `json:"dataSources"`
+	Resources   map[string]resourceDefinition `json:"resources"`
+}
+
+type resourceDefinition struct {
+	// ServiceName is the name of the Service Registration which this is registered within
+	ServiceName string `json:"serviceName"`
+
+	// ServicePackage is the name of the Service Package which this is defined within e.g. `containers`
+	ServicePackage string `json:"servicePackage"`
+
+	// Typed is whether this is implemented using the Typed SDK, rather than the Plugin SDK directly
+	Typed bool `json:"typed"`
+
+	// WebsiteCategories are the categories which this is documented within
+	WebsiteCategories []string `json:"websiteCategories"`
+
+	// ResourceId is the Resource ID (from the generated `parse` package) for this, when it can be determined
+	ResourceId *resourceIdDefinition `json:"resourceId,omitempty"`
+
+	// ResourceProviders are the Resource Providers (from `resourceproviders.Required()`) which this depends on
+	ResourceProviders []string `json:"resourceProviders"`
+
+	// ForceNewFields are the paths to the fields which require this is recreated when changed
+	ForceNewFields []string `json:"forceNewFields"`
+
+	// Timeouts are the default timeouts for each operation supported by this
+	Timeouts map[string]string `json:"timeouts"`
+
+	// DeprecationMessage is the message shown when this is deprecated
+	DeprecationMessage string `json:"deprecationMessage,omitempty"`
+
+	// DeprecatedFields are the paths to the deprecated fields, with the associated deprecation message
+	DeprecatedFields map[string]string `json:"deprecatedFields"`
+}
+
+type resourceIdDefinition struct {
+	// Name is the name of the Resource ID within the `parse` package e.g. `Cluster`
+	Name string `json:"name"`
+
+	// Format is the format of the Resource ID e.g. `/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}`
+	Format string `json:"format"`
+
+	// Example is an example of the Resource ID
+	Example string `json:"example"`
+
+	// MatchedBy is how the Resource ID was matched to the Data Source/Resource, either `name` for an exact match
+	// or `suffix` when the name of the Resource ID is a suffix of the name - which is a heuristic, so can be wrong
+	MatchedBy string `json:"matchedBy"`
+}
+
+const (
+	resourceIdMatchedByName   = "name"
+	resourceIdMatchedBySuffix = "suffix"
+)
+
+type serviceDefinition struct {
+	name              string
+	packageName       string
+	websiteCategories []string
+	typed             map[string]struct{}
+	dataSources       []string
+	resources         []string
+	resourceIds       []resourceIdDefinition
+}
+
+func loadServices(rootDirectory string) ([]*serviceDefinition, error) {
+	services := make(map[string]*serviceDefinition)
+	serviceFor := func(registration interface{}, name string, websiteCategories []string) (*serviceDefinition, error) {
+		// Service Registrations are reused across Typed and Untyped Services
+		if existing, ok := services[name]; ok {
+			return existing, nil
+		}
+
+		info := reflect.TypeOf(registration)
+		if info.Kind() == reflect.Ptr {
+			info = info.Elem()
+		}
+		packageSegments := strings.Split(info.PkgPath(), "/")
+		packageName := packageSegments[len(packageSegments)-1]
+
+		resourceIds, err := loadResourceIds(filepath.Join(rootDirectory, "internal", "services", packageName))
+		if err != nil {
+			return nil, fmt.Errorf("loading the Resource IDs for the Service %q: %+v", name, err)
+		}
+
+		service := &serviceDefinition{
+			name:              name,
+			packageName:       packageName,
+			websiteCategories: websiteCategories,
+			typed:             map[string]struct{}{},
+			dataSources:       make([]string, 0),
+			resources:         make([]string, 0),
+			resourceIds:       resourceIds,
+		}
+		services[name] = service
+		return service, nil
+	}
+
+	for _, registration := range provider.SupportedTypedServices() {
+		service, err := serviceFor(registration, registration.Name(), registration.WebsiteCategories())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ds := range registration.DataSources() {
+			service.dataSources = append(service.dataSources, ds.ResourceType())
+			service.typed[ds.ResourceType()] = struct{}{}
+		}
+
+		if v, ok := registration.(sdk.TypedServiceRegistrationWithListDataSources); ok {
+			for _, ds := range v.ListDataSources() {
+				service.dataSources = append(service.dataSources, ds.ResourceType())
+				service.typed[ds.ResourceType()] = struct{}{}
+			}
+		}
+
+		for _, r := range registration.Resources() {
+			service.resources = append(service.resources, r.ResourceType())
+			service.typed[r.ResourceType()] = struct{}{}
+		}
+	}
+
+	for _, registration := range provider.SupportedUntypedServices() {
+		service, err := serviceFor(registration, registration.Name(), registration.WebsiteCategories())
+		if err != nil {
+			return nil, err
+		}
+
+		for name := range registration.SupportedDataSources() {
+			service.dataSources = append(service.dataSources, name)
+		}
+
+		for name := range registration.SupportedResources() {
+			service.resources = append(service.resources, name)
+		}
+	}
+
+	names := make([]string, 0)
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	output := make([]*serviceDefinition, 0)
+	for _, name := range names {
+		output = append(output, services[name])
+	}
+
+	return output, nil
+}
+
+var (
+	generateRegex     = regexp.MustCompile(`^//go:generate .*generator-resource-id/main.go`)
+	generateNameRegex = regexp.MustCompile(`\s-name=(\S+)`)
+	generateIdRegex   = regexp.MustCompile(`\s-id=(\S+)`)
+)
+
+// loadResourceIds loads the Resource IDs generated for the Service Package, from the `go:generate` directives
+// for the Resource ID generator
+func loadResourceIds(servicePackagePath string) ([]resourceIdDefinition, error) {
+	files, err := filepath.Glob(filepath.Join(servicePackagePath, "*.go"))
+	if err != nil {
+		return nil, fmt.Errorf("listing the files within %q: %+v", servicePackagePath, err)
+	}
+
+	output := make([]resourceIdDefinition, 0)
+	for _, file := range files {
+		contents, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %q: %+v", file, err)
+		}
+
+		for _, line := range strings.Split(string(contents), "\n") {
+			line = strings.TrimSpace(line)
+			if !generateRegex.MatchString(line) {
+				continue
+			}
+
+			name := generateNameRegex.FindStringSubmatch(line)
+			id := generateIdRegex.FindStringSubmatch(line)
+			if name == nil || id == nil {
+				continue
+			}
+
+			output = append(output, resourceIdDefinition{
+				Name:    name[1],
+				Format:  resourceIdFormat(id[1]),
+				Example: id[1],
+			})
+		}
+	}
+
+	return output, nil
+}
+
+// resourceIdFormat returns the format of the Resource ID, replacing each of the user specified segments with a placeholder
+func resourceIdFormat(example string) string {
+	// Scoped Resource ID's can be nested within any Resource ID, so only the segments after the Scope are formatted
+	if strings.HasPrefix(example, "{scope}") {
+		return "{scope}" + resourceIdFormat(strings.TrimPrefix(example, "{scope}"))
+	}
+
+	segments := strings.Split(strings.Trim(example, "/"), "/")
+
+	output := make([]string, 0)
+	for i := 0; i < len(segments); i += 2 {
+		key := segments[i]
+		output = append(output, key)
+		if i+1 >= len(segments) {
+			break
+		}
+
+		value := segments[i+1]
+		switch {
+		case strings.EqualFold(key, "providers"):
+			// the Resource Provider is a constant
+			output = append(output, value)
+		case strings.EqualFold(key, "subscriptions"):
+			output = append(output, "{subscriptionId}")
+		case strings.EqualFold(key, "resourceGroups"):
+			output = append(output, "{resourceGroupName}")
+		default:
+			output = append(output, fmt.Sprintf("{%sName}", singular(key)))
+		}
+	}
+
+	return "/" + strings.Join(output, "/")
+}
+
+func singular(input string) string {
+	switch {
+	case strings.HasSuffix(input, "ies"):
+		return strings.TrimSuffix(input, "ies") + "y"
+	case strings.HasSuffix(input, "sses"):
+		return strings.TrimSuffix(input, "es")
+	case strings.HasSuffix(input, "s"):
+		return strings.TrimSuffix(input, "s")
+	}
+
+	return input
+}
+
+// findResourceId returns the Resource ID for the Data Source/Resource, matched by name. Since the names of the
+// Resource IDs are scoped to the Service Package these are commonly a suffix of the name of the Resource (for example
+// `Cluster` for `azurerm_kubernetes_cluster`) - as such an exact match is preferred, falling back to the longest suffix.
+// The Resource ID returned is a copy, recording how this was matched in MatchedBy.
+func findResourceId(resourceName string, resourceIds []resourceIdDefinition) *resourceIdDefinition {
+	normalizedName := strings.ReplaceAll(strings.TrimPrefix(resourceName, "azurerm_"), "_", "")
+
+	var match *resourceIdDefinition
+	for _, v := range resourceIds {
+		normalizedId := strings.ToLower(v.Name)
+		if normalizedId == normalizedName {
+			exact := v
+			exact.MatchedBy = resourceIdMatchedByName
+			return &exact
+		}
+
+		if !strings.HasSuffix(normalizedName, normalizedId) {
+			continue
+		}
+
+		if match == nil || len(v.Name) > len(match.Name) {
+			suffix := v
+			suffix.MatchedBy = resourceIdMatchedBySuffix
+			match = &suffix
+		}
+	}
+
+	return match
+}
+
+// resourceProvidersForId returns the Resource Providers (from `resourceproviders.Required()`) used in the Resource ID
+func resourceProvidersForId(resourceId string) []string {
+	required := resourceproviders.Required()
+	resourceProvider := func(input string) string {
+		for name := range required {
+			if strings.EqualFold(name, input) {
+				return name
+			}
+		}
+
+		return ""
+	}
+
+	output := make([]string, 0)
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(resourceId, "{scope}"), "/"), "/")
+	for i := 0; i+1 < len(segments); i += 2 {
+		if !strings.EqualFold(segments[i], "providers") {
+			continue
+		}
+
+		if name := resourceProvider(segments[i+1]); name != "" && !contains(output, name) {
+			output = append(output, name)
+		}
+	}
+
+	// Subscriptions and Resource Groups are a part of `Microsoft.Resources`
+	if len(output) == 0 {
+		if name := resourceProvider("Microsoft.Resources"); name != "" {
+			output = append(output, name)
+		}
+	}
+
+	return output
+}
+
+func newResourceDefinition(name string, resource *schema.Resource, service *serviceDefinition) resourceDefinition {
+	_, typed := service.typed[name]
+	definition := resourceDefinition{
+		ServiceName:        service.name,
+		ServicePackage:     service.packageName,
+		Typed:              typed,
+		WebsiteCategories:  service.websiteCategories,
+		ResourceProviders:  make([]string, 0),
+		ForceNewFields:     make([]string, 0),
+		Timeouts:           map[string]string{},
+		DeprecationMessage: resource.DeprecationMessage,
+		DeprecatedFields:   map[string]string{},
+	}
+
+	if resourceId := findResourceId(name, service.resourceIds); resourceId != nil {
+		definition.ResourceId = resourceId
+		definition.ResourceProviders = resourceProvidersForId(resourceId.Example)
+	}
+
+	walkSchema("", resource.Schema, func(path string, field *schema.Schema) {
+		if field.ForceNew {
+			definition.ForceNewFields = append(definition.ForceNewFields, path)
+		}
+
+		if field.Deprecated != "" {
+			definition.DeprecatedFields[path] = field.Deprecated
+		}
+	})
+	sort.Strings(definition.ForceNewFields)
+
+	if timeouts := resource.Timeouts; timeouts != nil {
+		if timeouts.Create != nil {
+			definition.Timeouts["create"] = timeouts.Create.String()
+		}
+		if timeouts.Read != nil {
+			definition.Timeouts["read"] = timeouts.Read.String()
+		}
+		if timeouts.Update != nil {
+			definition.Timeouts["update"] = timeouts.Update.String()
+		}
+		if timeouts.Delete != nil {
+			definition.Timeouts["delete"] = timeouts.Delete.String()
+		}
+	}
+
+	return definition
+}
+
+// walkSchema calls the specified function for each field within the Schema (including nested blocks), where the
+// path to nested fields is separated by a `.` e.g. `default_node_pool.vm_size`
+func walkSchema(prefix string, input map[string]*schema.Schema, fn func(path string, field *schema.Schema)) {
+	for name, field := range input {
+		path := name
+		if prefix != "" {
+			path = fmt.Sprintf("%s.%s", prefix, name)
+		}
+
+		fn(path, field)
+
+		if elem, ok := field.Elem.(*schema.Resource); ok {
+			walkSchema(path, elem.Schema, fn)
+		}
+	}
+}
+
+func contains(input []string, value string) bool {
+	for _, v := range input {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
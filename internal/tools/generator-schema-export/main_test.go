@@ -0,0 +1,150 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResourceIdFormat(t *testing.T) {
+	testData := []struct {
+		Input    string
+		Expected string
+	}{
+		{
+			Input:    "/subscriptions/12345678-1234-9876-4563-123456789012",
+			Expected: "/subscriptions/{subscriptionId}",
+		},
+		{
+			Input:    "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/group1",
+			Expected: "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}",
+		},
+		{
+			Input:    "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/resGroup1/providers/Microsoft.ContainerService/managedClusters/cluster1/agentPools/pool1",
+			Expected: "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.ContainerService/managedClusters/{managedClusterName}/agentPools/{agentPoolName}",
+		},
+		{
+			Input:    "/subscriptions/12345678-1234-9876-4563-123456789012/providers/Microsoft.Authorization/policyDefinitions/policy1",
+			Expected: "/subscriptions/{subscriptionId}/providers/Microsoft.Authorization/policyDefinitions/{policyDefinitionName}",
+		},
+		{
+			Input:    "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/group1/providers/Microsoft.Network/firewallPolicies/policy1",
+			Expected: "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Network/firewallPolicies/{firewallPolicyName}",
+		},
+		{
+			Input:    "{scope}/providers/Microsoft.Authorization/locks/lock1",
+			Expected: "{scope}/providers/Microsoft.Authorization/locks/{lockName}",
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Input)
+
+		actual := resourceIdFormat(v.Input)
+		if actual != v.Expected {
+			t.Fatalf("expected %q but got %q", v.Expected, actual)
+		}
+	}
+}
+
+func TestFindResourceId(t *testing.T) {
+	resourceIds := []resourceIdDefinition{
+		{
+			Name: "Cluster",
+		},
+		{
+			Name: "NodePool",
+		},
+		{
+			Name: "ContainerRegistryToken",
+		},
+	}
+
+	testData := []struct {
+		Input             string
+		Expected          string
+		ExpectedMatchedBy string
+	}{
+		{
+			Input:             "azurerm_kubernetes_cluster",
+			Expected:          "Cluster",
+			ExpectedMatchedBy: "suffix",
+		},
+		{
+			Input:             "azurerm_kubernetes_cluster_node_pool",
+			Expected:          "NodePool",
+			ExpectedMatchedBy: "suffix",
+		},
+		{
+			Input:             "azurerm_container_registry_token",
+			Expected:          "ContainerRegistryToken",
+			ExpectedMatchedBy: "name",
+		},
+		{
+			Input:    "azurerm_container_group",
+			Expected: "",
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Input)
+
+		actual := ""
+		actualMatchedBy := ""
+		if match := findResourceId(v.Input, resourceIds); match != nil {
+			actual = match.Name
+			actualMatchedBy = match.MatchedBy
+		}
+
+		if actual != v.Expected {
+			t.Fatalf("expected %q but got %q", v.Expected, actual)
+		}
+		if actualMatchedBy != v.ExpectedMatchedBy {
+			t.Fatalf("expected %q to be matched by %q but got %q", v.Input, v.ExpectedMatchedBy, actualMatchedBy)
+		}
+	}
+
+	// the Resource IDs themselves shouldn't be modified when matched
+	for _, v := range resourceIds {
+		if v.MatchedBy != "" {
+			t.Fatalf("expected the Resource ID %q to be unmodified but got %q", v.Name, v.MatchedBy)
+		}
+	}
+}
+
+func TestResourceProvidersForId(t *testing.T) {
+	testData := []struct {
+		Input    string
+		Expected []string
+	}{
+		{
+			Input:    "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/group1",
+			Expected: []string{"Microsoft.Resources"},
+		},
+		{
+			Input:    "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/resGroup1/providers/Microsoft.ContainerService/managedClusters/cluster1",
+			Expected: []string{"Microsoft.ContainerService"},
+		},
+		{
+			// Resource Providers are matched case-insensitively, but output as defined
+			Input:    "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/group1/providers/Microsoft.Insights/components/component1",
+			Expected: []string{"microsoft.insights"},
+		},
+		{
+			Input:    "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/group1/providers/Microsoft.Network/virtualNetworks/network1/providers/Microsoft.Authorization/locks/lock1",
+			Expected: []string{"Microsoft.Network", "Microsoft.Authorization"},
+		},
+		{
+			Input:    "{scope}/providers/Microsoft.Authorization/locks/lock1",
+			Expected: []string{"Microsoft.Authorization"},
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Input)
+
+		actual := resourceProvidersForId(v.Input)
+		if !reflect.DeepEqual(actual, v.Expected) {
+			t.Fatalf("expected %+v but got %+v", v.Expected, actual)
+		}
+	}
+}
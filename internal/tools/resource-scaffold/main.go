@@ -0,0 +1,1112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// NOTE: since we're using `go run` for these tools all of the code needs to live within the main.go
+
+func main() {
+	f := flag.NewFlagSet("resource-scaffold", flag.ExitOnError)
+
+	resourceName := f.String("name", "", "The name of the Resource which should be generated (e.g. azurerm_resource_group)")
+	brandName := f.String("brand-name", "", "The friendly/brand name of this Resource (e.g. Resource Group)")
+	resourceId := f.String("resource-id", "", "An example of the Azure Resource ID used for this Resource")
+	servicePackagePath := f.String("service-package-path", "", "The relative path to the Service Package which this Resource should be generated within")
+	sdkPackage := f.String("sdk-package", "", "The import path of the Azure SDK package used for this Resource")
+	clientName := f.String("client-name", "", "(Optional) The name of the SDK Client within the Service Client, defaults to `{Name}Client`")
+	websitePath := f.String("website-path", "", "The relative path to the website folder")
+
+	_ = f.Parse(os.Args[1:])
+
+	quitWithError := func(message string) {
+		log.Print(message)
+		os.Exit(1)
+	}
+
+	if resourceName == nil || *resourceName == "" {
+		quitWithError("The name of the Resource must be specified via `-name`")
+		return
+	}
+
+	if !strings.HasPrefix(*resourceName, "azurerm_") {
+		quitWithError("The name of the Resource specified via `-name` must be prefixed with `azurerm_`")
+		return
+	}
+
+	if brandName == nil || *brandName == "" {
+		quitWithError("The friendly/brand name of the Resource must be specified via `-brand-name`")
+		return
+	}
+
+	if resourceId == nil || *resourceId == "" {
+		quitWithError("An example of an Azure Resource ID must be specified via `-resource-id`")
+		return
+	}
+
+	if servicePackagePath == nil || *servicePackagePath == "" {
+		quitWithError("The Relative Service Package Path must be specified via `-service-package-path`")
+		return
+	}
+
+	if sdkPackage == nil || *sdkPackage == "" {
+		quitWithError("The import path of the Azure SDK package must be specified via `-sdk-package`")
+		return
+	}
+
+	if websitePath == nil || *websitePath == "" {
+		quitWithError("The Relative Website Path must be specified via `-website-path`")
+		return
+	}
+
+	if err := run(*resourceName, *brandName, *resourceId, *servicePackagePath, *sdkPackage, *clientName, *websitePath); err != nil {
+		quitWithError(err.Error())
+		return
+	}
+}
+
+func run(resourceName, brandName, resourceId, servicePackagePath, sdkPackage, clientName, websitePath string) error {
+	servicePackagePath, err := filepath.Abs(servicePackagePath)
+	if err != nil {
+		return fmt.Errorf("determining the absolute path for %q: %+v", servicePackagePath, err)
+	}
+
+	// the Service Package lives at `./internal/services/{name}` within the root of the repository
+	rootDirectory := filepath.Join(servicePackagePath, "..", "..", "..")
+	servicePackage := filepath.Base(servicePackagePath)
+
+	scaffold, err := newResourceScaffold(resourceName, brandName, resourceId, servicePackage, sdkPackage, clientName)
+	if err != nil {
+		return fmt.Errorf("parsing the Resource ID %q: %+v", resourceId, err)
+	}
+
+	scaffold.serviceClientName, err = findServiceClientName(rootDirectory, servicePackage)
+	if err != nil {
+		return fmt.Errorf("determining the Service Client for %q: %+v", servicePackage, err)
+	}
+
+	registrationFilePath := filepath.Join(servicePackagePath, "registration.go")
+	registration, err := ioutil.ReadFile(registrationFilePath)
+	if err != nil {
+		return fmt.Errorf("reading the Service Registration at %q: %+v", registrationFilePath, err)
+	}
+	scaffold.websiteCategories = findWebsiteCategories(string(registration))
+
+	files := map[string]string{
+		filepath.Join(servicePackagePath, fmt.Sprintf("%s_resource.go", scaffold.fileName)):         scaffold.resourceCode(),
+		filepath.Join(servicePackagePath, fmt.Sprintf("%s_resource_test.go", scaffold.fileName)):    scaffold.testCode(),
+		filepath.Join(websitePath, "docs", "r", fmt.Sprintf("%s.html.markdown", scaffold.fileName)): scaffold.documentation(),
+	}
+
+	// we don't want to overwrite anything which already exists, so check this up front
+	for filePath := range files {
+		if _, err := os.Stat(filePath); err == nil {
+			return fmt.Errorf("the file %q already exists", filePath)
+		}
+	}
+
+	if err := generateResourceId(servicePackagePath, scaffold.idName, resourceId); err != nil {
+		return fmt.Errorf("generating the Resource ID %q: %+v", scaffold.idName, err)
+	}
+
+	for _, filePath := range sortedKeys(files) {
+		if err := writeToFile(filePath, files[filePath]); err != nil {
+			return err
+		}
+	}
+
+	updatedRegistration, registeredTypedService, err := registerResource(string(registration), scaffold.typeName)
+	if err != nil {
+		return fmt.Errorf("registering the Resource in %q: %+v", registrationFilePath, err)
+	}
+	if err := writeToFile(registrationFilePath, updatedRegistration); err != nil {
+		return err
+	}
+
+	// when this is the first Typed Resource within this Service the Service needs to be registered as a Typed Service
+	if registeredTypedService {
+		servicesFilePath := filepath.Join(rootDirectory, "internal", "provider", "services.go")
+		services, err := ioutil.ReadFile(servicesFilePath)
+		if err != nil {
+			return fmt.Errorf("reading %q: %+v", servicesFilePath, err)
+		}
+
+		updatedServices, err := registerTypedService(string(services), servicePackage)
+		if err != nil {
+			return fmt.Errorf("registering the Typed Service in %q: %+v", servicesFilePath, err)
+		}
+		if err := writeToFile(servicesFilePath, updatedServices); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type resourceIdSegment struct {
+	// key is the key for this segment in the Resource ID e.g. `resourceGroups`
+	key string
+
+	// fieldName is the name of the field for this segment within the generated Resource ID struct e.g. `ResourceGroup`
+	fieldName string
+
+	// schemaName is the name of the field for this segment within the Schema e.g. `resource_group_name`
+	schemaName string
+
+	// modelName is the name of the field for this segment within the Model e.g. `ResourceGroupName`
+	modelName string
+}
+
+type resourceScaffold struct {
+	// resourceName is the name of the Resource e.g. `azurerm_resource_group`
+	resourceName string
+
+	// brandName is the marketing brand name used for this Resource (e.g. Resource Group / App Service / Web Apps)
+	brandName string
+
+	// resourceId is an example of the Resource ID used by this Resource
+	resourceId string
+
+	// servicePackage is the name of the Service Package which this Resource is generated within
+	servicePackage string
+
+	// sdkPackage is the import path of the Azure SDK package used by this Resource
+	sdkPackage string
+
+	// clientName is the name of the SDK Client within the Service Client
+	clientName string
+
+	// serviceClientName is the name of the Service Client within `clients.Client`
+	serviceClientName string
+
+	// typeName is the name used as a prefix for the Resource and Model e.g. `ResourceGroup`
+	typeName string
+
+	// idName is the name of the Resource ID within the `parse` package e.g. `ResourceGroup`
+	idName string
+
+	// fileName is the file name (without the suffix) used for this Resource e.g. `resource_group`
+	fileName string
+
+	// segments are the user specified segments from the Resource ID, in order
+	segments []resourceIdSegment
+
+	// hasResourceGroup is whether the Resource ID contains a Resource Group
+	hasResourceGroup bool
+
+	// hasSubscriptionId is whether the Resource ID is scoped to a Subscription
+	hasSubscriptionId bool
+
+	// isTopLevel is whether this Resource is nested directly within the Resource Group, in which case it's
+	// expected to have a Location and Tags
+	isTopLevel bool
+
+	// websiteCategories is the list of categories available for the Service
+	websiteCategories []string
+}
+
+func newResourceScaffold(resourceName, brandName, resourceId, servicePackage, sdkPackage, clientName string) (*resourceScaffold, error) {
+	split := strings.Split(strings.Trim(resourceId, "/"), "/")
+	if len(split) < 2 || len(split)%2 != 0 {
+		return nil, fmt.Errorf("segments weren't divisible by 2")
+	}
+
+	// the name of the Resource ID is the last segment, so that it's parsed into `Name` by the Resource ID generator
+	idName := strings.Title(singular(split[len(split)-2]))
+	if clientName == "" {
+		clientName = fmt.Sprintf("%sClient", idName)
+	}
+
+	scaffold := resourceScaffold{
+		resourceName:   resourceName,
+		brandName:      brandName,
+		resourceId:     resourceId,
+		servicePackage: servicePackage,
+		sdkPackage:     sdkPackage,
+		clientName:     clientName,
+		typeName:       toPascalCase(strings.TrimPrefix(resourceName, "azurerm_")),
+		idName:         idName,
+		fileName:       strings.TrimPrefix(resourceName, "azurerm_"),
+		segments:       make([]resourceIdSegment, 0),
+	}
+
+	resourcesWithinProvider := 0
+	for i := 0; i < len(split); i += 2 {
+		key := split[i]
+
+		switch {
+		case key == "providers":
+			// the Resource Provider is a constant
+			continue
+
+		case key == "subscriptions" && i == 0:
+			scaffold.hasSubscriptionId = true
+			scaffold.segments = append(scaffold.segments, resourceIdSegment{
+				key:       key,
+				fieldName: "SubscriptionId",
+			})
+			continue
+
+		case strings.EqualFold(key, "resourceGroups"):
+			scaffold.hasResourceGroup = true
+			scaffold.segments = append(scaffold.segments, resourceIdSegment{
+				key:        key,
+				fieldName:  "ResourceGroup",
+				schemaName: "resource_group_name",
+				modelName:  "ResourceGroupName",
+			})
+			continue
+		}
+
+		resourcesWithinProvider++
+		name := singular(key)
+		if strings.HasSuffix(key, "s") && strings.EqualFold(name, idName) {
+			scaffold.segments = append(scaffold.segments, resourceIdSegment{
+				key:        key,
+				fieldName:  "Name",
+				schemaName: "name",
+				modelName:  "Name",
+			})
+			continue
+		}
+
+		scaffold.segments = append(scaffold.segments, resourceIdSegment{
+			key:        key,
+			fieldName:  fmt.Sprintf("%sName", strings.Title(name)),
+			schemaName: fmt.Sprintf("%s_name", toSnakeCase(name)),
+			modelName:  fmt.Sprintf("%sName", strings.Title(name)),
+		})
+	}
+
+	scaffold.isTopLevel = scaffold.hasResourceGroup && resourcesWithinProvider == 1
+	return &scaffold, nil
+}
+
+// singular returns the singular form of the Resource ID segment, using the same rules as the Resource ID generator
+func singular(input string) string {
+	switch {
+	case strings.HasSuffix(input, "ies"):
+		return fmt.Sprintf("%sy", strings.TrimSuffix(input, "ies"))
+	case strings.HasSuffix(input, "sses"):
+		return fmt.Sprintf("%sss", strings.TrimSuffix(input, "sses"))
+	case strings.HasSuffix(input, "s"):
+		return strings.TrimSuffix(input, "s")
+	}
+
+	return input
+}
+
+func toPascalCase(input string) string {
+	output := ""
+	for _, v := range strings.Split(input, "_") {
+		output += strings.Title(v)
+	}
+	return output
+}
+
+func toSnakeCase(input string) string {
+	output := make([]rune, 0)
+	for i, char := range input {
+		if unicode.IsUpper(char) {
+			if i > 0 {
+				output = append(output, '_')
+			}
+			char = unicode.ToLower(char)
+		}
+		output = append(output, char)
+	}
+	return string(output)
+}
+
+// arguments returns the segments which are user specified arguments, excluding the Subscription ID
+func (s resourceScaffold) arguments() []resourceIdSegment {
+	output := make([]resourceIdSegment, 0)
+	for _, v := range s.segments {
+		if v.schemaName != "" {
+			output = append(output, v)
+		}
+	}
+
+	// the name is output first, with the parent segments in the order they're defined in the Resource ID
+	sort.SliceStable(output, func(i, j int) bool {
+		return output[i].schemaName == "name" && output[j].schemaName != "name"
+	})
+	return output
+}
+
+// idArguments returns the arguments used to identify this Resource in the SDK e.g. `id.ResourceGroup, id.Name`
+func (s resourceScaffold) idArguments() string {
+	arguments := make([]string, 0)
+	for _, v := range s.segments {
+		if v.fieldName == "SubscriptionId" {
+			continue
+		}
+		arguments = append(arguments, fmt.Sprintf("id.%s", v.fieldName))
+	}
+	return strings.Join(arguments, ", ")
+}
+
+// constructorArguments returns the arguments used to construct the Resource ID from the Model
+func (s resourceScaffold) constructorArguments() string {
+	arguments := make([]string, 0)
+	for _, v := range s.segments {
+		if v.fieldName == "SubscriptionId" {
+			arguments = append(arguments, "subscriptionId")
+			continue
+		}
+		arguments = append(arguments, fmt.Sprintf("model.%s", v.modelName))
+	}
+	return strings.Join(arguments, ", ")
+}
+
+func (s resourceScaffold) sdkAlias() string {
+	segments := strings.Split(s.sdkPackage, "/")
+	return segments[len(segments)-1]
+}
+
+func (s resourceScaffold) resourceCode() string {
+	imports := []string{
+		`"context"`,
+		`"fmt"`,
+		`"time"`,
+		"",
+		fmt.Sprintf("%q", s.sdkPackage),
+		`"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"`,
+		fmt.Sprintf(`"github.com/hashicorp/terraform-provider-azurerm/internal/services/%s/parse"`, s.servicePackage),
+		fmt.Sprintf(`"github.com/hashicorp/terraform-provider-azurerm/internal/services/%s/validate"`, s.servicePackage),
+		`"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"`,
+		`"github.com/hashicorp/terraform-provider-azurerm/internal/tf/validation"`,
+		`"github.com/hashicorp/terraform-provider-azurerm/utils"`,
+	}
+	if s.hasResourceGroup {
+		imports = append(imports, `"github.com/hashicorp/terraform-provider-azurerm/helpers/azure"`)
+	}
+	if s.isTopLevel {
+		imports = append(imports, `"github.com/hashicorp/terraform-provider-azurerm/internal/location"`)
+		imports = append(imports, `"github.com/hashicorp/terraform-provider-azurerm/internal/tags"`)
+	}
+
+	modelFields := make([]string, 0)
+	schemaFields := make([]string, 0)
+	readFields := make([]string, 0)
+	for _, v := range s.arguments() {
+		modelFields = append(modelFields, fmt.Sprintf("\t%s string `tfschema:%q`", v.modelName, v.schemaName))
+		readFields = append(readFields, fmt.Sprintf("\t\t\t\t%s: id.%s,", v.modelName, v.fieldName))
+
+		if v.schemaName == "resource_group_name" {
+			schemaFields = append(schemaFields, "\t\t\"resource_group_name\": azure.SchemaResourceGroupName(),\n")
+			continue
+		}
+
+		schemaFields = append(schemaFields, fmt.Sprintf(`		%q: {
+			Type:         pluginsdk.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+`, v.schemaName))
+	}
+
+	subscriptionId := ""
+	if s.hasSubscriptionId {
+		subscriptionId = "\t\t\tsubscriptionId := metadata.Client.Account.SubscriptionId\n"
+	}
+
+	payload := fmt.Sprintf("\t\t\tparameters := %s.%s{}\n", s.sdkAlias(), s.idName)
+	updateTags := ""
+	if s.isTopLevel {
+		modelFields = append(modelFields, "\tLocation string `tfschema:\"location\"`")
+		modelFields = append(modelFields, "\tTags map[string]interface{} `tfschema:\"tags\"`")
+		schemaFields = append(schemaFields, "\t\t\"location\": location.Schema(),\n")
+		schemaFields = append(schemaFields, "\t\t\"tags\": tags.Schema(),\n")
+		readFields = append(readFields, "\t\t\t\tLocation: location.NormalizeNilable(resp.Location),")
+		readFields = append(readFields, "\t\t\t\tTags: tags.Flatten(resp.Tags),")
+		payload = fmt.Sprintf(`			parameters := %s.%s{
+				Location: utils.String(location.Normalize(model.Location)),
+				Tags:     tags.Expand(model.Tags),
+			}
+`, s.sdkAlias(), s.idName)
+		updateTags = `
+			if metadata.ResourceData.HasChange("tags") {
+				existing.Tags = tags.Expand(model.Tags)
+			}
+`
+	}
+
+	code := fmt.Sprintf(`package %[1]s
+
+import (
+%[2]s
+)
+
+type %[3]sModel struct {
+%[4]s
+}
+
+type %[3]sResource struct{}
+
+var _ sdk.ResourceWithUpdate = %[3]sResource{}
+
+func (r %[3]sResource) ResourceType() string {
+	return %[5]q
+}
+
+func (r %[3]sResource) ModelObject() interface{} {
+	return %[3]sModel{}
+}
+
+func (r %[3]sResource) IDValidationFunc() pluginsdk.SchemaValidateFunc {
+	return validate.%[6]sID
+}
+
+func (r %[3]sResource) Arguments() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+%[7]s
+		// TODO: add any other arguments supported by this Resource
+	}
+}
+
+func (r %[3]sResource) Attributes() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{}
+}
+
+func (r %[3]sResource) Create() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: 30 * time.Minute,
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			client := metadata.Client.%[8]s.%[9]s
+%[15]s
+			var model %[3]sModel
+			if err := metadata.Decode(&model); err != nil {
+				return fmt.Errorf("decoding: %%+v", err)
+			}
+
+			id := parse.New%[6]sID(%[10]s)
+			existing, err := client.Get(ctx, %[11]s)
+			if err != nil {
+				if !utils.ResponseWasNotFound(existing.Response) {
+					return fmt.Errorf("checking for the presence of an existing %%s: %%+v", id, err)
+				}
+			}
+			if !utils.ResponseWasNotFound(existing.Response) {
+				return metadata.ResourceRequiresImport(r.ResourceType(), id)
+			}
+
+			// TODO: map the arguments from the Model into the payload
+%[12]s
+			// TODO: wait for the creation to complete if this is a Long Running Operation
+			if _, err := client.CreateOrUpdate(ctx, %[11]s, parameters); err != nil {
+				return fmt.Errorf("creating %%s: %%+v", id, err)
+			}
+
+			metadata.SetID(id)
+			return nil
+		},
+	}
+}
+
+func (r %[3]sResource) Read() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: 5 * time.Minute,
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			client := metadata.Client.%[8]s.%[9]s
+			id, err := parse.%[6]sID(metadata.ResourceData.Id())
+			if err != nil {
+				return err
+			}
+
+			resp, err := client.Get(ctx, %[11]s)
+			if err != nil {
+				if utils.ResponseWasNotFound(resp.Response) {
+					return metadata.MarkAsGone(id)
+				}
+				return fmt.Errorf("retrieving %%s: %%+v", id, err)
+			}
+
+			model := %[3]sModel{
+%[13]s
+			}
+
+			// TODO: map the properties from the API Response into the Model
+
+			return metadata.Encode(&model)
+		},
+	}
+}
+
+func (r %[3]sResource) Update() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: 30 * time.Minute,
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			client := metadata.Client.%[8]s.%[9]s
+			id, err := parse.%[6]sID(metadata.ResourceData.Id())
+			if err != nil {
+				return err
+			}
+
+			var model %[3]sModel
+			if err := metadata.Decode(&model); err != nil {
+				return fmt.Errorf("decoding: %%+v", err)
+			}
+
+			existing, err := client.Get(ctx, %[11]s)
+			if err != nil {
+				return fmt.Errorf("retrieving %%s: %%+v", id, err)
+			}
+%[14]s
+			// TODO: map any other arguments which have changed into the payload
+
+			// TODO: wait for the update to complete if this is a Long Running Operation
+			if _, err := client.CreateOrUpdate(ctx, %[11]s, existing); err != nil {
+				return fmt.Errorf("updating %%s: %%+v", id, err)
+			}
+
+			return nil
+		},
+	}
+}
+
+func (r %[3]sResource) Delete() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: 30 * time.Minute,
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			client := metadata.Client.%[8]s.%[9]s
+			id, err := parse.%[6]sID(metadata.ResourceData.Id())
+			if err != nil {
+				return err
+			}
+
+			// TODO: wait for the deletion to complete if this is a Long Running Operation
+			if _, err := client.Delete(ctx, %[11]s); err != nil {
+				return fmt.Errorf("deleting %%s: %%+v", id, err)
+			}
+
+			return nil
+		},
+	}
+}
+`, s.servicePackage, "\t"+strings.Join(imports, "\n\t"), s.typeName, strings.Join(modelFields, "\n"), s.resourceName, s.idName,
+		strings.Join(schemaFields, "\n"), s.serviceClientName, s.clientName, s.constructorArguments(), s.idArguments(), payload,
+		strings.Join(readFields, "\n"), updateTags, subscriptionId)
+	return code
+}
+
+func (s resourceScaffold) testCode() string {
+	requiredArguments := make([][]string, 0)
+	importArguments := make([][]string, 0)
+	for _, v := range s.arguments() {
+		value := `"TODO"`
+		switch v.schemaName {
+		case "name":
+			value = fmt.Sprintf(`"acctest-%s-%%[2]d"`, strings.ReplaceAll(s.fileName, "_", ""))
+		case "resource_group_name":
+			value = "azurerm_resource_group.test.name"
+		}
+		requiredArguments = append(requiredArguments, []string{v.schemaName, value})
+		importArguments = append(importArguments, []string{v.schemaName, fmt.Sprintf("%s.test.%s", s.resourceName, v.schemaName)})
+	}
+	if s.isTopLevel {
+		requiredArguments = append(requiredArguments, []string{"location", "azurerm_resource_group.test.location"})
+		importArguments = append(importArguments, []string{"location", fmt.Sprintf("%s.test.location", s.resourceName)})
+	}
+
+	completeArguments := hclArguments(requiredArguments)
+	if s.isTopLevel {
+		completeArguments += `
+
+  tags = {
+    ENV = "Test"
+  }`
+	} else {
+		completeArguments += `
+
+  # TODO: specify the optional arguments for this Resource`
+	}
+
+	template := `provider "azurerm" {
+  features {}
+}
+`
+	templateArguments := ""
+	if s.hasResourceGroup {
+		template += `
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%[1]d"
+  location = "%[2]s"
+}
+`
+		templateArguments = ", data.RandomInteger, data.Locations.Primary"
+	}
+	if !s.isTopLevel {
+		template += `
+# TODO: add the parent resources for this Resource
+`
+	}
+
+	code := fmt.Sprintf(`package %[1]s_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance/check"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/services/%[1]s/parse"
+	"github.com/hashicorp/terraform-provider-azurerm/utils"
+)
+
+type %[2]sResource struct{}
+
+func TestAcc%[2]s_basic(t *testing.T) {
+	data := acceptance.BuildTestData(t, %[3]q, "test")
+	r := %[2]sResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.basic(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep(),
+	})
+}
+
+func TestAcc%[2]s_requiresImport(t *testing.T) {
+	data := acceptance.BuildTestData(t, %[3]q, "test")
+	r := %[2]sResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.basic(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.RequiresImportErrorStep(r.requiresImport),
+	})
+}
+
+func TestAcc%[2]s_complete(t *testing.T) {
+	data := acceptance.BuildTestData(t, %[3]q, "test")
+	r := %[2]sResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.complete(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep(),
+	})
+}
+
+func TestAcc%[2]s_update(t *testing.T) {
+	data := acceptance.BuildTestData(t, %[3]q, "test")
+	r := %[2]sResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.basic(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep(),
+		{
+			Config: r.complete(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep(),
+		{
+			Config: r.basic(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep(),
+	})
+}
+
+func (r %[2]sResource) Exists(ctx context.Context, clients *clients.Client, state *terraform.InstanceState) (*bool, error) {
+	id, err := parse.%[4]sID(state.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := clients.%[5]s.%[6]s.Get(ctx, %[7]s)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp.Response) {
+			return utils.Bool(false), nil
+		}
+		return nil, fmt.Errorf("retrieving %%s: %%+v", id, err)
+	}
+
+	return utils.Bool(true), nil
+}
+
+func (r %[2]sResource) basic(data acceptance.TestData) string {
+	return fmt.Sprintf(`+"`"+`
+%%[1]s
+
+resource %[3]q "test" {
+%[8]s
+}
+`+"`"+`, r.template(data), data.RandomInteger)
+}
+
+func (r %[2]sResource) requiresImport(data acceptance.TestData) string {
+	return fmt.Sprintf(`+"`"+`
+%%s
+
+resource %[3]q "import" {
+%[9]s
+}
+`+"`"+`, r.basic(data))
+}
+
+func (r %[2]sResource) complete(data acceptance.TestData) string {
+	return fmt.Sprintf(`+"`"+`
+%%[1]s
+
+resource %[3]q "test" {
+%[10]s
+}
+`+"`"+`, r.template(data), data.RandomInteger)
+}
+
+func (r %[2]sResource) template(data acceptance.TestData) string {
+	return fmt.Sprintf(`+"`"+`
+%[11]s`+"`"+`%[12]s)
+}
+`, s.servicePackage, s.typeName, s.resourceName, s.idName, s.serviceClientName, s.clientName, s.idArguments(),
+		hclArguments(requiredArguments), hclArguments(importArguments), completeArguments, template, templateArguments)
+	return code
+}
+
+func (s resourceScaffold) documentation() string {
+	category := "TODO"
+	if len(s.websiteCategories) == 1 {
+		category = s.websiteCategories[0]
+	} else if len(s.websiteCategories) > 1 {
+		category = fmt.Sprintf("TODO - pick from: %s", strings.Join(s.websiteCategories, "|"))
+	}
+
+	exampleArguments := make([][]string, 0)
+	requiredArguments := make([]string, 0)
+	optionalArguments := make([]string, 0)
+	for _, v := range s.arguments() {
+		switch v.schemaName {
+		case "name":
+			exampleArguments = append(exampleArguments, []string{"name", fmt.Sprintf(`"example-%s"`, strings.ReplaceAll(s.fileName, "_", ""))})
+			requiredArguments = append(requiredArguments, fmt.Sprintf("* `name` - (Required) The name which should be used for this %[1]s. Changing this forces a new %[1]s to be created.", s.brandName))
+		case "resource_group_name":
+			exampleArguments = append(exampleArguments, []string{"resource_group_name", "azurerm_resource_group.example.name"})
+			requiredArguments = append(requiredArguments, fmt.Sprintf("* `resource_group_name` - (Required) The name of the Resource Group where the %[1]s should exist. Changing this forces a new %[1]s to be created.", s.brandName))
+		default:
+			exampleArguments = append(exampleArguments, []string{v.schemaName, `"TODO"`})
+			requiredArguments = append(requiredArguments, fmt.Sprintf("* `%[1]s` - (Required) TODO. Changing this forces a new %[2]s to be created.", v.schemaName, s.brandName))
+		}
+	}
+	if s.isTopLevel {
+		exampleArguments = append(exampleArguments, []string{"location", "azurerm_resource_group.example.location"})
+		requiredArguments = append(requiredArguments, fmt.Sprintf("* `location` - (Required) The Azure Region where the %[1]s should exist. Changing this forces a new %[1]s to be created.", s.brandName))
+		optionalArguments = append(optionalArguments, fmt.Sprintf("* `tags` - (Optional) A mapping of tags which should be assigned to the %s.", s.brandName))
+	}
+
+	arguments := strings.Join(requiredArguments, "\n\n")
+	if len(optionalArguments) > 0 {
+		arguments += "\n\n---\n\n" + strings.Join(optionalArguments, "\n\n")
+	}
+
+	example := ""
+	if s.hasResourceGroup {
+		example = `resource "azurerm_resource_group" "example" {
+  name     = "example-resources"
+  location = "West Europe"
+}
+
+`
+	}
+	example += fmt.Sprintf(`resource %q "example" {
+%s
+}`, s.resourceName, hclArguments(exampleArguments))
+
+	template := fmt.Sprintf(`---
+subcategory: "%[1]s"
+layout: "azurerm"
+page_title: "Azure Resource Manager: %[2]s"
+description: |-
+  Manages a %[3]s.
+---
+
+# %[2]s
+
+Manages a %[3]s.
+
+## Example Usage
+
+[][][]hcl
+%[4]s
+[][][]
+
+## Arguments Reference
+
+The following arguments are supported:
+
+%[5]s
+
+## Attributes Reference
+
+In addition to the Arguments listed above - the following Attributes are exported:
+
+* [following]id[following] - The ID of the %[3]s.
+
+## Timeouts
+
+The [following]timeouts[following] block allows you to specify [timeouts](https://www.terraform.io/docs/configuration/resources.html#timeouts) for certain actions:
+
+* [following]create[following] - (Defaults to 30 minutes) Used when creating the %[3]s.
+* [following]read[following] - (Defaults to 5 minutes) Used when retrieving the %[3]s.
+* [following]update[following] - (Defaults to 30 minutes) Used when updating the %[3]s.
+* [following]delete[following] - (Defaults to 30 minutes) Used when deleting the %[3]s.
+
+## Import
+
+%[3]ss can be imported using the [following]resource id[following], e.g.
+
+[][][]shell
+terraform import %[2]s.example %[6]s
+[][][]
+`, category, s.resourceName, s.brandName, example, arguments, s.resourceId)
+	template = strings.ReplaceAll(template, "[][][]", "```")
+	return strings.ReplaceAll(template, "[following]", "`")
+}
+
+// hclArguments returns the HCL for the specified key/value pairs, with the values aligned as `terrafmt` expects
+func hclArguments(input [][]string) string {
+	width := 0
+	for _, v := range input {
+		if len(v[0]) > width {
+			width = len(v[0])
+		}
+	}
+
+	lines := make([]string, 0)
+	for _, v := range input {
+		lines = append(lines, fmt.Sprintf("  %-*s = %s", width, v[0], v[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// findServiceClientName returns the name of the field within `clients.Client` for the Service Client
+func findServiceClientName(rootDirectory, servicePackage string) (string, error) {
+	filePath := filepath.Join(rootDirectory, "internal", "clients", "client.go")
+	contents, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("reading %q: %+v", filePath, err)
+	}
+
+	importRegex := regexp.MustCompile(fmt.Sprintf(`(?m)^\s*(\w+)\s+"github.com/hashicorp/terraform-provider-azurerm/internal/services/%s/client"`, regexp.QuoteMeta(servicePackage)))
+	importMatch := importRegex.FindStringSubmatch(string(contents))
+	if importMatch == nil {
+		return "", fmt.Errorf("the Service Client for %q is not imported within %q", servicePackage, filePath)
+	}
+
+	fieldRegex := regexp.MustCompile(fmt.Sprintf(`(?m)^\s*(\w+)\s+\*%s\.Client\s*$`, regexp.QuoteMeta(importMatch[1])))
+	fieldMatch := fieldRegex.FindStringSubmatch(string(contents))
+	if fieldMatch == nil {
+		return "", fmt.Errorf("the Service Client for %q is not defined within %q", servicePackage, filePath)
+	}
+
+	return fieldMatch[1], nil
+}
+
+var websiteCategoriesRegex = regexp.MustCompile(`WebsiteCategories\(\) \[\]string \{\s*return \[\]string\{([^}]*)\}`)
+
+// findWebsiteCategories returns the Website Categories defined within the Service Registration
+func findWebsiteCategories(registration string) []string {
+	output := make([]string, 0)
+
+	match := websiteCategoriesRegex.FindStringSubmatch(registration)
+	if match == nil {
+		return output
+	}
+
+	for _, v := range regexp.MustCompile(`"([^"]+)"`).FindAllStringSubmatch(match[1], -1) {
+		output = append(output, v[1])
+	}
+	return output
+}
+
+// registerResource adds the Typed Resource to the Service Registration, returning the updated Service Registration
+// and whether this is the first Typed Resource within the Service (in which case the Service needs to be registered)
+func registerResource(registration, typeName string) (string, bool, error) {
+	entry := fmt.Sprintf("\t\t%sResource{},\n", typeName)
+
+	const emptyResources = "Resources() []sdk.Resource {\n\treturn []sdk.Resource{}\n}"
+	if strings.Contains(registration, emptyResources) {
+		updated := strings.Replace(registration, emptyResources, fmt.Sprintf("Resources() []sdk.Resource {\n\treturn []sdk.Resource{\n%s\t}\n}", entry), 1)
+		output, err := formatCode(updated)
+		return output, false, err
+	}
+
+	const existingResources = "Resources() []sdk.Resource {\n\treturn []sdk.Resource{\n"
+	if start := strings.Index(registration, existingResources); start != -1 {
+		end := strings.Index(registration[start:], "\n\t}\n}")
+		if end == -1 {
+			return "", false, fmt.Errorf("the end of the Typed Resources couldn't be found")
+		}
+		insertAt := start + end + 1
+
+		updated := registration[:insertAt] + entry + registration[insertAt:]
+		output, err := formatCode(updated)
+		return output, false, err
+	}
+
+	// this Service doesn't contain any Typed Data Sources/Resources at this time, so these need to be added
+	updated := registration
+	if !strings.Contains(updated, `"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"`) {
+		updated = strings.Replace(updated, "import (\n", "import (\n\t\"github.com/hashicorp/terraform-provider-azurerm/internal/sdk\"\n", 1)
+	}
+	if !strings.Contains(updated, "DataSources() []sdk.DataSource {") {
+		updated = strings.TrimSuffix(updated, "\n") + `
+
+// DataSources returns a list of Data Sources supported by this Service
+func (r Registration) DataSources() []sdk.DataSource {
+	return []sdk.DataSource{}
+}
+`
+	}
+	updated = strings.TrimSuffix(updated, "\n") + fmt.Sprintf(`
+
+// Resources returns a list of Resources supported by this Service
+func (r Registration) Resources() []sdk.Resource {
+	return []sdk.Resource{
+%s	}
+}
+`, entry)
+
+	output, err := formatCode(updated)
+	return output, true, err
+}
+
+// registerTypedService adds the Service to the list of Typed Services supported by the Provider
+func registerTypedService(services, servicePackage string) (string, error) {
+	const typedServices = "SupportedTypedServices() []sdk.TypedServiceRegistration {\n\treturn []sdk.TypedServiceRegistration{\n"
+	start := strings.Index(services, typedServices)
+	if start == -1 {
+		return "", fmt.Errorf("the Typed Services couldn't be found")
+	}
+	start += len(typedServices)
+
+	length := strings.Index(services[start:], "\t}\n")
+	if length == -1 {
+		return "", fmt.Errorf("the end of the Typed Services couldn't be found")
+	}
+
+	entries := strings.Split(strings.TrimSuffix(services[start:start+length], "\n"), "\n")
+	entry := fmt.Sprintf("\t\t%s.Registration{},", servicePackage)
+	for _, v := range entries {
+		if v == entry {
+			return services, nil
+		}
+	}
+	entries = append(entries, entry)
+	sort.Strings(entries)
+
+	return formatCode(services[:start] + strings.Join(entries, "\n") + "\n" + services[start+length:])
+}
+
+var resourceIdsGenerateRegex = regexp.MustCompile(`(?m)^//go:generate .*generator-resource-id/main.go.*\s-name=(\S+)`)
+
+// generateResourceId adds the Resource ID to the `resourceids.go` file within the Service Package (unless it already
+// exists) and then runs the Resource ID generator to output the Parser & Validator
+func generateResourceId(servicePackagePath, idName, resourceId string) error {
+	filePath := filepath.Join(servicePackagePath, "resourceids.go")
+	contents, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("reading %q: %+v", filePath, err)
+		}
+
+		contents = []byte(fmt.Sprintf("package %s\n\n", filepath.Base(servicePackagePath)))
+	}
+
+	for _, match := range resourceIdsGenerateRegex.FindAllStringSubmatch(string(contents), -1) {
+		if match[1] == idName {
+			log.Printf("[DEBUG] The Resource ID %q already exists - reusing this..", idName)
+			return nil
+		}
+	}
+
+	arguments := []string{"run", "../../tools/generator-resource-id/main.go", "-path=./", fmt.Sprintf("-name=%s", idName), fmt.Sprintf("-id=%s", resourceId)}
+	directive := fmt.Sprintf("//go:generate go %s\n", strings.Join(arguments, " "))
+	updated := strings.TrimSuffix(string(contents), "\n") + "\n" + directive
+	if err := ioutil.WriteFile(filePath, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("writing %q: %+v", filePath, err)
+	}
+
+	cmd := exec.Command("go", arguments...)
+	cmd.Dir = servicePackagePath
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running the Resource ID generator: %+v", err)
+	}
+
+	return nil
+}
+
+func formatCode(input string) (string, error) {
+	output, err := format.Source([]byte(input))
+	if err != nil {
+		return "", fmt.Errorf("formatting: %+v", err)
+	}
+
+	return string(output), nil
+}
+
+func writeToFile(filePath, contents string) error {
+	if strings.HasSuffix(filePath, ".go") {
+		formatted, err := formatCode(contents)
+		if err != nil {
+			return fmt.Errorf("formatting %q: %+v", filePath, err)
+		}
+		contents = formatted
+	}
+
+	if err := ioutil.WriteFile(filePath, []byte(contents), 0644); err != nil {
+		return fmt.Errorf("writing %q: %+v", filePath, err)
+	}
+
+	log.Printf("[DEBUG] Wrote %q", filePath)
+	return nil
+}
+
+func sortedKeys(input map[string]string) []string {
+	keys := make([]string, 0)
+	for k := range input {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
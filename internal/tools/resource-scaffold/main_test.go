@@ -0,0 +1,267 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewResourceScaffold(t *testing.T) {
+	testData := []struct {
+		Name             string
+		ResourceName     string
+		ResourceId       string
+		ExpectedTypeName string
+		ExpectedIdName   string
+		ExpectedFields   []string
+		ExpectedTopLevel bool
+	}{
+		{
+			Name:             "Top Level",
+			ResourceName:     "azurerm_maps_creator",
+			ResourceId:       "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/group1/providers/Microsoft.Maps/creators/creator1",
+			ExpectedTypeName: "MapsCreator",
+			ExpectedIdName:   "Creator",
+			ExpectedFields:   []string{"SubscriptionId", "ResourceGroup", "Name"},
+			ExpectedTopLevel: true,
+		},
+		{
+			Name:             "Nested",
+			ResourceName:     "azurerm_eventhub_consumer_group",
+			ResourceId:       "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/group1/providers/Microsoft.EventHub/namespaces/namespace1/eventhubs/eventhub1/consumerGroups/group1",
+			ExpectedTypeName: "EventhubConsumerGroup",
+			ExpectedIdName:   "ConsumerGroup",
+			ExpectedFields:   []string{"SubscriptionId", "ResourceGroup", "NamespaceName", "EventhubName", "Name"},
+			ExpectedTopLevel: false,
+		},
+		{
+			Name:             "Pluralised Segments",
+			ResourceName:     "azurerm_firewall_policy",
+			ResourceId:       "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/group1/providers/Microsoft.Network/firewallPolicies/policy1",
+			ExpectedTypeName: "FirewallPolicy",
+			ExpectedIdName:   "FirewallPolicy",
+			ExpectedFields:   []string{"SubscriptionId", "ResourceGroup", "Name"},
+			ExpectedTopLevel: true,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		actual, err := newResourceScaffold(v.ResourceName, "Example", v.ResourceId, "example", "github.com/Azure/azure-sdk-for-go/services/example/mgmt/2021-01-01/example", "")
+		if err != nil {
+			t.Fatalf("building scaffold: %+v", err)
+		}
+
+		if actual.typeName != v.ExpectedTypeName {
+			t.Fatalf("expected the Type Name to be %q but got %q", v.ExpectedTypeName, actual.typeName)
+		}
+		if actual.idName != v.ExpectedIdName {
+			t.Fatalf("expected the ID Name to be %q but got %q", v.ExpectedIdName, actual.idName)
+		}
+		if actual.isTopLevel != v.ExpectedTopLevel {
+			t.Fatalf("expected top level to be %t but got %t", v.ExpectedTopLevel, actual.isTopLevel)
+		}
+
+		fields := make([]string, 0)
+		for _, segment := range actual.segments {
+			fields = append(fields, segment.fieldName)
+		}
+		if !reflect.DeepEqual(fields, v.ExpectedFields) {
+			t.Fatalf("expected the fields %+v but got %+v", v.ExpectedFields, fields)
+		}
+
+		// the generated code needs to be valid Go, which is checked when formatting
+		actual.serviceClientName = "Example"
+		if _, err := formatCode(actual.resourceCode()); err != nil {
+			t.Fatalf("formatting the Resource: %+v", err)
+		}
+		if _, err := formatCode(actual.testCode()); err != nil {
+			t.Fatalf("formatting the Tests: %+v", err)
+		}
+	}
+}
+
+func TestRegisterResource(t *testing.T) {
+	testData := []struct {
+		Name                      string
+		Input                     string
+		Expected                  string
+		ExpectedTypedRegistration bool
+	}{
+		{
+			Name: "Existing Typed Resources",
+			Input: `package example
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+)
+
+type Registration struct{}
+
+func (r Registration) DataSources() []sdk.DataSource {
+	return []sdk.DataSource{}
+}
+
+func (r Registration) Resources() []sdk.Resource {
+	return []sdk.Resource{
+		ExistingResource{},
+	}
+}
+`,
+			Expected: `package example
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+)
+
+type Registration struct{}
+
+func (r Registration) DataSources() []sdk.DataSource {
+	return []sdk.DataSource{}
+}
+
+func (r Registration) Resources() []sdk.Resource {
+	return []sdk.Resource{
+		ExistingResource{},
+		ExampleResource{},
+	}
+}
+`,
+		},
+		{
+			Name: "Empty Typed Resources",
+			Input: `package example
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+)
+
+type Registration struct{}
+
+func (r Registration) Resources() []sdk.Resource {
+	return []sdk.Resource{}
+}
+`,
+			Expected: `package example
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+)
+
+type Registration struct{}
+
+func (r Registration) Resources() []sdk.Resource {
+	return []sdk.Resource{
+		ExampleResource{},
+	}
+}
+`,
+		},
+		{
+			Name: "No Typed Resources",
+			Input: `package example
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+type Registration struct{}
+
+func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{}
+}
+`,
+			Expected: `package example
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+type Registration struct{}
+
+func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{}
+}
+
+// DataSources returns a list of Data Sources supported by this Service
+func (r Registration) DataSources() []sdk.DataSource {
+	return []sdk.DataSource{}
+}
+
+// Resources returns a list of Resources supported by this Service
+func (r Registration) Resources() []sdk.Resource {
+	return []sdk.Resource{
+		ExampleResource{},
+	}
+}
+`,
+			ExpectedTypedRegistration: true,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		actual, typedRegistration, err := registerResource(v.Input, "Example")
+		if err != nil {
+			t.Fatalf("registering: %+v", err)
+		}
+
+		if actual != v.Expected {
+			t.Fatalf("expected:\n%s\n\nbut got:\n%s", v.Expected, actual)
+		}
+		if typedRegistration != v.ExpectedTypedRegistration {
+			t.Fatalf("expected the Typed Registration to be %t but got %t", v.ExpectedTypedRegistration, typedRegistration)
+		}
+	}
+}
+
+func TestRegisterTypedService(t *testing.T) {
+	input := `package provider
+
+func SupportedTypedServices() []sdk.TypedServiceRegistration {
+	return []sdk.TypedServiceRegistration{
+		batch.Registration{},
+		web.Registration{},
+	}
+}
+`
+	actual, err := registerTypedService(input, "maps")
+	if err != nil {
+		t.Fatalf("registering: %+v", err)
+	}
+
+	expected := strings.Replace(input, "\t\tweb.Registration{},", "\t\tmaps.Registration{},\n\t\tweb.Registration{},", 1)
+	if actual != expected {
+		t.Fatalf("expected:\n%s\n\nbut got:\n%s", expected, actual)
+	}
+
+	// registering an existing Service is a no-op
+	again, err := registerTypedService(actual, "maps")
+	if err != nil {
+		t.Fatalf("registering again: %+v", err)
+	}
+	if again != actual {
+		t.Fatalf("expected registering an existing Service to be a no-op but got:\n%s", again)
+	}
+}
+
+func TestFindWebsiteCategories(t *testing.T) {
+	input := `package example
+
+// WebsiteCategories returns a list of categories which can be used for the sidebar
+func (r Registration) WebsiteCategories() []string {
+	return []string{
+		"Container",
+		"Networking",
+	}
+}
+`
+	actual := findWebsiteCategories(input)
+	expected := []string{"Container", "Networking"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %+v but got %+v", expected, actual)
+	}
+}
@@ -0,0 +1,543 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/provider"
+)
+
+// NOTE: since we're using `go run` for these tools all of the code needs to live within the main.go
+
+func main() {
+	f := flag.NewFlagSet("website-drift", flag.ExitOnError)
+
+	resourceName := f.String("name", "", "(Optional) The name of a single Data Source/Resource which should be checked")
+	websitePath := f.String("website-path", "", "The relative path to the website folder")
+
+	_ = f.Parse(os.Args[1:])
+
+	quitWithError := func(message string) {
+		log.Print(message)
+		os.Exit(1)
+	}
+
+	if websitePath == nil || *websitePath == "" {
+		quitWithError("The Relative Website Path must be specified via `-website-path`")
+		return
+	}
+
+	issues, err := run(*websitePath, *resourceName)
+	if err != nil {
+		quitWithError(err.Error())
+		return
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+
+	if len(issues) > 0 {
+		quitWithError(fmt.Sprintf("Found %d differences between the documentation and the schema", len(issues)))
+		return
+	}
+}
+
+func run(websitePath, resourceName string) ([]string, error) {
+	azureProvider := provider.AzureProvider()
+
+	issues := make([]string, 0)
+	check := func(kind string, resources map[string]*schema.Resource) error {
+		names := make([]string, 0)
+		for name := range resources {
+			if resourceName != "" && name != resourceName {
+				continue
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			fileName := fmt.Sprintf("%s.html.markdown", strings.TrimPrefix(name, "azurerm_"))
+			filePath := filepath.Join(websitePath, "docs", kind, fileName)
+
+			contents, err := ioutil.ReadFile(filePath)
+			if err != nil {
+				if os.IsNotExist(err) {
+					issues = append(issues, fmt.Sprintf("%s: the documentation page %q doesn't exist", name, filePath))
+					continue
+				}
+
+				return fmt.Errorf("reading %q: %+v", filePath, err)
+			}
+
+			page := parseDocumentationPage(string(contents))
+			for _, issue := range compare(resources[name], page) {
+				issues = append(issues, fmt.Sprintf("%s: %s", name, issue))
+			}
+		}
+
+		return nil
+	}
+
+	if err := check("d", azureProvider.DataSourcesMap); err != nil {
+		return nil, fmt.Errorf("checking the Data Sources: %+v", err)
+	}
+
+	if err := check("r", azureProvider.ResourcesMap); err != nil {
+		return nil, fmt.Errorf("checking the Resources: %+v", err)
+	}
+
+	return issues, nil
+}
+
+type documentedField struct {
+	required bool
+	optional bool
+}
+
+type documentationPage struct {
+	// arguments are the fields documented in the Arguments Reference, keyed by the block name (an empty
+	// string for the top-level) and then the field name
+	arguments map[string]map[string]documentedField
+
+	// attributes are the fields documented in the Attributes Reference, keyed by the block name (an empty
+	// string for the top-level) and then the field name
+	attributes map[string]map[string]documentedField
+
+	// timeouts are the operations documented in the Timeouts section, with their default (when it can be parsed)
+	timeouts map[string]*time.Duration
+}
+
+var (
+	// matches "An `example` block supports the following:" and "The `example` block exports:"
+	blockRegex = regexp.MustCompile("(?i)^(?:an?|the)\\s+`([^`]+)`.*\\bblock\\b.*\\b(?:supports|exports)\\b")
+
+	// matches "* `name` - (Required) The name.."
+	fieldRegex = regexp.MustCompile("^\\*\\s+`([^`]+)`\\s*-\\s*(.*)$")
+
+	// matches "(Defaults to 30 minutes)", "(Defaults to 1 hour)" and "(Defaults to 1 hour and 30 minutes)"
+	timeoutRegex = regexp.MustCompile("(?i)\\(Defaults to (\\d+) (hours?|minutes?)(?: and (\\d+) minutes?)?\\)")
+)
+
+func parseDocumentationPage(input string) documentationPage {
+	page := documentationPage{
+		arguments:  map[string]map[string]documentedField{},
+		attributes: map[string]map[string]documentedField{},
+		timeouts:   map[string]*time.Duration{},
+	}
+
+	var section map[string]map[string]documentedField
+	inTimeouts := false
+	block := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "## ") {
+			heading := strings.ToLower(line)
+			section = nil
+			inTimeouts = false
+			block = ""
+
+			switch {
+			case strings.Contains(heading, "argument"):
+				section = page.arguments
+			case strings.Contains(heading, "attribute"):
+				section = page.attributes
+			case strings.Contains(heading, "timeout"):
+				inTimeouts = true
+			}
+			continue
+		}
+
+		if inTimeouts {
+			match := fieldRegex.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+
+			page.timeouts[match[1]] = parseTimeoutDefault(match[2])
+			continue
+		}
+
+		if section == nil {
+			continue
+		}
+
+		if match := blockRegex.FindStringSubmatch(line); match != nil {
+			block = match[1]
+			continue
+		}
+
+		match := fieldRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		if _, ok := section[block]; !ok {
+			section[block] = map[string]documentedField{}
+		}
+		section[block][match[1]] = documentedField{
+			required: strings.HasPrefix(match[2], "(Required"),
+			optional: strings.HasPrefix(match[2], "(Optional"),
+		}
+	}
+
+	return page
+}
+
+func parseTimeoutDefault(input string) *time.Duration {
+	match := timeoutRegex.FindStringSubmatch(input)
+	if match == nil {
+		return nil
+	}
+
+	value, _ := strconv.Atoi(match[1])
+	duration := time.Duration(value) * time.Minute
+	if strings.HasPrefix(strings.ToLower(match[2]), "hour") {
+		duration = time.Duration(value) * time.Hour
+	}
+
+	if match[3] != "" {
+		minutes, _ := strconv.Atoi(match[3])
+		duration += time.Duration(minutes) * time.Minute
+	}
+
+	return &duration
+}
+
+// schemaBlocks are the fields within the Schema keyed by the block name (an empty string for the top-level) and
+// then the field name - since the documentation refers to nested blocks by name the same block name can be used
+// in multiple places, in which case all of the definitions are retained
+type schemaBlocks map[string]map[string][]*schema.Schema
+
+func flattenSchema(block string, input map[string]*schema.Schema, deprecated bool, output schemaBlocks) {
+	if _, ok := output[block]; !ok {
+		output[block] = map[string][]*schema.Schema{}
+	}
+
+	for name, field := range input {
+		// fields within a deprecated block are implicitly deprecated
+		if deprecated && field.Deprecated == "" {
+			copied := *field
+			copied.Deprecated = fmt.Sprintf("the block `%s` is deprecated", block)
+			field = &copied
+		}
+
+		output[block][name] = append(output[block][name], field)
+
+		if elem, ok := field.Elem.(*schema.Resource); ok {
+			flattenSchema(name, elem.Schema, field.Deprecated != "", output)
+		}
+	}
+}
+
+func compare(resource *schema.Resource, page documentationPage) []string {
+	blocks := schemaBlocks{}
+	flattenSchema("", resource.Schema, false, blocks)
+
+	issues := make([]string, 0)
+	issues = append(issues, compareArguments(blocks, page)...)
+	issues = append(issues, compareAttributes(blocks, page)...)
+	issues = append(issues, compareTimeouts(resource.Timeouts, page)...)
+	return issues
+}
+
+func compareArguments(blocks schemaBlocks, page documentationPage) []string {
+	issues := make([]string, 0)
+
+	for _, block := range sortedKeys(blocks) {
+		documented, blockDocumented := page.arguments[block]
+		if !blockDocumented {
+			_, blockDocumented = page.attributes[block]
+		}
+		if block == "" {
+			blockDocumented = true
+		}
+
+		for _, name := range sortedKeys(blocks[block]) {
+			definitions := blocks[block][name]
+			if !isArgument(definitions) {
+				continue
+			}
+
+			field, ok := documented[name]
+			if !ok {
+				if isDeprecated(definitions) || ignoreField(block, name) {
+					continue
+				}
+
+				// the block itself being undocumented is reported once, rather than for each field
+				if !blockDocumented {
+					issues = append(issues, fmt.Sprintf("the block `%s` is not documented", block))
+					break
+				}
+
+				issues = append(issues, fmt.Sprintf("the argument %s is not documented", fieldName(block, name)))
+				continue
+			}
+
+			if field.optional && allOf(definitions, func(s *schema.Schema) bool { return s.Required }) {
+				issues = append(issues, fmt.Sprintf("the argument %s is documented as Optional but is Required", fieldName(block, name)))
+			}
+			if field.required && allOf(definitions, func(s *schema.Schema) bool { return s.Optional }) {
+				issues = append(issues, fmt.Sprintf("the argument %s is documented as Required but is Optional", fieldName(block, name)))
+			}
+		}
+	}
+
+	for _, block := range sortedKeys(page.arguments) {
+		fields, ok := blocks[block]
+		if !ok {
+			issues = append(issues, fmt.Sprintf("the block `%s` is documented as an argument but doesn't exist in the schema", block))
+			continue
+		}
+
+		for _, name := range sortedKeys(page.arguments[block]) {
+			definitions, ok := fields[name]
+			if !ok {
+				issues = append(issues, fmt.Sprintf("the argument %s is documented but doesn't exist in the schema", fieldName(block, name)))
+				continue
+			}
+
+			if !isArgument(definitions) {
+				issues = append(issues, fmt.Sprintf("the argument %s is documented but is Computed", fieldName(block, name)))
+			}
+		}
+	}
+
+	return issues
+}
+
+func compareAttributes(blocks schemaBlocks, page documentationPage) []string {
+	issues := make([]string, 0)
+
+	for _, block := range sortedKeys(blocks) {
+		_, inArguments := page.arguments[block]
+		_, inAttributes := page.attributes[block]
+		if block != "" && !inArguments && !inAttributes {
+			// blocks containing arguments which aren't documented have already been reported
+			if !containsArguments(blocks[block]) && !isDeprecated(flatten(blocks[block])) {
+				issues = append(issues, fmt.Sprintf("the block `%s` is not documented", block))
+			}
+			continue
+		}
+
+		for _, name := range sortedKeys(blocks[block]) {
+			definitions := blocks[block][name]
+			if isArgument(definitions) || isDeprecated(definitions) || ignoreField(block, name) {
+				continue
+			}
+
+			if _, ok := page.attributes[block][name]; ok {
+				continue
+			}
+
+			// nested Computed fields are sometimes documented alongside the arguments for that block
+			if _, ok := page.arguments[block][name]; ok && block != "" {
+				continue
+			}
+
+			issues = append(issues, fmt.Sprintf("the attribute %s is not documented", fieldName(block, name)))
+		}
+	}
+
+	for _, block := range sortedKeys(page.attributes) {
+		fields, ok := blocks[block]
+		if !ok {
+			issues = append(issues, fmt.Sprintf("the block `%s` is documented as an attribute but doesn't exist in the schema", block))
+			continue
+		}
+
+		for _, name := range sortedKeys(page.attributes[block]) {
+			if ignoreField(block, name) {
+				continue
+			}
+
+			if _, ok := fields[name]; !ok {
+				issues = append(issues, fmt.Sprintf("the attribute %s is documented but doesn't exist in the schema", fieldName(block, name)))
+			}
+		}
+	}
+
+	return issues
+}
+
+func compareTimeouts(input *schema.ResourceTimeout, page documentationPage) []string {
+	operations := []string{"create", "read", "update", "delete"}
+	expected := map[string]*time.Duration{}
+	if input != nil {
+		expected["create"] = input.Create
+		expected["read"] = input.Read
+		expected["update"] = input.Update
+		expected["delete"] = input.Delete
+	}
+
+	issues := make([]string, 0)
+	for _, operation := range operations {
+		expectedDefault := expected[operation]
+		documentedDefault, documented := page.timeouts[operation]
+
+		if expectedDefault == nil {
+			if documented {
+				issues = append(issues, fmt.Sprintf("the timeout `%s` is documented but isn't supported", operation))
+			}
+			continue
+		}
+
+		if !documented {
+			issues = append(issues, fmt.Sprintf("the timeout `%s` is not documented (defaults to %s)", operation, formatDuration(*expectedDefault)))
+			continue
+		}
+
+		if documentedDefault == nil {
+			issues = append(issues, fmt.Sprintf("the default for the timeout `%s` couldn't be parsed (defaults to %s)", operation, formatDuration(*expectedDefault)))
+			continue
+		}
+
+		if *documentedDefault != *expectedDefault {
+			issues = append(issues, fmt.Sprintf("the timeout `%s` is documented as defaulting to %s but defaults to %s", operation, formatDuration(*documentedDefault), formatDuration(*expectedDefault)))
+		}
+	}
+
+	for _, operation := range sortedKeys(page.timeouts) {
+		valid := false
+		for _, v := range operations {
+			valid = valid || v == operation
+		}
+
+		if !valid {
+			issues = append(issues, fmt.Sprintf("the timeout `%s` is documented but isn't a valid operation", operation))
+		}
+	}
+
+	return issues
+}
+
+// ignoreField returns whether the field should be ignored, since it's documented at a Provider level
+func ignoreField(block, name string) bool {
+	if block != "" {
+		return false
+	}
+
+	// `id` is implicitly defined for all Data Sources/Resources and `tags_all` is added when `default_tags` is supported
+	return name == "id" || name == "tags_all"
+}
+
+func isArgument(definitions []*schema.Schema) bool {
+	for _, definition := range definitions {
+		if definition.Required || definition.Optional {
+			return true
+		}
+	}
+
+	return false
+}
+
+func flatten(fields map[string][]*schema.Schema) []*schema.Schema {
+	output := make([]*schema.Schema, 0)
+	for _, definitions := range fields {
+		output = append(output, definitions...)
+	}
+
+	return output
+}
+
+func containsArguments(fields map[string][]*schema.Schema) bool {
+	for _, definitions := range fields {
+		if isArgument(definitions) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isDeprecated(definitions []*schema.Schema) bool {
+	return allOf(definitions, func(s *schema.Schema) bool {
+		return s.Deprecated != ""
+	})
+}
+
+func allOf(definitions []*schema.Schema, predicate func(s *schema.Schema) bool) bool {
+	for _, definition := range definitions {
+		if !predicate(definition) {
+			return false
+		}
+	}
+
+	return len(definitions) > 0
+}
+
+func fieldName(block, name string) string {
+	if block == "" {
+		return fmt.Sprintf("`%s`", name)
+	}
+
+	return fmt.Sprintf("`%s` (within the `%s` block)", name, block)
+}
+
+func formatDuration(input time.Duration) string {
+	hours := int(input.Hours())
+	minutes := int(input.Minutes()) - (hours * 60)
+
+	if hours > 0 && minutes > 0 {
+		return fmt.Sprintf("%d %s and %d minutes", hours, plural(hours, "hour"), minutes)
+	}
+	if hours > 0 {
+		return fmt.Sprintf("%d %s", hours, plural(hours, "hour"))
+	}
+
+	return fmt.Sprintf("%d %s", minutes, plural(minutes, "minute"))
+}
+
+func plural(value int, unit string) string {
+	if value == 1 {
+		return unit
+	}
+
+	return unit + "s"
+}
+
+func sortedKeys(input interface{}) []string {
+	keys := make([]string, 0)
+
+	switch v := input.(type) {
+	case schemaBlocks:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string][]*schema.Schema:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]map[string]documentedField:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]documentedField:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*time.Duration:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const testDocumentation = `---
+subcategory: "Base"
+layout: "azurerm"
+page_title: "Azure Resource Manager: azurerm_example"
+description: |-
+  Manages an Example.
+---
+
+# azurerm_example
+
+Manages an Example.
+
+## Arguments Reference
+
+The following arguments are supported:
+
+* ` + "`name`" + ` - (Required) The name of this Example. Changing this forces a new Example to be created.
+
+* ` + "`location`" + ` - (Optional) The Azure Region where the Example should exist.
+
+* ` + "`sku`" + ` - (Optional) A ` + "`sku`" + ` block as defined below.
+
+* ` + "`legacy`" + ` - (Optional) A field which no longer exists.
+
+---
+
+A ` + "`sku`" + ` block supports the following:
+
+* ` + "`name`" + ` - (Required) The name of the SKU.
+
+## Attributes Reference
+
+In addition to the Arguments listed above - the following Attributes are exported:
+
+* ` + "`id`" + ` - The ID of the Example.
+
+* ` + "`endpoint`" + ` - The Endpoint of the Example.
+
+## Timeouts
+
+The ` + "`timeouts`" + ` block allows you to specify [timeouts](https://www.terraform.io/docs/configuration/resources.html#timeouts) for certain actions:
+
+* ` + "`create`" + ` - (Defaults to 1 hour and 30 minutes) Used when creating the Example.
+* ` + "`read`" + ` - (Defaults to 5 minutes) Used when retrieving the Example.
+* ` + "`update`" + ` - (Defaults to 30 minutes) Used when updating the Example.
+* ` + "`delete`" + ` - (Defaults to 2 hours) Used when deleting the Example.
+`
+
+func TestParseDocumentationPage(t *testing.T) {
+	actual := parseDocumentationPage(testDocumentation)
+
+	expectedArguments := map[string]map[string]documentedField{
+		"": {
+			"name":     {required: true},
+			"location": {optional: true},
+			"sku":      {optional: true},
+			"legacy":   {optional: true},
+		},
+		"sku": {
+			"name": {required: true},
+		},
+	}
+	if !reflect.DeepEqual(actual.arguments, expectedArguments) {
+		t.Fatalf("expected the arguments %+v but got %+v", expectedArguments, actual.arguments)
+	}
+
+	expectedAttributes := map[string]map[string]documentedField{
+		"": {
+			"id":       {},
+			"endpoint": {},
+		},
+	}
+	if !reflect.DeepEqual(actual.attributes, expectedAttributes) {
+		t.Fatalf("expected the attributes %+v but got %+v", expectedAttributes, actual.attributes)
+	}
+
+	expectedTimeouts := map[string]*time.Duration{
+		"create": durationPointer(90 * time.Minute),
+		"read":   durationPointer(5 * time.Minute),
+		"update": durationPointer(30 * time.Minute),
+		"delete": durationPointer(2 * time.Hour),
+	}
+	if !reflect.DeepEqual(actual.timeouts, expectedTimeouts) {
+		t.Fatalf("expected the timeouts %+v but got %+v", expectedTimeouts, actual.timeouts)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	resource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"location": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"sku": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"capacity": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"network": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"subnet_id": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			"old_field": {
+				Type:       schema.TypeString,
+				Optional:   true,
+				Deprecated: "this has been superseded by `name`",
+			},
+			"endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"principal_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: durationPointer(90 * time.Minute),
+			Read:   durationPointer(5 * time.Minute),
+			Delete: durationPointer(90 * time.Minute),
+		},
+	}
+
+	actual := compare(resource, parseDocumentationPage(testDocumentation))
+	expected := []string{
+		"the argument `location` is documented as Optional but is Required",
+		"the argument `network` is not documented",
+		"the block `network` is not documented",
+		"the argument `capacity` (within the `sku` block) is not documented",
+		"the argument `legacy` is documented but doesn't exist in the schema",
+		"the attribute `principal_id` is not documented",
+		"the timeout `update` is documented but isn't supported",
+		"the timeout `delete` is documented as defaulting to 2 hours but defaults to 1 hour and 30 minutes",
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected:\n%v\n\nbut got:\n%v", expected, actual)
+	}
+}
+
+func TestParseTimeoutDefault(t *testing.T) {
+	testData := []struct {
+		Input    string
+		Expected *time.Duration
+	}{
+		{
+			Input:    "(Defaults to 30 minutes) Used when creating the Example.",
+			Expected: durationPointer(30 * time.Minute),
+		},
+		{
+			Input:    "(Defaults to 1 minute) Used when creating the Example.",
+			Expected: durationPointer(time.Minute),
+		},
+		{
+			Input:    "(Defaults to 1 hour) Used when creating the Example.",
+			Expected: durationPointer(time.Hour),
+		},
+		{
+			Input:    "(Defaults to 3 hours and 15 minutes) Used when creating the Example.",
+			Expected: durationPointer(3*time.Hour + 15*time.Minute),
+		},
+		{
+			Input:    "Used when creating the Example.",
+			Expected: nil,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Input)
+
+		actual := parseTimeoutDefault(v.Input)
+		if !reflect.DeepEqual(actual, v.Expected) {
+			t.Fatalf("expected %v but got %v", v.Expected, actual)
+		}
+	}
+}
+
+func durationPointer(input time.Duration) *time.Duration {
+	return &input
+}
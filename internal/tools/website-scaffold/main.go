@@ -103,6 +103,22 @@ func getContent(resourceName, brandName string, resourceId *string, isResource b
 					break
 				}
 			}
+
+			if v, ok := service.(sdk.TypedServiceRegistrationWithListDataSources); ok {
+				for _, ds := range v.ListDataSources() {
+					if ds.ResourceType() == resourceName {
+						wrapper := sdk.NewListDataSourceWrapper(ds)
+						dsWrapper, err := wrapper.DataSource()
+						if err != nil {
+							return nil, fmt.Errorf("wrapping List Data Source %q: %+v", ds.ResourceType(), err)
+						}
+
+						generator.resource = dsWrapper
+						generator.websiteCategories = service.WebsiteCategories()
+						break
+					}
+				}
+			}
 		}
 		for _, service := range provider.SupportedUntypedServices() {
 			for key, ds := range service.SupportedDataSources() {
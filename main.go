@@ -5,6 +5,7 @@ import (
 	"flag"
 	"log"
 
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/provider"
 )
@@ -18,17 +19,21 @@ func main() {
 	flag.BoolVar(&debugMode, "debuggable", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
+	providerServer := func() tfprotov5.ProviderServer {
+		return provider.NewProviderServer(provider.AzureProvider())
+	}
+
 	if debugMode {
 		err := plugin.Debug(context.Background(), "registry.terraform.io/hashicorp/azurerm",
 			&plugin.ServeOpts{
-				ProviderFunc: provider.AzureProvider,
+				GRPCProviderFunc: providerServer,
 			})
 		if err != nil {
 			log.Println(err.Error())
 		}
 	} else {
 		plugin.Serve(&plugin.ServeOpts{
-			ProviderFunc: provider.AzureProvider,
+			GRPCProviderFunc: providerServer,
 		})
 	}
 }